@@ -1,8 +1,12 @@
 package main
 
 import (
-	"regexp"
+	"fmt"
+	"strings"
 
+	"github.com/Betzalel75/ctop/config"
+	"github.com/Betzalel75/ctop/cwidgets/single"
+	"github.com/Betzalel75/ctop/widgets"
 	ui "github.com/gizak/termui"
 )
 
@@ -50,12 +54,186 @@ var ColorMap = map[string]ui.Attribute{
 	"status.danger":      ui.ColorRed,
 }
 
-func InvertColorMap() {
-	re := regexp.MustCompile(".*.fg")
-	for k := range ColorMap {
-		if re.FindAllString(k, 1) != nil {
-			ColorMap[k] = ui.ColorBlack
+// builtin themes, overriding colors of the dark theme
+var themes = map[string]map[string]ui.Attribute{
+	"dark": {},
+	"light": {
+		"fg":                 ui.ColorBlack,
+		"border.fg":          ui.ColorBlack,
+		"label.fg":           ui.ColorBlue,
+		"menu.text.fg":       ui.ColorBlack,
+		"menu.border.fg":     ui.ColorBlue,
+		"menu.label.fg":      ui.ColorBlue,
+		"header.fg":          ui.ColorWhite,
+		"header.bg":          ui.ColorBlue,
+		"gauge.bar.bg":       ui.ColorBlue,
+		"gauge.percent.fg":   ui.ColorBlack,
+		"linechart.line.fg":  ui.ColorBlue,
+		"mbarchart.bar.bg":   ui.ColorBlue,
+		"mbarchart.num.fg":   ui.ColorBlack,
+		"mbarchart.text.fg":  ui.ColorBlack,
+		"par.text.fg":        ui.ColorBlack,
+		"par.text.hi":        ui.ColorWhite,
+		"sparkline.line.fg":  ui.ColorBlue,
+		"sparkline.title.fg": ui.ColorBlack,
+		"status.warn":        ui.ColorMagenta,
+	},
+	"solarized": {
+		"border.fg":         ui.ColorBlue,
+		"label.fg":          ui.ColorYellow,
+		"menu.border.fg":    ui.ColorBlue,
+		"menu.label.fg":     ui.ColorYellow,
+		"header.fg":         ui.ColorBlack,
+		"header.bg":         ui.ColorCyan,
+		"gauge.bar.bg":      ui.ColorCyan,
+		"linechart.line.fg": ui.ColorCyan,
+		"mbarchart.bar.bg":  ui.ColorCyan,
+		"par.text.fg":       ui.ColorCyan,
+		"sparkline.line.fg": ui.ColorCyan,
+	},
+	"high-contrast": {
+		"fg":                 ui.ColorWhite | ui.AttrBold,
+		"border.fg":          ui.ColorWhite | ui.AttrBold,
+		"label.fg":           ui.ColorYellow | ui.AttrBold,
+		"menu.text.fg":       ui.ColorWhite | ui.AttrBold,
+		"menu.border.fg":     ui.ColorYellow | ui.AttrBold,
+		"menu.label.fg":      ui.ColorYellow | ui.AttrBold,
+		"header.fg":          ui.ColorBlack,
+		"header.bg":          ui.ColorYellow,
+		"gauge.bar.bg":       ui.ColorYellow,
+		"linechart.line.fg":  ui.ColorYellow | ui.AttrBold,
+		"mbarchart.bar.bg":   ui.ColorYellow,
+		"sparkline.line.fg":  ui.ColorYellow | ui.AttrBold,
+		"sparkline.title.fg": ui.ColorWhite | ui.AttrBold,
+		"status.ok":          ui.ColorGreen | ui.AttrBold,
+		"status.warn":        ui.ColorYellow | ui.AttrBold,
+		"status.danger":      ui.ColorRed | ui.AttrBold,
+	},
+	"monochrome": {
+		"label.fg":          ui.ColorWhite | ui.AttrBold,
+		"menu.border.fg":    ui.ColorWhite,
+		"menu.label.fg":     ui.ColorWhite | ui.AttrBold,
+		"gauge.bar.bg":      ui.ColorWhite,
+		"linechart.line.fg": ui.ColorWhite,
+		"mbarchart.bar.bg":  ui.ColorWhite,
+		"sparkline.line.fg": ui.ColorWhite,
+		"status.ok":         ui.ColorWhite,
+		"status.warn":       ui.ColorWhite | ui.AttrBold,
+		"status.danger":     ui.ColorWhite | ui.AttrBold | ui.AttrUnderline,
+	},
+}
+
+// builtin theme names, in menu order
+var themeNames = []string{"dark", "light", "solarized", "high-contrast", "monochrome"}
+
+var colorNames = map[string]ui.Attribute{
+	"default":   ui.ColorDefault,
+	"black":     ui.ColorBlack,
+	"red":       ui.ColorRed,
+	"green":     ui.ColorGreen,
+	"yellow":    ui.ColorYellow,
+	"blue":      ui.ColorBlue,
+	"magenta":   ui.ColorMagenta,
+	"cyan":      ui.ColorCyan,
+	"white":     ui.ColorWhite,
+	"bold":      ui.AttrBold,
+	"underline": ui.AttrUnderline,
+	"reverse":   ui.AttrReverse,
+}
+
+// parse a color, a color name optionally followed by attributes, e.g.
+// "yellow,bold"
+func parseColor(s string) (ui.Attribute, error) {
+	var a ui.Attribute
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '|' || r == '+' || r == ' ' })
+	if len(fields) == 0 {
+		return a, fmt.Errorf("empty color")
+	}
+	for _, f := range fields {
+		c, ok := colorNames[strings.ToLower(f)]
+		if !ok {
+			return a, fmt.Errorf("invalid color %s", f)
 		}
+		a |= c
+	}
+	return a, nil
+}
+
+// Theme returns the color map of a builtin theme, or of a theme defined
+// in the config file. Config themes override a builtin theme named in
+// their "base" key, the theme of the same name or the dark theme
+func Theme(name string) (map[string]ui.Attribute, error) {
+	custom, isCustom := config.GetTheme(name)
+	base := name
+	if b, ok := custom["base"]; ok {
+		base = b
+	}
+	overrides, ok := themes[base]
+	if !ok {
+		if !isCustom {
+			return nil, fmt.Errorf("unknown theme %s", name)
+		}
+		if _, ok := custom["base"]; ok {
+			return nil, fmt.Errorf("theme %s: unknown base theme %s", name, base)
+		}
+	}
+
+	cm := make(map[string]ui.Attribute)
+	for k, v := range ColorMap {
+		cm[k] = v
+	}
+	for k, v := range overrides {
+		cm[k] = v
 	}
-	ColorMap["par.text.hi"] = ui.ColorWhite
+	for k, v := range custom {
+		if k == "base" {
+			continue
+		}
+		c, err := parseColor(v)
+		if err != nil {
+			return nil, fmt.Errorf("theme %s: %s: %s", name, k, err)
+		}
+		cm[k] = c
+	}
+	return cm, nil
+}
+
+// ThemeNames returns builtin theme names, followed by the themes
+// defined in the config file
+func ThemeNames() []string {
+	names := append([]string{}, themeNames...)
+	for _, name := range config.ThemeNames() {
+		if _, ok := themes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// SetTheme applies a theme to widgets created afterwards
+func SetTheme(name string) error {
+	cm, err := Theme(name)
+	if err != nil {
+		return err
+	}
+	ui.ColorMap = cm
+	config.Update("theme", name)
+	return nil
+}
+
+// applyTheme switches the theme of the running display, recreating
+// widgets holding theme colors
+func applyTheme(name string) error {
+	if err := SetTheme(name); err != nil {
+		return err
+	}
+
+	recreateWidgets()
+	containerView.Recolor()
+	status = widgets.NewStatusLine()
+	status.Align()
+	errView = widgets.NewErrorView()
+	detachPane()
+	pane = single.NewPane()
+	return nil
 }
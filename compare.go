@@ -0,0 +1,124 @@
+package main
+
+import (
+	"github.com/Betzalel75/ctop/config"
+	"github.com/Betzalel75/ctop/container"
+	"github.com/Betzalel75/ctop/cwidgets/single"
+	"github.com/Betzalel75/ctop/models"
+	ui "github.com/gizak/termui"
+)
+
+// ids of containers marked for comparison, in marking order
+var compareMarks []string
+
+func isMarked(id string) bool {
+	for _, m := range compareMarks {
+		if m == id {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleMark marks or unmarks the container under the cursor
+func toggleMark() {
+	c := cursor.Selected()
+	if c == nil {
+		return
+	}
+	for n, m := range compareMarks {
+		if m == c.Id {
+			compareMarks = append(compareMarks[:n], compareMarks[n+1:]...)
+			c.Widgets.SetMarked(false)
+			return
+		}
+	}
+	if len(compareMarks) >= single.MaxCompare {
+		log.Statusf("at most %d containers can be compared", single.MaxCompare)
+		return
+	}
+	compareMarks = append(compareMarks, c.Id)
+	c.Widgets.SetMarked(true)
+}
+
+// marked containers still known to the connector, dropping others
+func markedContainers() (containers []*container.Container) {
+	cSource, err := cursor.cSuper.Get()
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	for _, id := range compareMarks {
+		if c, ok := cSource.Get(id); ok {
+			containers = append(containers, c)
+			ids = append(ids, id)
+		}
+	}
+	compareMarks = ids
+	return containers
+}
+
+func CompareView() MenuFn {
+	containers := markedContainers()
+	if len(containers) < 2 {
+		log.Statusf("mark 2 to %d containers with %s to compare them", single.MaxCompare, config.KeyHint("running.mark"))
+		return nil
+	}
+
+	ui.Clear()
+	ui.DefaultEvtStream.ResetHandlers()
+	defer ui.DefaultEvtStream.ResetHandlers()
+
+	var names []string
+	for _, c := range containers {
+		names = append(names, c.GetMeta("name"))
+	}
+	cmp := single.NewCompare(names)
+
+	// backfill graphs with recorded metrics, aligned on the most recent
+	// sample of the shortest history
+	hists := make([][]models.Metrics, len(containers))
+	n := container.HistoryLen
+	for i, c := range containers {
+		hists[i] = c.History()
+		n = min(n, len(hists[i]))
+	}
+	for i := 0; i < n; i++ {
+		samples := make([]models.Metrics, len(hists))
+		for j, h := range hists {
+			samples[j] = h[len(h)-n+i]
+		}
+		cmp.Add(samples)
+	}
+
+	sample := func() {
+		metrics := make([]models.Metrics, len(containers))
+		metas := make([]models.Meta, len(containers))
+		for i, c := range containers {
+			metrics[i] = c.Metrics
+			metas[i] = c.Meta
+		}
+		cmp.SetMeta(metas)
+		cmp.Add(metrics)
+	}
+	sample()
+
+	cmp.Align()
+	ui.Render(cmp)
+
+	ui.Handle("/sys/kbd/", func(ui.Event) { ui.StopLoop() })
+	ui.Handle("/timer/1s", func(ui.Event) {
+		sample()
+		ui.Render(cmp)
+	})
+	ui.Handle("/sys/wnd/resize", func(e ui.Event) {
+		cmp.SetWidth(ui.TermWidth())
+		cmp.Height = ui.TermHeight()
+		ui.Clear()
+		cmp.Align()
+		ui.Render(cmp)
+	})
+
+	ui.Loop()
+	return nil
+}
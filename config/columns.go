@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -76,12 +79,160 @@ var defaultColumns = []Column{
 		Label:   "Running uptime duration",
 		Enabled: true,
 	},
+	{
+		Name:    "restarts",
+		Label:   "Restart count",
+		Enabled: false,
+	},
+	{
+		Name:    "exit",
+		Label:   "Last exit code",
+		Enabled: false,
+	},
+	{
+		Name:    "health",
+		Label:   "Health status and failing streak",
+		Enabled: false,
+	},
+	{
+		Name:    "throttle",
+		Label:   "CPU throttling (% of periods / total time)",
+		Enabled: false,
+	},
 }
 
 type Column struct {
 	Name    string
 	Label   string
 	Enabled bool
+	Format  ColumnFormat
+}
+
+// ColumnFormat holds width and display settings of a column, read from
+// [columns.<name>] tables of the config file. Zero values keep the
+// defaults of the column
+type ColumnFormat struct {
+	Width     int    `toml:"width,omitempty"`     // fixed width
+	MinWidth  int    `toml:"min,omitempty"`       // lower bound of an automatic width
+	MaxWidth  int    `toml:"max,omitempty"`       // upper bound of an automatic width
+	Align     string `toml:"align,omitempty"`     // left, center or right
+	Units     string `toml:"units,omitempty"`     // byte units, iec or si
+	Precision int    `toml:"precision,omitempty"` // decimals of byte values
+	Style     string `toml:"style,omitempty"`     // gauges drawn as a bar or a number
+}
+
+var columnFormatValues = map[string][]string{
+	"align": {"left", "center", "right"},
+	"units": {"iec", "si"},
+	"style": {"bar", "number"},
+}
+
+// Validate checks format settings for valid values
+func (f ColumnFormat) Validate() error {
+	for k, n := range map[string]int{"width": f.Width, "min": f.MinWidth, "max": f.MaxWidth} {
+		if n < 0 {
+			return fmt.Errorf("invalid %s %d", k, n)
+		}
+	}
+	if f.MaxWidth > 0 && f.MinWidth > f.MaxWidth {
+		return fmt.Errorf("min %d greater than max %d", f.MinWidth, f.MaxWidth)
+	}
+	if f.Precision < 0 || f.Precision > 3 {
+		return fmt.Errorf("invalid precision %d, expected 0 to 3", f.Precision)
+	}
+	for k, v := range map[string]string{"align": f.Align, "units": f.Units, "style": f.Style} {
+		if v != "" && !hasString(columnFormatValues[k], v) {
+			return fmt.Errorf("invalid %s %s, expected one of %s", k, v, strings.Join(columnFormatValues[k], ", "))
+		}
+	}
+	return nil
+}
+
+// String returns settings as space-separated key=value pairs, as read
+// by ParseColumnFormat
+func (f ColumnFormat) String() string {
+	var a []string
+	for _, kv := range []struct {
+		k string
+		n int
+	}{{"width", f.Width}, {"min", f.MinWidth}, {"max", f.MaxWidth}, {"precision", f.Precision}} {
+		if kv.n != 0 {
+			a = append(a, fmt.Sprintf("%s=%d", kv.k, kv.n))
+		}
+	}
+	for _, kv := range [][2]string{{"align", f.Align}, {"units", f.Units}, {"style", f.Style}} {
+		if kv[1] != "" {
+			a = append(a, kv[0]+"="+kv[1])
+		}
+	}
+	return strings.Join(a, " ")
+}
+
+// ParseColumnFormat parses settings given as key=value pairs separated
+// by spaces or commas, e.g. "width=40 align=right"
+func ParseColumnFormat(s string) (f ColumnFormat, err error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+	for _, field := range fields {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return f, fmt.Errorf("invalid setting %s, expected key=value", field)
+		}
+		k, v := strings.ToLower(kv[0]), strings.ToLower(kv[1])
+
+		var n *int
+		switch k {
+		case "width":
+			n = &f.Width
+		case "min":
+			n = &f.MinWidth
+		case "max":
+			n = &f.MaxWidth
+		case "precision":
+			n = &f.Precision
+		case "align":
+			f.Align = v
+		case "units":
+			f.Units = v
+		case "style":
+			f.Style = v
+		default:
+			return f, fmt.Errorf("unknown setting %s", k)
+		}
+		if n != nil {
+			if *n, err = strconv.Atoi(v); err != nil {
+				return f, fmt.Errorf("invalid %s %s", k, v)
+			}
+		}
+	}
+	return f, f.Validate()
+}
+
+// GetColumnFormat returns format settings of a given column name
+func GetColumnFormat(name string) ColumnFormat {
+	lock.RLock()
+	defer lock.RUnlock()
+	if idx := colIndex(name); idx >= 0 {
+		return GlobalColumns[idx].Format
+	}
+	return ColumnFormat{}
+}
+
+// SetColumnFormat validates and sets format settings of a given column name
+func SetColumnFormat(name string, f ColumnFormat) error {
+	if err := f.Validate(); err != nil {
+		return fmt.Errorf("column %s: %s", name, err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	idx := colIndex(name)
+	if idx < 0 {
+		return fmt.Errorf("no such column name: %s", name)
+	}
+	col := GlobalColumns[idx]
+	log.Noticef("config change [column-%s]: %s -> %s", col.Name, quote(col.Format.String()), quote(f.String()))
+	col.Format = f
+	return nil
 }
 
 // ColumnsString returns an ordered and comma-delimited string of currently enabled Columns
@@ -173,3 +324,39 @@ func colIndex(name string) int {
 	}
 	return -1
 }
+
+func readColumnFormats(formats map[string]ColumnFormat) {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := SetColumnFormat(name, formats[name]); err != nil {
+			log.Warningf("ignoring column format: %s", err)
+		}
+	}
+}
+
+func exportColumnFormats() map[string]ColumnFormat {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	formats := make(map[string]ColumnFormat)
+	for _, col := range GlobalColumns {
+		if col.Format != (ColumnFormat{}) {
+			formats[col.Name] = col.Format
+		}
+	}
+	return formats
+}
+
+func hasString(a []string, s string) bool {
+	for _, x := range a {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
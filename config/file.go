@@ -15,20 +15,33 @@ var (
 )
 
 type File struct {
-	Options map[string]string `toml:"options"`
-	Toggles map[string]bool   `toml:"toggles"`
+	Options  map[string]string                 `toml:"options"`
+	Toggles  map[string]bool                   `toml:"toggles"`
+	Keys     map[string]interface{}            `toml:"keys,omitempty"`
+	Themes   map[string]map[string]string      `toml:"theme,omitempty"`
+	Profiles map[string]map[string]interface{} `toml:"profiles,omitempty"`
+	Views    []View                            `toml:"views,omitempty"`
+	Columns  map[string]ColumnFormat           `toml:"columns,omitempty"`
 }
 
 func exportConfig() File {
 	// update columns param from working config
 	Update("columns", ColumnsString())
+	keys := exportKeys()
+	themes := exportThemes()
+	columns := exportColumnFormats()
 
 	lock.RLock()
 	defer lock.RUnlock()
 
 	c := File{
-		Options: make(map[string]string),
-		Toggles: make(map[string]bool),
+		Options:  make(map[string]string),
+		Toggles:  make(map[string]bool),
+		Keys:     keys,
+		Themes:   themes,
+		Profiles: exportProfiles(),
+		Views:    exportViews(),
+		Columns:  columns,
 	}
 
 	for _, p := range GlobalParams {
@@ -38,6 +51,17 @@ func exportConfig() File {
 		c.Toggles[sw.Key] = sw.Val
 	}
 
+	// working profile options and switches are saved to the active
+	// profile, leaving base ones unchanged
+	if activeProfile != "" {
+		for k, v := range baseParams {
+			c.Options[k] = v
+		}
+		for k, v := range baseSwitches {
+			c.Toggles[k] = v
+		}
+	}
+
 	return c
 }
 
@@ -59,9 +83,25 @@ func Read() error {
 	for k, v := range config.Toggles {
 		UpdateSwitch(k, v)
 	}
+	readThemes(config.Themes)
 
 	// set working column config, if provided
-	colStr := GetVal("columns")
+	setColumnsString(GetVal("columns"))
+	readColumnFormats(config.Columns)
+
+	lock.Lock()
+	saveBase()
+	lock.Unlock()
+	readProfiles(config.Profiles)
+	readViews(config.Views)
+
+	// key bindings are applied last, returning a *KeysError
+	return readKeys(config.Keys)
+}
+
+// set working column config from a comma-delimited string of enabled
+// columns, if not empty
+func setColumnsString(colStr string) {
 	if len(colStr) > 0 {
 		var colNames []string
 		for _, s := range strings.Split(colStr, ",") {
@@ -72,8 +112,6 @@ func Read() error {
 		}
 		SetColumns(colNames)
 	}
-
-	return nil
 }
 
 func Write() (path string, err error) {
@@ -109,6 +147,12 @@ func Write() (path string, err error) {
 		return path, fmt.Errorf("failed to write config: %s", err)
 	}
 
+	lock.Lock()
+	if activeProfile != "" {
+		GlobalProfiles[activeProfile] = currentProfile()
+	}
+	lock.Unlock()
+
 	return path, nil
 }
 
@@ -0,0 +1,392 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Key scopes, the views actions apply in. Navigation actions apply in
+// every view, global actions in the Running, All and Swarm views
+const (
+	ScopeNav       = "nav"
+	ScopeGlobal    = "global"
+	ScopeRunning   = "running"
+	ScopeList      = "list"
+	ScopeColumns   = "columns"
+	ScopeContainer = "container"
+	ScopeProcesses = "processes"
+	ScopeTree      = "tree"
+	ScopeLogs      = "logs"
+	ScopeEvents    = "events"
+	ScopeCopy      = "copy"
+	ScopeConfirm   = "confirm"
+)
+
+type KeyScope struct {
+	Name  string
+	Title string
+}
+
+// KeyScopes in help order
+var KeyScopes = []KeyScope{
+	{ScopeNav, "Navigation"},
+	{ScopeGlobal, "Global"},
+	{ScopeRunning, "Running view"},
+	{ScopeList, "All and Swarm views"},
+	{ScopeColumns, "Columns menu"},
+	{ScopeContainer, "Container menu"},
+	{ScopeProcesses, "Processes view"},
+	{ScopeTree, "Inspect, diff and files views"},
+	{ScopeLogs, "Log view"},
+	{ScopeEvents, "Event timeline"},
+	{ScopeCopy, "Copy dialog"},
+	{ScopeConfirm, "Confirm dialog"},
+}
+
+// scopes active along with a scope, besides navigation
+var keyScopeSets = map[string][]string{
+	ScopeRunning: {ScopeGlobal},
+	ScopeList:    {ScopeGlobal},
+}
+
+// defaults
+var defaultKeys = []KeyBinding{
+	{Action: "up", Scope: ScopeNav, Keys: []string{"<up>", "k"}, Label: "move up"},
+	{Action: "down", Scope: ScopeNav, Keys: []string{"<down>", "j"}, Label: "move down"},
+	{Action: "pgup", Scope: ScopeNav, Keys: []string{"<previous>", "C-<up>"}, Label: "page up"},
+	{Action: "pgdown", Scope: ScopeNav, Keys: []string{"<next>", "C-<down>"}, Label: "page down"},
+	{Action: "exit", Scope: ScopeNav, Keys: []string{"q", "C-c", "<escape>"}, Label: "back / exit ctop"},
+
+	{Action: "help", Scope: ScopeGlobal, Keys: []string{"h", "?"}, Label: "open this help dialog"},
+	{Action: "header", Scope: ScopeGlobal, Keys: []string{"H"}, Label: "toggle ctop header"},
+	{Action: "host", Scope: ScopeGlobal, Keys: []string{"O"}, Label: "toggle host overview panel"},
+	{Action: "columns", Scope: ScopeGlobal, Keys: []string{"c"}, Label: "configure columns"},
+	{Action: "profile", Scope: ScopeGlobal, Keys: []string{"P"}, Label: "switch config profile"},
+	{Action: "theme", Scope: ScopeGlobal, Keys: []string{"t"}, Label: "select color theme"},
+	{Action: "split", Scope: ScopeGlobal, Keys: []string{"|"}, Label: "toggle detail pane below the container list"},
+	{Action: "splitMode", Scope: ScopeGlobal, Keys: []string{"m"}, Label: "cycle detail pane: graphs, logs, env"},
+	{Action: "splitGrow", Scope: ScopeGlobal, Keys: []string{"+"}, Label: "grow detail pane"},
+	{Action: "splitShrink", Scope: ScopeGlobal, Keys: []string{"-"}, Label: "shrink detail pane"},
+	{Action: "mouse", Scope: ScopeGlobal, Keys: []string{"M"}, Label: "toggle mouse support"},
+	{Action: "save", Scope: ScopeGlobal, Keys: []string{"S"}, Label: "save current configuration to file"},
+	{Action: "nextView", Scope: ScopeGlobal, Keys: []string{"<tab>"}, Label: "cycle between Running/All/Swarm views"},
+	{Action: "runningView", Scope: ScopeGlobal, Keys: []string{"<f1>"}, Label: "switch to Running view"},
+	{Action: "allView", Scope: ScopeGlobal, Keys: []string{"<f2>"}, Label: "switch to All view"},
+	{Action: "swarmView", Scope: ScopeGlobal, Keys: []string{"<f3>"}, Label: "switch to Swarm view"},
+	{Action: "saveView", Scope: ScopeGlobal, Keys: []string{"v"}, Label: "save filter, sort and columns as a view"},
+	{Action: "deleteView", Scope: ScopeGlobal, Keys: []string{"V"}, Label: "delete current view"},
+	{Action: "view1", Scope: ScopeGlobal, Keys: []string{"1"}, Label: "recall view 1"},
+	{Action: "view2", Scope: ScopeGlobal, Keys: []string{"2"}, Label: "recall view 2"},
+	{Action: "view3", Scope: ScopeGlobal, Keys: []string{"3"}, Label: "recall view 3"},
+	{Action: "view4", Scope: ScopeGlobal, Keys: []string{"4"}, Label: "recall view 4"},
+	{Action: "view5", Scope: ScopeGlobal, Keys: []string{"5"}, Label: "recall view 5"},
+	{Action: "view6", Scope: ScopeGlobal, Keys: []string{"6"}, Label: "recall view 6"},
+	{Action: "view7", Scope: ScopeGlobal, Keys: []string{"7"}, Label: "recall view 7"},
+	{Action: "view8", Scope: ScopeGlobal, Keys: []string{"8"}, Label: "recall view 8"},
+	{Action: "view9", Scope: ScopeGlobal, Keys: []string{"9"}, Label: "recall view 9"},
+
+	{Action: "running.menu", Scope: ScopeRunning, Keys: []string{"<enter>"}, Label: "open container menu"},
+	{Action: "running.all", Scope: ScopeRunning, Keys: []string{"a"}, Label: "toggle display of all containers"},
+	{Action: "running.filter", Scope: ScopeRunning, Keys: []string{"f"}, Label: "filter displayed containers"},
+	{Action: "running.sort", Scope: ScopeRunning, Keys: []string{"s"}, Label: "select container sort field"},
+	{Action: "running.reverse", Scope: ScopeRunning, Keys: []string{"r"}, Label: "reverse container sort order"},
+	{Action: "running.single", Scope: ScopeRunning, Keys: []string{"o", "<right>"}, Label: "open single view"},
+	{Action: "running.inspect", Scope: ScopeRunning, Keys: []string{"i"}, Label: "inspect container"},
+	{Action: "running.logs", Scope: ScopeRunning, Keys: []string{"l", "<left>"}, Label: "view container logs"},
+	{Action: "running.exec", Scope: ScopeRunning, Keys: []string{"e"}, Label: "exec shell"},
+	{Action: "running.browser", Scope: ScopeRunning, Keys: []string{"w"}, Label: "open browser"},
+	{Action: "running.events", Scope: ScopeRunning, Keys: []string{"E"}, Label: "event timeline"},
+	{Action: "running.dump", Scope: ScopeRunning, Keys: []string{"D"}, Label: "dump container to the log"},
+	{Action: "running.mark", Scope: ScopeRunning, Keys: []string{"<space>"}, Label: "mark container for comparison"},
+	{Action: "running.compare", Scope: ScopeRunning, Keys: []string{"C"}, Label: "compare marked containers"},
+
+	{Action: "list.open", Scope: ScopeList, Keys: []string{"<enter>"}, Label: "select menu item / list tasks of the service"},
+	{Action: "list.select", Scope: ScopeList, Keys: []string{"<space>"}, Label: "toggle item selection"},
+	{Action: "list.delete", Scope: ScopeList, Keys: []string{"d"}, Label: "delete selected items"},
+	{Action: "list.inspect", Scope: ScopeList, Keys: []string{"i"}, Label: "inspect item"},
+	{Action: "list.prune", Scope: ScopeList, Keys: []string{"p"}, Label: "prune category (Disk Usage) / confirm publishing"},
+	{Action: "list.refresh", Scope: ScopeList, Keys: []string{"r"}, Label: "refresh current list"},
+	{Action: "list.scale", Scope: ScopeList, Keys: []string{"s"}, Label: "scale service (Swarm)"},
+	{Action: "list.update", Scope: ScopeList, Keys: []string{"u"}, Label: "force update service (Swarm)"},
+	{Action: "list.rollback", Scope: ScopeList, Keys: []string{"b"}, Label: "roll back service (Swarm)"},
+
+	{Action: "columns.toggle", Scope: ScopeColumns, Keys: []string{"<enter>", "x"}, Label: "enable/disable column"},
+	{Action: "columns.edit", Scope: ScopeColumns, Keys: []string{"e"}, Label: "edit column width and format"},
+
+	{Action: "container.single", Scope: ScopeContainer, Keys: []string{"o"}, Label: "single view"},
+	{Action: "container.logs", Scope: ScopeContainer, Keys: []string{"l"}, Label: "log view"},
+	{Action: "container.inspect", Scope: ScopeContainer, Keys: []string{"i"}, Label: "inspect"},
+	{Action: "container.diff", Scope: ScopeContainer, Keys: []string{"d"}, Label: "diff"},
+	{Action: "container.files", Scope: ScopeContainer, Keys: []string{"f"}, Label: "browse files"},
+	{Action: "container.copy", Scope: ScopeContainer, Keys: []string{"x"}, Label: "copy files"},
+	{Action: "container.top", Scope: ScopeContainer, Keys: []string{"t"}, Label: "processes"},
+	{Action: "container.stop", Scope: ScopeContainer, Keys: []string{"s"}, Label: "stop / start"},
+	{Action: "container.pause", Scope: ScopeContainer, Keys: []string{"p"}, Label: "pause / unpause"},
+	{Action: "container.restart", Scope: ScopeContainer, Keys: []string{"r"}, Label: "restart"},
+	{Action: "container.exec", Scope: ScopeContainer, Keys: []string{"e"}, Label: "exec shell"},
+	{Action: "container.browser", Scope: ScopeContainer, Keys: []string{"w"}, Label: "open in browser"},
+	{Action: "container.remove", Scope: ScopeContainer, Keys: []string{"R"}, Label: "remove"},
+	{Action: "container.cancel", Scope: ScopeContainer, Keys: []string{"c"}, Label: "cancel"},
+
+	{Action: "processes.sort", Scope: ScopeProcesses, Keys: []string{"s"}, Label: "sort"},
+	{Action: "processes.reverse", Scope: ScopeProcesses, Keys: []string{"r"}, Label: "reverse"},
+	{Action: "processes.term", Scope: ScopeProcesses, Keys: []string{"T"}, Label: "SIGTERM"},
+	{Action: "processes.kill", Scope: ScopeProcesses, Keys: []string{"K"}, Label: "SIGKILL"},
+
+	{Action: "tree.toggle", Scope: ScopeTree, Keys: []string{"<enter>", "<space>"}, Label: "collapse/expand"},
+	{Action: "tree.expand", Scope: ScopeTree, Keys: []string{"<right>"}, Label: "expand"},
+	{Action: "tree.collapse", Scope: ScopeTree, Keys: []string{"<left>"}, Label: "collapse"},
+	{Action: "tree.search", Scope: ScopeTree, Keys: []string{"/"}, Label: "search"},
+	{Action: "tree.next", Scope: ScopeTree, Keys: []string{"n"}, Label: "next"},
+	{Action: "tree.copy", Scope: ScopeTree, Keys: []string{"y"}, Label: "copy path+value"},
+	{Action: "tree.goto", Scope: ScopeTree, Keys: []string{"g"}, Label: "go to path"},
+	{Action: "tree.pull", Scope: ScopeTree, Keys: []string{"x"}, Label: "pull to local path"},
+
+	{Action: "logs.timestamps", Scope: ScopeLogs, Keys: []string{"t"}, Label: "toggle timestamps"},
+
+	{Action: "events.type", Scope: ScopeEvents, Keys: []string{"t"}, Label: "type"},
+	{Action: "events.container", Scope: ScopeEvents, Keys: []string{"c"}, Label: "container"},
+	{Action: "events.probes", Scope: ScopeEvents, Keys: []string{"p"}, Label: "health probes"},
+	{Action: "events.open", Scope: ScopeEvents, Keys: []string{"<enter>"}, Label: "go to container"},
+
+	{Action: "copy.in", Scope: ScopeCopy, Keys: []string{"i"}, Label: "local -> container"},
+	{Action: "copy.out", Scope: ScopeCopy, Keys: []string{"o"}, Label: "container -> local"},
+	{Action: "copy.cancel", Scope: ScopeCopy, Keys: []string{"c"}, Label: "cancel"},
+
+	{Action: "confirm.yes", Scope: ScopeConfirm, Keys: []string{"y"}, Label: "yes"},
+	{Action: "confirm.no", Scope: ScopeConfirm, Keys: []string{"c"}, Label: "cancel"},
+}
+
+// KeyBinding binds keys, as named in termui keyboard events, to an
+// action. Actions are named after their scope, but for navigation and
+// global actions
+type KeyBinding struct {
+	Action string
+	Scope  string
+	Keys   []string
+	Label  string
+}
+
+// special key names, which may be prefixed with C- or M-
+var specialKeys = map[string]bool{
+	"<insert>": true, "<delete>": true, "<home>": true, "<end>": true,
+	"<previous>": true, "<next>": true, "<up>": true, "<down>": true,
+	"<left>": true, "<right>": true, "<space>": true, "<backspace>": true,
+	"<tab>": true, "<enter>": true, "<escape>": true,
+	"<f1>": true, "<f2>": true, "<f3>": true, "<f4>": true, "<f5>": true, "<f6>": true,
+	"<f7>": true, "<f8>": true, "<f9>": true, "<f10>": true, "<f11>": true, "<f12>": true,
+}
+
+func validKey(k string) bool {
+	k = strings.TrimPrefix(k, "C-")
+	k = strings.TrimPrefix(k, "M-")
+	if strings.HasPrefix(k, "<") {
+		return specialKeys[k]
+	}
+	return utf8.RuneCountInString(k) == 1
+}
+
+// KeysError reports invalid or conflicting key bindings
+type KeysError struct {
+	Errors []string
+}
+
+func (e *KeysError) Error() string {
+	return "invalid key bindings:\n  " + strings.Join(e.Errors, "\n  ")
+}
+
+// GetKeyBinding returns a KeyBinding by action, nil if not existing
+func GetKeyBinding(action string) *KeyBinding {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	for _, b := range GlobalKeys {
+		if b.Action == action {
+			return b
+		}
+	}
+	return nil
+}
+
+// GetKeys returns the keys bound to an action
+func GetKeys(action string) []string {
+	b := GetKeyBinding(action)
+	if b == nil {
+		return nil
+	}
+
+	lock.RLock()
+	defer lock.RUnlock()
+	return append([]string(nil), b.Keys...)
+}
+
+// SetKeys binds keys to an action, replacing its bindings
+func SetKeys(action string, keys []string) error {
+	b := GetKeyBinding(action)
+	if b == nil {
+		return fmt.Errorf("unknown action %s", quote(action))
+	}
+	for _, k := range keys {
+		if !validKey(k) {
+			return fmt.Errorf("invalid key %s for action %s", quote(k), quote(action))
+		}
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	log.Noticef("config change [keys.%s]: %v -> %v", action, b.Keys, keys)
+	b.Keys = keys
+	return nil
+}
+
+// KeysByScope returns the key bindings of a scope
+func KeysByScope(scope string) (a []*KeyBinding) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	for _, b := range GlobalKeys {
+		if b.Scope == scope {
+			a = append(a, b)
+		}
+	}
+	return a
+}
+
+// FormatKey returns a key as shown in help, [k] or <enter>
+func FormatKey(k string) string {
+	if strings.HasPrefix(k, "<") {
+		return k
+	}
+	return "[" + k + "]"
+}
+
+// initKeys sets key bindings to their defaults
+func initKeys() {
+	GlobalKeys = nil
+	for _, b := range defaultKeys {
+		x := b
+		x.Keys = append([]string(nil), b.Keys...)
+		GlobalKeys = append(GlobalKeys, &x)
+	}
+}
+
+// KeyHint returns the first key bound to an action, formatted for help
+// texts
+func KeyHint(action string) string {
+	keys := GetKeys(action)
+	if len(keys) == 0 {
+		return "[]"
+	}
+	return FormatKey(keys[0])
+}
+
+// KeyConflicts returns an error listing keys bound to several actions
+// active in the same view, nil if none
+func KeyConflicts() error {
+	var errs []string
+	reported := make(map[string]bool)
+
+	for _, scope := range KeyScopes {
+		if scope.Name == ScopeNav || scope.Name == ScopeGlobal {
+			continue
+		}
+		active := append([]string{ScopeNav, scope.Name}, keyScopeSets[scope.Name]...)
+
+		bound := make(map[string]string) // action by key
+		for _, s := range active {
+			for _, b := range KeysByScope(s) {
+				for _, k := range b.Keys {
+					other, ok := bound[k]
+					if !ok {
+						bound[k] = b.Action
+						continue
+					}
+					if other == b.Action {
+						continue
+					}
+					msg := fmt.Sprintf("key %s bound to both %s and %s", quote(k), quote(other), quote(b.Action))
+					if !reported[msg] {
+						reported[msg] = true
+						errs = append(errs, msg)
+					}
+				}
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return &KeysError{errs}
+	}
+	return nil
+}
+
+// readKeys applies a [keys] table, holding navigation and global
+// actions, and a table per other scope
+func readKeys(keys map[string]interface{}) error {
+	var errs []string
+	set := func(action string, v interface{}) {
+		var ks []string
+		switch v := v.(type) {
+		case string:
+			ks = []string{v}
+		case []interface{}:
+			for _, k := range v {
+				s, ok := k.(string)
+				if !ok {
+					errs = append(errs, fmt.Sprintf("keys of %s must be strings", quote(action)))
+					return
+				}
+				ks = append(ks, s)
+			}
+		default:
+			errs = append(errs, fmt.Sprintf("keys of %s must be a string or an array", quote(action)))
+			return
+		}
+		if err := SetKeys(action, ks); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	for k, v := range keys {
+		if table, ok := v.(map[string]interface{}); ok {
+			for name, v := range table {
+				set(k+"."+name, v)
+			}
+			continue
+		}
+		set(k, v)
+	}
+
+	sort.Strings(errs)
+	if err := KeyConflicts(); err != nil {
+		errs = append(errs, err.(*KeysError).Errors...)
+	}
+	if len(errs) > 0 {
+		return &KeysError{errs}
+	}
+	return nil
+}
+
+// exportKeys returns the bindings differing from defaults, as a [keys]
+// table
+func exportKeys() map[string]interface{} {
+	keys := make(map[string]interface{})
+	for _, d := range defaultKeys {
+		b := GetKeyBinding(d.Action)
+		if b == nil || strings.Join(b.Keys, "\x00") == strings.Join(d.Keys, "\x00") {
+			continue
+		}
+		if d.Scope == ScopeNav || d.Scope == ScopeGlobal {
+			keys[d.Action] = b.Keys
+			continue
+		}
+		table, ok := keys[d.Scope].(map[string]interface{})
+		if !ok {
+			table = make(map[string]interface{})
+			keys[d.Scope] = table
+		}
+		table[strings.TrimPrefix(d.Action, d.Scope+".")] = b.Keys
+	}
+	return keys
+}
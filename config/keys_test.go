@@ -0,0 +1,73 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDefaultKeysNoConflict(t *testing.T) {
+	initKeys()
+	if err := KeyConflicts(); err != nil {
+		t.Fatalf("expected no conflict in default keys, got: %s", err)
+	}
+	for _, b := range defaultKeys {
+		for _, k := range b.Keys {
+			if !validKey(k) {
+				t.Errorf("invalid default key %q for %s", k, b.Action)
+			}
+		}
+	}
+}
+
+func TestKeysConflictingOverride(t *testing.T) {
+	initKeys()
+	err := readKeys(map[string]interface{}{
+		"running": map[string]interface{}{"menu": "f"},
+	})
+	var kerr *KeysError
+	if !errors.As(err, &kerr) {
+		t.Fatalf("expected a *KeysError, got: %v", err)
+	}
+	if len(kerr.Errors) != 1 || !strings.Contains(kerr.Errors[0], `"running.menu"`) || !strings.Contains(kerr.Errors[0], `"running.filter"`) {
+		t.Errorf("expected conflict of running.menu and running.filter, got: %v", kerr.Errors)
+	}
+}
+
+func TestKeysOverride(t *testing.T) {
+	initKeys()
+	err := readKeys(map[string]interface{}{
+		"help":    []interface{}{"<f12>", "?"},
+		"running": map[string]interface{}{"menu": "C-o"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if keys := GetKeys("help"); strings.Join(keys, ",") != "<f12>,?" {
+		t.Errorf("expected: <f12>,?, got: %v", keys)
+	}
+	if keys := GetKeys("running.menu"); strings.Join(keys, ",") != "C-o" {
+		t.Errorf("expected: C-o, got: %v", keys)
+	}
+}
+
+func TestKeysInvalid(t *testing.T) {
+	tests := []struct {
+		keys map[string]interface{}
+		err  string
+	}{
+		{map[string]interface{}{"help": "<bogus>"}, `invalid key "<bogus>"`},
+		{map[string]interface{}{"help": "ab"}, `invalid key "ab"`},
+		{map[string]interface{}{"help": 3}, "must be a string or an array"},
+		{map[string]interface{}{"help": []interface{}{"x", 2}}, "must be strings"},
+		{map[string]interface{}{"running": map[string]interface{}{"bogus": "x"}}, `unknown action "running.bogus"`},
+	}
+
+	for _, tt := range tests {
+		initKeys()
+		err := readKeys(tt.keys)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%v: expected error %q, got: %v", tt.keys, tt.err, err)
+		}
+	}
+}
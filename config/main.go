@@ -12,6 +12,7 @@ var (
 	GlobalParams   []*Param
 	GlobalSwitches []*Switch
 	GlobalColumns  []*Column
+	GlobalKeys     []*KeyBinding
 	lock           sync.RWMutex
 	log            = logging.Init()
 )
@@ -30,6 +31,8 @@ func Init() {
 		GlobalColumns = append(GlobalColumns, &x)
 		log.Infof("loaded default widget config [%s]: %t", quote(x.Name), x.Enabled)
 	}
+	saveBase()
+	initKeys()
 }
 
 func quote(s string) string {
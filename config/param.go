@@ -17,6 +17,31 @@ var defaultParams = []*Param{
 		Val:   "status,name,id,cpu,mem,net,io,pids,uptime",
 		Label: "Enabled Columns",
 	},
+	&Param{
+		Key:   "connector",
+		Val:   "docker",
+		Label: "Container Connector",
+	},
+	&Param{
+		Key:   "profile",
+		Val:   "",
+		Label: "Config Profile",
+	},
+	&Param{
+		Key:   "theme",
+		Val:   "dark",
+		Label: "Color Theme",
+	},
+	&Param{
+		Key:   "splitMode",
+		Val:   "graphs",
+		Label: "Split Pane Content",
+	},
+	&Param{
+		Key:   "splitHeight",
+		Val:   "12",
+		Label: "Split Pane Height",
+	},
 }
 
 type Param struct {
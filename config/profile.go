@@ -0,0 +1,181 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// options set by profiles; profiles also set every switch
+var profileParams = []string{"columns", "sortField", "filterStr", "connector"}
+
+// Profile holds the options and switches of a [profiles.<name>] table,
+// applied over the options and toggles of the config file
+type Profile struct {
+	Options map[string]string
+	Toggles map[string]bool
+}
+
+var (
+	GlobalProfiles = make(map[string]*Profile)
+	activeProfile  string
+	// profile options and switches as set without a profile
+	baseParams   = make(map[string]string)
+	baseSwitches = make(map[string]bool)
+)
+
+func isProfileParam(k string) bool {
+	for _, p := range profileParams {
+		if p == k {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshot the working profile options and switches as base values; the
+// lock must be held
+func saveBase() {
+	for _, k := range profileParams {
+		for _, p := range GlobalParams {
+			if p.Key == k {
+				baseParams[k] = p.Val
+			}
+		}
+	}
+	for _, sw := range GlobalSwitches {
+		baseSwitches[sw.Key] = sw.Val
+	}
+}
+
+// current profile options and switches; the lock must be held
+func currentProfile() *Profile {
+	p := &Profile{Options: make(map[string]string), Toggles: make(map[string]bool)}
+	for _, k := range profileParams {
+		for _, param := range GlobalParams {
+			if param.Key == k {
+				p.Options[k] = param.Val
+			}
+		}
+	}
+	for _, sw := range GlobalSwitches {
+		p.Toggles[sw.Key] = sw.Val
+	}
+	return p
+}
+
+// Profiles returns the names of configured profiles
+func Profiles() (a []string) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	for name := range GlobalProfiles {
+		a = append(a, name)
+	}
+	sort.Strings(a)
+	return a
+}
+
+// ActiveProfile returns the name of the active profile, empty if none
+func ActiveProfile() string {
+	lock.RLock()
+	defer lock.RUnlock()
+	return activeProfile
+}
+
+// SetProfile applies a profile over the base options and switches, or
+// restores them if name is empty
+func SetProfile(name string) error {
+	lock.RLock()
+	p, ok := GlobalProfiles[name]
+	lock.RUnlock()
+	if name != "" && !ok {
+		return fmt.Errorf("unknown profile %s", quote(name))
+	}
+
+	lock.RLock()
+	params := make(map[string]string)
+	switches := make(map[string]bool)
+	for k, v := range baseParams {
+		params[k] = v
+	}
+	for k, v := range baseSwitches {
+		switches[k] = v
+	}
+	if p != nil {
+		for k, v := range p.Options {
+			params[k] = v
+		}
+		for k, v := range p.Toggles {
+			switches[k] = v
+		}
+	}
+	lock.RUnlock()
+
+	for k, v := range params {
+		if GetVal(k) != v {
+			Update(k, v)
+		}
+	}
+	for k, v := range switches {
+		UpdateSwitch(k, v)
+	}
+	setColumnsString(GetVal("columns"))
+	Update("profile", name)
+
+	lock.Lock()
+	activeProfile = name
+	lock.Unlock()
+	log.Noticef("config change [profile]: %s", quote(name))
+	return nil
+}
+
+// readProfiles reads [profiles.<name>] tables, holding option strings
+// and toggle booleans
+func readProfiles(profiles map[string]map[string]interface{}) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	for name, t := range profiles {
+		p := &Profile{Options: make(map[string]string), Toggles: make(map[string]bool)}
+		for k, v := range t {
+			switch v := v.(type) {
+			case string:
+				if !isProfileParam(k) {
+					log.Warningf("profile %s: ignoring unknown option %s", quote(name), quote(k))
+					continue
+				}
+				p.Options[k] = v
+			case bool:
+				if _, ok := baseSwitches[k]; !ok {
+					log.Warningf("profile %s: ignoring unknown toggle %s", quote(name), quote(k))
+					continue
+				}
+				p.Toggles[k] = v
+			default:
+				log.Warningf("profile %s: ignoring %s, not a string or a boolean", quote(name), quote(k))
+			}
+		}
+		GlobalProfiles[name] = p
+		log.Infof("loaded config profile [%s]: %d options, %d toggles", quote(name), len(p.Options), len(p.Toggles))
+	}
+}
+
+// exportProfiles returns profiles as [profiles] tables, with the working
+// options and switches saved to the active profile; the lock must be held
+func exportProfiles() map[string]map[string]interface{} {
+	profiles := make(map[string]map[string]interface{})
+	for name, p := range GlobalProfiles {
+		if name == activeProfile {
+			p = currentProfile()
+		}
+		t := make(map[string]interface{})
+		for k, v := range p.Options {
+			t[k] = v
+		}
+		for k, v := range p.Toggles {
+			t[k] = v
+		}
+		profiles[name] = t
+	}
+	return profiles
+}
@@ -22,6 +22,21 @@ var defaultSwitches = []*Switch{
 		Val:   true,
 		Label: "Enable status header",
 	},
+	&Switch{
+		Key:   "hostPanel",
+		Val:   false,
+		Label: "Show host usage below the header",
+	},
+	&Switch{
+		Key:   "splitPane",
+		Val:   false,
+		Label: "Show a detail pane below the container list",
+	},
+	&Switch{
+		Key:   "enableMouse",
+		Val:   false,
+		Label: "Enable mouse support",
+	},
 }
 
 type Switch struct {
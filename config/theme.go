@@ -0,0 +1,51 @@
+package config
+
+import (
+	"sort"
+)
+
+// GlobalThemes holds themes defined in [theme.<name>] tables of the
+// config file, mapping theme attributes to color names
+var GlobalThemes = make(map[string]map[string]string)
+
+// GetTheme returns a theme defined in the config file
+func GetTheme(name string) (map[string]string, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	t, ok := GlobalThemes[name]
+	return t, ok
+}
+
+// ThemeNames returns the names of themes defined in the config file
+func ThemeNames() (a []string) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	for name := range GlobalThemes {
+		a = append(a, name)
+	}
+	sort.Strings(a)
+	return a
+}
+
+func readThemes(themes map[string]map[string]string) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	for name, t := range themes {
+		GlobalThemes[name] = t
+		log.Infof("loaded config theme [%s]: %d colors", quote(name), len(t))
+	}
+}
+
+func exportThemes() map[string]map[string]string {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	themes := make(map[string]map[string]string)
+	for name, t := range GlobalThemes {
+		themes[name] = t
+	}
+	return themes
+}
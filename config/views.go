@@ -0,0 +1,140 @@
+package config
+
+import (
+	"fmt"
+)
+
+// MaxViews is the number of saved views, recalled with keys 1 to 9
+const MaxViews = 9
+
+// View is a saved filter, sort and set of columns
+type View struct {
+	Name     string `toml:"name"`
+	Filter   string `toml:"filter"`
+	Sort     string `toml:"sort"`
+	Reversed bool   `toml:"reversed"`
+	Columns  string `toml:"columns"`
+}
+
+var (
+	GlobalViews []*View
+	activeView  = -1
+)
+
+// Views returns saved views, in recall order
+func Views() (a []View) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	for _, v := range GlobalViews {
+		a = append(a, *v)
+	}
+	return a
+}
+
+// ActiveView returns the index of the last saved or recalled view, -1
+// if none
+func ActiveView() int {
+	lock.RLock()
+	defer lock.RUnlock()
+	return activeView
+}
+
+// SaveView saves the working filter, sort and columns as a view,
+// replacing a view of the same name, and returns its index
+func SaveView(name string) (int, error) {
+	v := &View{
+		Name:     name,
+		Filter:   GetVal("filterStr"),
+		Sort:     GetVal("sortField"),
+		Reversed: GetSwitchVal("sortReversed"),
+		Columns:  ColumnsString(),
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	n := -1
+	for i, sv := range GlobalViews {
+		if sv.Name == name {
+			n = i
+		}
+	}
+	if n < 0 {
+		if len(GlobalViews) >= MaxViews {
+			return n, fmt.Errorf("cannot save more than %d views", MaxViews)
+		}
+		GlobalViews = append(GlobalViews, v)
+		n = len(GlobalViews) - 1
+	} else {
+		GlobalViews[n] = v
+	}
+	activeView = n
+	log.Noticef("config change [view-%d]: %s", n+1, quote(name))
+	return n, nil
+}
+
+// RecallView applies the filter, sort and columns of a view
+func RecallView(n int) error {
+	lock.RLock()
+	if n < 0 || n >= len(GlobalViews) {
+		lock.RUnlock()
+		return fmt.Errorf("no view %d", n+1)
+	}
+	v := *GlobalViews[n]
+	lock.RUnlock()
+
+	Update("filterStr", v.Filter)
+	if v.Sort != "" {
+		Update("sortField", v.Sort)
+	}
+	UpdateSwitch("sortReversed", v.Reversed)
+	setColumnsString(v.Columns)
+
+	lock.Lock()
+	activeView = n
+	lock.Unlock()
+	return nil
+}
+
+// DeleteView removes a view, shifting the following ones
+func DeleteView(n int) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if n < 0 || n >= len(GlobalViews) {
+		return fmt.Errorf("no view %d", n+1)
+	}
+	log.Noticef("config change [view-%d]: deleted %s", n+1, quote(GlobalViews[n].Name))
+	GlobalViews = append(GlobalViews[:n], GlobalViews[n+1:]...)
+	switch {
+	case activeView == n:
+		activeView = -1
+	case activeView > n:
+		activeView--
+	}
+	return nil
+}
+
+func readViews(views []View) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	for i := range views {
+		if len(GlobalViews) >= MaxViews {
+			log.Warningf("ignoring views beyond the first %d", MaxViews)
+			break
+		}
+		v := views[i]
+		GlobalViews = append(GlobalViews, &v)
+		log.Infof("loaded config view [%d]: %s", len(GlobalViews), quote(v.Name))
+	}
+}
+
+// exportViews returns saved views; the lock must be held
+func exportViews() (a []View) {
+	for _, v := range GlobalViews {
+		a = append(a, *v)
+	}
+	return a
+}
@@ -0,0 +1,294 @@
+//go:build linux
+// +build linux
+
+package connector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Betzalel75/ctop/connector/collector"
+	"github.com/Betzalel75/ctop/connector/manager"
+	"github.com/Betzalel75/ctop/container"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/opencontainers/runc/libcontainer/cgroups/fs"
+	"github.com/opencontainers/runc/libcontainer/cgroups/fs2"
+	"github.com/opencontainers/runc/libcontainer/configs"
+)
+
+func init() { enabled["cgroup"] = NewCgroup }
+
+// cgroups of systemd-nspawn and libvirt machines, and of LXC/LXD
+// containers, by default
+const defaultCgroupPatterns = "machine.slice/*.scope,lxc.payload.*,lxc/*"
+
+type CgroupOpts struct {
+	patterns []string // glob patterns of cgroup paths, relative to the hierarchy root
+	root     string   // hierarchy root cgroups are discovered in
+	v1Mounts []cgroups.Mount
+}
+
+func NewCgroupOpts() (CgroupOpts, error) {
+	var opts CgroupOpts
+	patterns := os.Getenv("CGROUP_PATTERNS")
+	if patterns == "" {
+		patterns = defaultCgroupPatterns
+	}
+	for _, p := range strings.Split(patterns, ",") {
+		if p = strings.Trim(strings.TrimSpace(p), "/"); p != "" {
+			if _, err := filepath.Match(p, ""); err != nil {
+				return opts, fmt.Errorf("invalid cgroup pattern %q: %v", p, err)
+			}
+			opts.patterns = append(opts.patterns, p)
+		}
+	}
+
+	if cgroups.IsCgroup2UnifiedMode() {
+		opts.root = "/sys/fs/cgroup"
+		return opts, nil
+	}
+
+	// cgroup v1: discover in the memory hierarchy, read each controller
+	// in its own
+	mounts, err := cgroups.GetCgroupMounts(false)
+	if err != nil {
+		return opts, err
+	}
+	for _, m := range mounts {
+		for _, s := range m.Subsystems {
+			if s == "memory" {
+				opts.root = m.Mountpoint
+			}
+		}
+	}
+	if opts.root == "" {
+		return opts, fmt.Errorf("memory cgroup hierarchy not mounted")
+	}
+	opts.v1Mounts = mounts
+	return opts, nil
+}
+
+type Cgroup struct {
+	opts       CgroupOpts
+	containers map[string]*container.Container
+	managers   map[string]cgroups.Manager
+	closed     chan struct{}
+	lock       sync.RWMutex
+}
+
+func NewCgroup() (Connector, error) {
+	opts, err := NewCgroupOpts()
+	if err != nil {
+		return nil, err
+	}
+
+	cm := &Cgroup{
+		opts:       opts,
+		containers: make(map[string]*container.Container),
+		managers:   make(map[string]cgroups.Manager),
+		closed:     make(chan struct{}),
+	}
+
+	cm.refreshAll()
+	go func() {
+		for {
+			select {
+			case <-cm.closed:
+				return
+			case <-time.After(5 * time.Second):
+				cm.refreshAll()
+			}
+		}
+	}()
+
+	return cm, nil
+}
+
+// cgroupManager reads processes in the hierarchy cgroups are discovered
+// in, where v1 managers read them in the devices hierarchy
+type cgroupManager struct {
+	cgroups.Manager
+	dir string
+}
+
+func (m cgroupManager) GetAllPids() ([]int, error) {
+	return cgroups.GetAllPids(m.dir)
+}
+
+// newManager returns a cgroup manager for a path relative to the
+// hierarchy root, only reading the stats of existing controllers
+func (cm *Cgroup) newManager(path string) (cgroups.Manager, error) {
+	config := &configs.Cgroup{Resources: &configs.Resources{}}
+	dir := filepath.Join(cm.opts.root, path)
+	if cm.opts.v1Mounts == nil {
+		m, err := fs2.NewManager(config, dir)
+		return cgroupManager{m, dir}, err
+	}
+
+	paths := make(map[string]string)
+	for _, m := range cm.opts.v1Mounts {
+		dir := filepath.Join(m.Mountpoint, path)
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		for _, s := range m.Subsystems {
+			paths[s] = dir
+		}
+	}
+	m, err := fs.NewManager(config, paths)
+	return cgroupManager{m, dir}, err
+}
+
+// Glob cgroups matching the patterns, creating, updating and removing
+// containers
+func (cm *Cgroup) refreshAll() {
+	seen := make(map[string]bool)
+	for _, pattern := range cm.opts.patterns {
+		matches, err := filepath.Glob(filepath.Join(cm.opts.root, pattern))
+		if err != nil {
+			log.Errorf("%s (%T)", err.Error(), err)
+			continue
+		}
+		for _, dir := range matches {
+			if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+				continue
+			}
+			path, _ := filepath.Rel(cm.opts.root, dir)
+			seen[path] = true
+			if c := cm.MustGet(path); c != nil {
+				cm.refresh(c)
+			}
+		}
+	}
+
+	cm.lock.Lock()
+	for id, c := range cm.containers {
+		if !seen[id] {
+			c.SetState("exited") // stops the collector
+			delete(cm.containers, id)
+			delete(cm.managers, id)
+			log.Infof("removed dead container: %s", id)
+		}
+	}
+	cm.lock.Unlock()
+}
+
+// update a ctop container from its cgroup
+func (cm *Cgroup) refresh(c *container.Container) {
+	cm.lock.RLock()
+	cg := cm.managers[c.Id]
+	cm.lock.RUnlock()
+
+	pids, err := cg.GetAllPids()
+	if err != nil {
+		log.Warningf("failed to read processes of cgroup %s: %s", c.Id, err)
+		return
+	}
+	c.SetMeta("pids", strconv.Itoa(len(pids)))
+
+	freezer, err := cg.GetFreezerState()
+	switch {
+	case err == nil && freezer == configs.Frozen:
+		c.SetState("paused")
+	case len(pids) > 0:
+		c.SetState("running")
+	default:
+		c.SetState("exited")
+	}
+}
+
+// cgroupUnit returns the systemd unit name of a cgroup path, if any
+func cgroupUnit(path string) string {
+	base := filepath.Base(path)
+	for _, suffix := range []string{".service", ".scope", ".slice"} {
+		if strings.HasSuffix(base, suffix) {
+			return base
+		}
+	}
+	return ""
+}
+
+// cgroupName returns a display name for a cgroup path, as its base
+// name without systemd escapes, unit suffix or machine prefix
+func cgroupName(path string) string {
+	name := filepath.Base(path)
+	if unit := cgroupUnit(path); unit != "" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	// systemd escapes characters as \xNN, as "-" in machine names
+	for i := strings.Index(name, `\x`); i >= 0 && i+4 <= len(name); i = strings.Index(name, `\x`) {
+		b, err := strconv.ParseUint(name[i+2:i+4], 16, 8)
+		if err != nil {
+			break
+		}
+		name = name[:i] + string(rune(b)) + name[i+4:]
+	}
+	name = strings.TrimPrefix(name, "machine-")
+	name = strings.TrimPrefix(name, "lxc.payload.")
+	return name
+}
+
+// MustGet gets a single ctop container in the map matching a cgroup
+// path, creating one anew if not existing; nil if the cgroup cannot
+// be read
+func (cm *Cgroup) MustGet(path string) *container.Container {
+	c, ok := cm.Get(path)
+	if !ok {
+		cg, err := cm.newManager(path)
+		if err != nil {
+			log.Warningf("failed to read cgroup %s: %s", path, err)
+			return nil
+		}
+		unit := cgroupUnit(path)
+
+		collector := collector.NewCgroup(path, cg, path, unit)
+		manager := manager.NewCgroup(cg, path, unit)
+		c = container.New(path, collector, manager)
+		name := cgroupName(path)
+		c.SetMeta("name", name)
+		if len(name) > 12 {
+			name = name[:12]
+		}
+		c.SetMeta("id", name)
+		c.SetMeta("cgroup", "/"+path)
+		if unit != "" {
+			c.SetMeta("unit", unit)
+		}
+
+		cm.lock.Lock()
+		cm.containers[path] = c
+		cm.managers[path] = cg
+		cm.lock.Unlock()
+		log.Debugf("saw new container: %s", path)
+	}
+
+	return c
+}
+
+// Cgroup implements Connector
+func (cm *Cgroup) Wait() struct{} { return <-cm.closed }
+
+// Cgroup implements Connector
+func (cm *Cgroup) Get(id string) (*container.Container, bool) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	c, ok := cm.containers[id]
+	return c, ok
+}
+
+// Cgroup implements Connector
+func (cm *Cgroup) All() (containers container.Containers) {
+	cm.lock.Lock()
+	for _, c := range cm.containers {
+		containers = append(containers, c)
+	}
+	containers.Sort()
+	containers.Filter()
+	cm.lock.Unlock()
+	return containers
+}
@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Betzalel75/ctop/models"
+)
+
+// resolved block device names, by major:minor number
+var deviceNames sync.Map
+
+// blkioEntry is a per-device IO counter, as reported by both
+// Docker and runc blkio stats
+type blkioEntry struct {
+	major uint64
+	minor uint64
+	op    string
+	value uint64
+}
+
+// readBlockDevices aggregates byte and operation counters per device
+func readBlockDevices(bytes, ops []blkioEntry) []models.BlockDevice {
+	devs := make(map[string]*models.BlockDevice)
+	get := func(e blkioEntry) *models.BlockDevice {
+		name := deviceName(e.major, e.minor)
+		d, ok := devs[name]
+		if !ok {
+			d = &models.BlockDevice{Name: name}
+			devs[name] = d
+		}
+		return d
+	}
+
+	// op names are capitalized with cgroup v1, lowercase with v2
+	for _, e := range bytes {
+		switch strings.ToLower(e.op) {
+		case "read":
+			get(e).ReadBytes += int64(e.value)
+		case "write":
+			get(e).WriteBytes += int64(e.value)
+		}
+	}
+	for _, e := range ops {
+		switch strings.ToLower(e.op) {
+		case "read":
+			get(e).ReadOps += int64(e.value)
+		case "write":
+			get(e).WriteOps += int64(e.value)
+		}
+	}
+
+	var list []models.BlockDevice
+	for _, d := range devs {
+		list = append(list, *d)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// deviceName returns the kernel name of a block device from sysfs,
+// falling back to its major:minor number
+func deviceName(major, minor uint64) string {
+	num := fmt.Sprintf("%d:%d", major, minor)
+	if name, ok := deviceNames.Load(num); ok {
+		return name.(string)
+	}
+
+	name := num
+	if b, err := os.ReadFile(fmt.Sprintf("/sys/dev/block/%s/uevent", num)); err == nil {
+		for _, line := range strings.Split(string(b), "\n") {
+			if dev, ok := strings.CutPrefix(line, "DEVNAME="); ok {
+				name = dev
+				break
+			}
+		}
+	}
+	deviceNames.Store(num, name)
+	return name
+}
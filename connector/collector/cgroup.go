@@ -0,0 +1,87 @@
+//go:build linux
+// +build linux
+
+package collector
+
+import (
+	"os"
+	"strconv"
+
+	linuxproc "github.com/c9s/goprocinfo/linux"
+	"github.com/opencontainers/runc/libcontainer"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/opencontainers/runc/types"
+)
+
+// Cgroup collector, reading the stats of a plain cgroup as the Runc
+// collector does for runc containers
+type Cgroup struct {
+	*Runc
+	path string // cgroup path, relative to the hierarchy root
+	unit string // systemd unit name, if any
+}
+
+func NewCgroup(id string, cg cgroups.Manager, path, unit string) *Cgroup {
+	c := &Cgroup{
+		Runc: &Runc{id: id, interval: 1},
+		path: path,
+		unit: unit,
+	}
+	c.stats = func() (*libcontainer.Stats, error) {
+		stats, err := cg.GetStats()
+		if err != nil {
+			return nil, err
+		}
+		s := &libcontainer.Stats{CgroupStats: stats}
+		if pids, err := cg.GetAllPids(); err == nil && len(pids) > 0 {
+			s.Interfaces = netnsInterfaces(pids[0])
+		}
+		return s, nil
+	}
+	return c
+}
+
+// Logs follows the journal of the unit, or of the cgroup otherwise
+func (c *Cgroup) Logs() LogCollector {
+	if c.unit != "" {
+		return NewJournalLogs("-u", c.unit)
+	}
+	return NewJournalLogs("_SYSTEMD_CGROUP=/" + c.path)
+}
+
+// netnsInterfaces reads the interfaces of the network namespace of pid,
+// none if it shares the host namespace
+func netnsInterfaces(pid int) []*types.NetworkInterface {
+	dir := "/proc/" + strconv.Itoa(pid)
+	host, err := os.Readlink("/proc/self/ns/net")
+	if err != nil {
+		return nil
+	}
+	ns, err := os.Readlink(dir + "/ns/net")
+	if err != nil || ns == host {
+		return nil
+	}
+
+	stats, err := linuxproc.ReadNetworkStat(dir + "/net/dev")
+	if err != nil {
+		return nil
+	}
+	var ifaces []*types.NetworkInterface
+	for _, s := range stats {
+		if s.Iface == "lo" {
+			continue
+		}
+		ifaces = append(ifaces, &types.NetworkInterface{
+			Name:      s.Iface,
+			RxBytes:   s.RxBytes,
+			RxPackets: s.RxPackets,
+			RxErrors:  s.RxErrs,
+			RxDropped: s.RxDrop,
+			TxBytes:   s.TxBytes,
+			TxPackets: s.TxPackets,
+			TxErrors:  s.TxErrs,
+			TxDropped: s.TxDrop,
+		})
+	}
+	return ifaces
+}
@@ -1,6 +1,15 @@
 package collector
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
 	"github.com/Betzalel75/ctop/models"
 	api "github.com/fsouza/go-dockerclient"
 )
@@ -12,9 +21,10 @@ type Docker struct {
 	client     *api.Client
 	running    bool
 	stream     chan models.Metrics
-	done       chan bool
+	cancel     context.CancelFunc
 	lastCpu    float64
 	lastSysCpu float64
+	lastUsage  cpuUsage
 }
 
 func NewDocker(client *api.Client, id string) *Docker {
@@ -25,29 +35,34 @@ func NewDocker(client *api.Client, id string) *Docker {
 	}
 }
 
+// dockerStats is a stats record along with its raw memory.stat keys,
+// which go-dockerclient only decodes as named by cgroup v1
+type dockerStats struct {
+	api.Stats
+	memStats map[string]uint64
+}
+
 func (c *Docker) Start() {
-	c.done = make(chan bool)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
 	c.stream = make(chan models.Metrics)
-	stats := make(chan *api.Stats)
+	stats := make(chan *dockerStats)
 
 	go func() {
-		opts := api.StatsOptions{
-			ID:     c.id,
-			Stats:  stats,
-			Stream: true,
-			Done:   c.done,
+		defer close(stats)
+		if err := c.readStats(ctx, stats); err != nil && ctx.Err() == nil {
+			log.Errorf("failed to collect stats for container %s: %s", c.id, err)
 		}
-		c.client.Stats(opts)
 		c.running = false
 	}()
 
 	go func() {
 		defer close(c.stream)
 		for s := range stats {
-			c.ReadCPU(s)
-			c.ReadMem(s)
-			c.ReadNet(s)
-			c.ReadIO(s)
+			c.ReadCPU(&s.Stats)
+			c.ReadMem(&s.Stats, s.memStats)
+			c.ReadNet(&s.Stats)
+			c.ReadIO(&s.Stats)
 			c.stream <- c.Metrics
 		}
 		log.Infof("collector stopped for container: %s", c.id)
@@ -72,7 +87,79 @@ func (c *Docker) Logs() LogCollector {
 // Stop collector
 func (c *Docker) Stop() {
 	c.running = false
-	c.done <- true
+	c.cancel()
+}
+
+// readStats streams container stats until ctx is done; records are
+// decoded twice, client.Stats dropping memory.stat keys of cgroup v2
+func (c *Docker) readStats(ctx context.Context, out chan<- *dockerStats) error {
+	u, err := statsURL(c.client, c.id)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s (%d)", strings.TrimSpace(string(body)), resp.StatusCode)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		s := &dockerStats{}
+		if err := json.Unmarshal(raw, &s.Stats); err != nil {
+			return err
+		}
+		var mem struct {
+			MemoryStats struct {
+				Stats map[string]uint64 `json:"stats"`
+			} `json:"memory_stats"`
+		}
+		if err := json.Unmarshal(raw, &mem); err != nil {
+			return err
+		}
+		s.memStats = mem.MemoryStats.Stats
+
+		select {
+		case out <- s:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// statsURL builds the stats stream URL of a container for the client
+// endpoint; the transport of unix sockets ignores the host
+func statsURL(client *api.Client, id string) (string, error) {
+	path := fmt.Sprintf("/containers/%s/stats?stream=true", url.PathEscape(id))
+	u, err := url.Parse(client.Endpoint())
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "unix", "npipe":
+		return "http://unix.sock" + path, nil
+	case "tcp":
+		u.Scheme = "http"
+		if client.TLSConfig != nil {
+			u.Scheme = "https"
+		}
+	}
+	return strings.TrimRight(u.String(), "/") + path, nil
 }
 
 func (c *Docker) ReadCPU(stats *api.Stats) {
@@ -90,33 +177,65 @@ func (c *Docker) ReadCPU(stats *api.Stats) {
 	c.CPUUtil = percent(cpudiff, syscpudiff)
 	c.lastCpu = total
 	c.lastSysCpu = system
+
+	u, t := stats.CPUStats.CPUUsage, stats.CPUStats.ThrottlingData
+	c.CPUPeriods = int64(t.Periods)
+	c.CPUThrottledPeriods = int64(t.ThrottledPeriods)
+	c.CPUThrottledTime = int64(t.ThrottledTime)
+	readCPUBreakdown(&c.Metrics, &c.lastUsage, cpuUsage{
+		user:      u.UsageInUsermode,
+		system:    u.UsageInKernelmode,
+		percpu:    u.PercpuUsage,
+		host:      stats.CPUStats.SystemCPUUsage,
+		periods:   t.Periods,
+		throttled: t.ThrottledPeriods,
+	})
 	c.Pids = int(stats.PidsStats.Current)
 }
 
-func (c *Docker) ReadMem(stats *api.Stats) {
-	c.MemUsage = int64(stats.MemoryStats.Usage - stats.MemoryStats.Stats.Cache)
+func (c *Docker) ReadMem(stats *api.Stats, memStats map[string]uint64) {
+	readMemStats(&c.Metrics, memStats)
+	c.MemUsage = int64(stats.MemoryStats.Usage) - c.MemCache
 	c.MemLimit = int64(stats.MemoryStats.Limit)
 	c.MemPercent = percent(float64(c.MemUsage), float64(c.MemLimit))
 }
 
 func (c *Docker) ReadNet(stats *api.Stats) {
 	var rx, tx int64
-	for _, network := range stats.Networks {
+	ifaces := make([]models.NetInterface, 0, len(stats.Networks))
+	for name, network := range stats.Networks {
 		rx += int64(network.RxBytes)
 		tx += int64(network.TxBytes)
+		ifaces = append(ifaces, models.NetInterface{
+			Name:      name,
+			Rx:        int64(network.RxBytes),
+			Tx:        int64(network.TxBytes),
+			RxErrors:  int64(network.RxErrors),
+			TxErrors:  int64(network.TxErrors),
+			RxDropped: int64(network.RxDropped),
+			TxDropped: int64(network.TxDropped),
+		})
 	}
+	sort.Slice(ifaces, func(i, j int) bool { return ifaces[i].Name < ifaces[j].Name })
 	c.NetRx, c.NetTx = rx, tx
+	c.NetInterfaces = ifaces
 }
 
 func (c *Docker) ReadIO(stats *api.Stats) {
-	var read, write int64
-	for _, blk := range stats.BlkioStats.IOServiceBytesRecursive {
-		if blk.Op == "Read" {
-			read += int64(blk.Value)
-		}
-		if blk.Op == "Write" {
-			write += int64(blk.Value)
+	conv := func(entries []api.BlkioStatsEntry) []blkioEntry {
+		l := make([]blkioEntry, len(entries))
+		for i, e := range entries {
+			l[i] = blkioEntry{e.Major, e.Minor, e.Op, e.Value}
 		}
+		return l
+	}
+
+	var read, write int64
+	devs := readBlockDevices(conv(stats.BlkioStats.IOServiceBytesRecursive), conv(stats.BlkioStats.IOServicedRecursive))
+	for _, d := range devs {
+		read += d.ReadBytes
+		write += d.WriteBytes
 	}
 	c.IOBytesRead, c.IOBytesWrite = read, write
+	c.BlockDevices = devs
 }
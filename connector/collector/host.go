@@ -0,0 +1,66 @@
+//go:build linux
+// +build linux
+
+package collector
+
+import (
+	"github.com/Betzalel75/ctop/models"
+	linuxproc "github.com/c9s/goprocinfo/linux"
+)
+
+// Host reads system-wide usage from /proc and the filesystem of the
+// runtime data root
+type Host struct {
+	lastTotal uint64 // cumulative cpu ticks at the last reading
+	lastIdle  uint64
+}
+
+func NewHost() *Host { return &Host{} }
+
+// Read returns host usage; CPU utilization is averaged since the
+// previous reading, or since boot for the first one. dataRoot is
+// skipped if empty or unreadable
+func (h *Host) Read(dataRoot string) (models.Host, error) {
+	var host models.Host
+
+	stat, err := linuxproc.ReadStat("/proc/stat")
+	if err != nil {
+		return host, err
+	}
+	s := stat.CPUStatAll
+	idle := s.Idle + s.IOWait
+	total := s.User + s.Nice + s.System + s.Idle + s.IOWait + s.IRQ + s.SoftIRQ + s.Steal
+	if total > h.lastTotal {
+		busy := (total - h.lastTotal) - (idle - h.lastIdle)
+		host.CPUUtil = int(busy * 100 / (total - h.lastTotal))
+	}
+	h.lastTotal, h.lastIdle = total, idle
+	host.NCpus = len(stat.CPUStats)
+
+	mem, err := linuxproc.ReadMemInfo("/proc/meminfo")
+	if err != nil {
+		return host, err
+	}
+	host.MemTotal = int64(mem.MemTotal * 1024)
+	host.MemUsed = int64((mem.MemTotal - mem.MemAvailable) * 1024)
+	host.SwapTotal = int64(mem.SwapTotal * 1024)
+	host.SwapUsed = int64((mem.SwapTotal - mem.SwapFree) * 1024)
+
+	load, err := linuxproc.ReadLoadAvg("/proc/loadavg")
+	if err != nil {
+		return host, err
+	}
+	host.Load = [3]float64{load.Last1Min, load.Last5Min, load.Last15Min}
+
+	if dataRoot != "" {
+		if disk, err := linuxproc.ReadDisk(dataRoot); err == nil {
+			host.DiskPath = dataRoot
+			host.DiskTotal = int64(disk.All)
+			host.DiskUsed = int64(disk.Used)
+		} else {
+			log.Debugf("host disk usage of %s: %s", dataRoot, err)
+		}
+	}
+
+	return host, nil
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+// +build !linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/Betzalel75/ctop/models"
+)
+
+// Host reads system-wide usage, only supported on linux
+type Host struct{}
+
+func NewHost() *Host { return &Host{} }
+
+func (h *Host) Read(dataRoot string) (models.Host, error) {
+	return models.Host{}, fmt.Errorf("host stats not supported on this platform")
+}
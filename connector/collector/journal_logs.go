@@ -0,0 +1,96 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/Betzalel75/ctop/models"
+)
+
+// JournalLogs follows systemd journal entries with journalctl
+type JournalLogs struct {
+	matches []string // journalctl unit options or field matches
+	done    chan bool
+}
+
+func NewJournalLogs(matches ...string) *JournalLogs {
+	return &JournalLogs{
+		matches: matches,
+		done:    make(chan bool),
+	}
+}
+
+// journal entry fields, MESSAGE being an array of bytes for binary data
+type journalEntry struct {
+	Timestamp string          `json:"__REALTIME_TIMESTAMP"`
+	Message   json.RawMessage `json:"MESSAGE"`
+}
+
+func (l *JournalLogs) Stream() chan models.Log {
+	logCh := make(chan models.Log)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	args := append([]string{"--follow", "--lines", "20", "--output", "json", "--no-pager"}, l.matches...)
+	cmd := exec.CommandContext(ctx, "journalctl", args...)
+
+	go func() {
+		out, err := cmd.StdoutPipe()
+		if err == nil {
+			err = cmd.Start()
+		}
+		if err != nil {
+			log.Errorf("error reading journal logs: %s", err)
+			return
+		}
+		scanner := bufio.NewScanner(out)
+		for scanner.Scan() {
+			var e journalEntry
+			if json.Unmarshal(scanner.Bytes(), &e) != nil {
+				continue
+			}
+			select {
+			case logCh <- models.Log{Timestamp: e.time(), Message: e.message()}:
+			case <-ctx.Done():
+			}
+		}
+		cmd.Wait()
+		log.Infof("journal reader stopped for %v", l.matches)
+	}()
+
+	go func() {
+		<-l.done
+		cancel()
+	}()
+
+	log.Infof("journal reader started for %v", l.matches)
+	return logCh
+}
+
+func (l *JournalLogs) Stop() { l.done <- true }
+
+func (e journalEntry) time() time.Time {
+	usec, err := strconv.ParseInt(e.Timestamp, 10, 64)
+	if err != nil {
+		return time.Now()
+	}
+	return time.UnixMicro(usec)
+}
+
+func (e journalEntry) message() string {
+	var s string
+	if json.Unmarshal(e.Message, &s) == nil {
+		return s
+	}
+	var b []byte
+	var ints []int
+	if json.Unmarshal(e.Message, &ints) == nil {
+		for _, i := range ints {
+			b = append(b, byte(i))
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,193 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Betzalel75/ctop/connector/kubeapi"
+	"github.com/Betzalel75/ctop/models"
+)
+
+// metrics-server and the kubelet refresh usage every 10-15s, polling
+// more often would only repeat samples
+const kubernetesInterval = 5 * time.Second
+
+var errNoSample = errors.New("no usage sample yet")
+
+// KubernetesTarget identifies a pod container and the resources it
+// is measured against
+type KubernetesTarget struct {
+	Namespace  string
+	Pod        string
+	Container  string
+	Node       string
+	CPUs       float64 // node CPU capacity
+	MemLimit   int64   // container memory limit, or node capacity
+	MetricsAPI bool    // read usage from the metrics API, else the kubelet summary
+}
+
+// Kubernetes collector
+type Kubernetes struct {
+	models.Metrics
+	client  *kubeapi.Client
+	target  KubernetesTarget
+	running bool
+	stream  chan models.Metrics
+	cancel  context.CancelFunc
+}
+
+func NewKubernetes(client *kubeapi.Client, target KubernetesTarget) *Kubernetes {
+	return &Kubernetes{
+		Metrics: models.Metrics{},
+		client:  client,
+		target:  target,
+	}
+}
+
+func (c *Kubernetes) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.stream = make(chan models.Metrics)
+	c.running = true
+	go c.run(ctx)
+	log.Infof("collector started for container: %s/%s", c.target.Pod, c.target.Container)
+}
+
+func (c *Kubernetes) Running() bool {
+	return c.running
+}
+
+func (c *Kubernetes) Stream() chan models.Metrics {
+	return c.stream
+}
+
+func (c *Kubernetes) Logs() LogCollector {
+	return NewKubernetesLogs(c.client, c.target.Namespace, c.target.Pod, c.target.Container)
+}
+
+// Stop collector
+func (c *Kubernetes) Stop() {
+	c.running = false
+	c.cancel()
+}
+
+func (c *Kubernetes) run(ctx context.Context) {
+	defer close(c.stream)
+	for {
+		var err error
+		if c.target.MetricsAPI {
+			err = c.readMetrics(ctx)
+		} else {
+			err = c.readSummary(ctx)
+		}
+		// new pods have no sample until the next scrape, keep polling
+		if err != nil && ctx.Err() == nil {
+			log.Debugf("no metrics for container %s/%s: %s", c.target.Pod, c.target.Container, err)
+		} else if err == nil {
+			select {
+			case c.stream <- c.Metrics:
+			case <-ctx.Done():
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Infof("collector stopped for container: %s/%s", c.target.Pod, c.target.Container)
+			return
+		case <-time.After(kubernetesInterval):
+		}
+	}
+}
+
+// readMetrics reads usage from the metrics API
+func (c *Kubernetes) readMetrics(ctx context.Context) error {
+	m, err := c.client.PodMetrics(ctx, c.target.Namespace, c.target.Pod)
+	if err != nil {
+		return err
+	}
+	for _, cm := range m.Containers {
+		if cm.Name != c.target.Container {
+			continue
+		}
+		cpu, _ := kubeapi.ParseQuantity(cm.Usage["cpu"])
+		mem, _ := kubeapi.ParseQuantity(cm.Usage["memory"])
+		c.setUsage(cpu*1e9, int64(mem))
+		return nil
+	}
+	return errNoSample
+}
+
+// readSummary reads usage, along with the network counters of the pod,
+// from the kubelet summary of the node
+func (c *Kubernetes) readSummary(ctx context.Context) error {
+	s, err := c.client.NodeSummary(ctx, c.target.Node)
+	if err != nil {
+		return err
+	}
+	for _, p := range s.Pods {
+		if p.PodRef.Namespace != c.target.Namespace || p.PodRef.Name != c.target.Pod {
+			continue
+		}
+		found := false
+		for _, cs := range p.Containers {
+			if cs.Name != c.target.Container {
+				continue
+			}
+			found = true
+			var cpu float64
+			var mem int64
+			if cs.CPU != nil && cs.CPU.UsageNanoCores != nil {
+				cpu = float64(*cs.CPU.UsageNanoCores)
+			}
+			if cs.Memory != nil {
+				mem = int64(deref(cs.Memory.WorkingSetBytes))
+				c.MemRSS = int64(deref(cs.Memory.RSSBytes))
+				c.MemMajorFaults = int64(deref(cs.Memory.MajorPageFaults))
+			}
+			c.setUsage(cpu, mem)
+		}
+		// restarting or not reported yet
+		if !found {
+			return errNoSample
+		}
+
+		// network is shared by the containers of a pod
+		if p.Network != nil {
+			var rx, tx int64
+			ifaces := make([]models.NetInterface, 0, len(p.Network.Interfaces))
+			for _, i := range p.Network.Interfaces {
+				rx += int64(deref(i.RxBytes))
+				tx += int64(deref(i.TxBytes))
+				ifaces = append(ifaces, models.NetInterface{
+					Name:     i.Name,
+					Rx:       int64(deref(i.RxBytes)),
+					Tx:       int64(deref(i.TxBytes)),
+					RxErrors: int64(deref(i.RxErrors)),
+					TxErrors: int64(deref(i.TxErrors)),
+				})
+			}
+			c.NetRx, c.NetTx = rx, tx
+			c.NetInterfaces = ifaces
+		}
+		return nil
+	}
+	return errNoSample
+}
+
+// setUsage sets CPU utilization, relative to the node capacity, and
+// memory usage
+func (c *Kubernetes) setUsage(nanoCores float64, mem int64) {
+	c.NCpus = uint8(c.target.CPUs)
+	c.CPUUtil = percent(nanoCores, c.target.CPUs*1e9)
+	c.MemUsage = mem
+	c.MemLimit = c.target.MemLimit
+	c.MemPercent = percent(float64(c.MemUsage), float64(c.MemLimit))
+}
+
+func deref(v *uint64) uint64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+
+	"github.com/Betzalel75/ctop/connector/kubeapi"
+	"github.com/Betzalel75/ctop/models"
+)
+
+type KubernetesLogs struct {
+	client    *kubeapi.Client
+	namespace string
+	pod       string
+	container string
+	done      chan bool
+}
+
+func NewKubernetesLogs(client *kubeapi.Client, namespace, pod, container string) *KubernetesLogs {
+	return &KubernetesLogs{
+		client:    client,
+		namespace: namespace,
+		pod:       pod,
+		container: container,
+		done:      make(chan bool),
+	}
+}
+
+func (l *KubernetesLogs) Stream() chan models.Log {
+	logCh := make(chan models.Log)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		body, err := l.client.Logs(ctx, l.namespace, l.pod, l.container, 20)
+		if err != nil {
+			log.Errorf("error reading container logs: %s", err)
+			return
+		}
+		defer body.Close()
+
+		scanner := bufio.NewScanner(body)
+		for scanner.Scan() {
+			ts, msg := kubeapi.ParseLogLine(scanner.Text())
+			select {
+			case logCh <- models.Log{Timestamp: ts, Message: msg}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		log.Infof("log reader stopped for container: %s/%s", l.pod, l.container)
+	}()
+
+	go func() {
+		<-l.done
+		cancel()
+	}()
+
+	log.Infof("log reader started for container: %s/%s", l.pod, l.container)
+	return logCh
+}
+
+func (l *KubernetesLogs) Stop() { l.done <- true }
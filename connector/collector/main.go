@@ -33,3 +33,62 @@ func percent(val float64, total float64) int {
 	}
 	return round((val / total) * 100)
 }
+
+// cpuUsage holds the cumulative CPU time counters of a container and
+// the host total they are relative to, in nanoseconds
+type cpuUsage struct {
+	user, system uint64
+	percpu       []uint64
+	host         uint64
+	periods      uint64
+	throttled    uint64
+}
+
+// readCPUBreakdown sets the user/system split, per-core utilization and
+// throttling of m from the counter differences since last, which is
+// then replaced by cur
+func readCPUBreakdown(m *models.Metrics, last *cpuUsage, cur cpuUsage) {
+	hostdiff := float64(cur.host) - float64(last.host)
+	m.CPUUser = percent(float64(cur.user)-float64(last.user), hostdiff)
+	m.CPUSystem = percent(float64(cur.system)-float64(last.system), hostdiff)
+
+	m.PerCPU = nil
+	if n := len(cur.percpu); n > 0 && n == len(last.percpu) {
+		// each core accounts for an equal share of host time
+		m.PerCPU = make([]int, n)
+		for i, v := range cur.percpu {
+			m.PerCPU[i] = percent((float64(v)-float64(last.percpu[i]))*float64(n), hostdiff)
+		}
+	}
+
+	m.CPUThrottled = 0
+	if cur.periods > last.periods && cur.throttled >= last.throttled {
+		m.CPUThrottled = percent(float64(cur.throttled-last.throttled), float64(cur.periods-last.periods))
+	}
+	*last = cur
+}
+
+// memStat returns the value of the first key present in a cgroup
+// memory.stat map
+func memStat(stats map[string]uint64, keys ...string) int64 {
+	for _, k := range keys {
+		if v, ok := stats[k]; ok {
+			return int64(v)
+		}
+	}
+	return 0
+}
+
+// readMemStats sets the memory breakdown of m from memory.stat keys,
+// as named by cgroup v1 (hierarchical totals first) or v2
+func readMemStats(m *models.Metrics, stats map[string]uint64) {
+	m.MemRSS = memStat(stats, "total_rss", "rss", "anon")
+	m.MemCache = memStat(stats, "total_cache", "cache", "file")
+	m.MemSwap = memStat(stats, "total_swap", "swap")
+	m.MemActiveFile = memStat(stats, "total_active_file", "active_file")
+	m.MemInactiveFile = memStat(stats, "total_inactive_file", "inactive_file")
+	m.MemMajorFaults = memStat(stats, "total_pgmajfault", "pgmajfault")
+	if n := memStat(stats, "oom_kill"); n > m.OOMKills {
+		m.OOMKills = n
+	}
+}
@@ -60,20 +60,47 @@ func (c *Mock) run() {
 	c.Pids = rand.Intn(12)
 	c.IOBytesRead = rand.Int63n(8098) * c.aggression
 	c.IOBytesWrite = rand.Int63n(8098) * c.aggression
+	c.BlockDevices = []models.BlockDevice{{
+		Name:       "sda",
+		ReadBytes:  c.IOBytesRead,
+		WriteBytes: c.IOBytesWrite,
+		ReadOps:    c.IOBytesRead / 4096,
+		WriteOps:   c.IOBytesWrite / 4096,
+	}}
 
 	for {
 		c.CPUUtil += rand.Intn(2) * int(c.aggression)
 		if c.CPUUtil >= 100 {
 			c.CPUUtil = 0
 		}
+		c.CPUUser = c.CPUUtil * 3 / 4
+		c.CPUSystem = c.CPUUtil - c.CPUUser
+		c.PerCPU = make([]int, 4)
+		for i := range c.PerCPU {
+			c.PerCPU[i] = min(c.CPUUtil*4*(i+1)/10, 100)
+		}
+		c.CPUPeriods += 10
+		if c.CPUUtil > 75 {
+			c.CPUThrottledPeriods += 2
+			c.CPUThrottledTime += 2e7
+			c.CPUThrottled = 20
+		} else {
+			c.CPUThrottled = 0
+		}
 
 		c.NetTx += rand.Int63n(60) * c.aggression
 		c.NetRx += rand.Int63n(60) * c.aggression
+		c.NetInterfaces = []models.NetInterface{
+			{Name: "eth0", Rx: c.NetRx * 3 / 4, Tx: c.NetTx * 3 / 4},
+			{Name: "eth1", Rx: c.NetRx - c.NetRx*3/4, Tx: c.NetTx - c.NetTx*3/4},
+		}
 		c.MemUsage += rand.Int63n(c.MemLimit/512) * c.aggression
 		if c.MemUsage > c.MemLimit {
 			c.MemUsage = 0
 		}
 		c.MemPercent = percent(float64(c.MemUsage), float64(c.MemLimit))
+		c.MemRSS = c.MemUsage * 2 / 3
+		c.MemCache = c.MemUsage - c.MemRSS
 		c.stream <- c.Metrics
 		if c.done {
 			break
@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Betzalel75/ctop/connector/pluginapi"
+	"github.com/Betzalel75/ctop/models"
+)
+
+// Plugin collector, reading a metrics stream of a connector plugin
+type Plugin struct {
+	models.Metrics
+	client  *pluginapi.Client
+	id      string
+	running bool
+	stream  chan models.Metrics
+	cancel  context.CancelFunc
+}
+
+func NewPlugin(client *pluginapi.Client, id string) *Plugin {
+	return &Plugin{
+		Metrics: models.Metrics{},
+		client:  client,
+		id:      id,
+	}
+}
+
+func (c *Plugin) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.stream = make(chan models.Metrics)
+	c.running = true
+	go c.run(ctx)
+	log.Infof("collector started for container: %s", c.id)
+}
+
+func (c *Plugin) Running() bool {
+	return c.running
+}
+
+func (c *Plugin) Stream() chan models.Metrics {
+	return c.stream
+}
+
+func (c *Plugin) Logs() LogCollector {
+	return NewPluginLogs(c.client, c.id)
+}
+
+// Stop collector
+func (c *Plugin) Stop() {
+	c.running = false
+	c.cancel()
+}
+
+func (c *Plugin) run(ctx context.Context) {
+	defer close(c.stream)
+	s, err := c.client.Stream(ctx, pluginapi.MethodMetrics, pluginapi.ContainerParams{Container: c.id})
+	if err != nil {
+		log.Errorf("cannot read metrics of container %s: %s", c.id, err)
+		c.running = false
+		return
+	}
+
+	for raw := range s.C {
+		var m pluginapi.Metrics
+		if err := json.Unmarshal(raw, &m); err != nil {
+			log.Warningf("invalid metrics of container %s: %s", c.id, err)
+			continue
+		}
+		c.read(m)
+		select {
+		case c.stream <- c.Metrics:
+		case <-ctx.Done():
+		}
+	}
+	if err := s.Err(); err != nil {
+		log.Errorf("metrics stream of container %s ended: %s", c.id, err)
+	}
+	c.running = false
+	log.Infof("collector stopped for container: %s", c.id)
+}
+
+func (c *Plugin) read(m pluginapi.Metrics) {
+	c.NCpus = uint8(m.CPUs)
+	c.CPUUtil = m.CPUUtil
+	c.CPUUser = m.CPUUser
+	c.CPUSystem = m.CPUSystem
+	c.PerCPU = m.PerCPU
+	c.MemUsage = m.MemUsage
+	c.MemLimit = m.MemLimit
+	c.MemPercent = percent(float64(m.MemUsage), float64(m.MemLimit))
+	c.MemRSS = m.MemRSS
+	c.MemCache = m.MemCache
+	c.MemSwap = m.MemSwap
+	c.NetRx = m.NetRx
+	c.NetTx = m.NetTx
+	c.IOBytesRead = m.IOBytesRead
+	c.IOBytesWrite = m.IOBytesWrite
+	c.Pids = m.Pids
+
+	c.NetInterfaces = nil
+	for _, i := range m.NetInterfaces {
+		c.NetInterfaces = append(c.NetInterfaces, models.NetInterface{Name: i.Name, Rx: i.Rx, Tx: i.Tx})
+	}
+	c.BlockDevices = nil
+	for _, d := range m.BlockDevices {
+		c.BlockDevices = append(c.BlockDevices, models.BlockDevice{Name: d.Name, ReadBytes: d.ReadBytes, WriteBytes: d.WriteBytes})
+	}
+}
@@ -0,0 +1,60 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Betzalel75/ctop/connector/pluginapi"
+	"github.com/Betzalel75/ctop/models"
+)
+
+type PluginLogs struct {
+	client *pluginapi.Client
+	id     string
+	done   chan bool
+}
+
+func NewPluginLogs(client *pluginapi.Client, id string) *PluginLogs {
+	return &PluginLogs{
+		client: client,
+		id:     id,
+		done:   make(chan bool),
+	}
+}
+
+func (l *PluginLogs) Stream() chan models.Log {
+	logCh := make(chan models.Log)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		s, err := l.client.Stream(ctx, pluginapi.MethodLogs, pluginapi.ContainerParams{Container: l.id})
+		if err != nil {
+			log.Errorf("error reading container logs: %s", err)
+			return
+		}
+		for raw := range s.C {
+			var line pluginapi.LogLine
+			if err := json.Unmarshal(raw, &line); err != nil {
+				continue
+			}
+			select {
+			case logCh <- models.Log{Timestamp: line.Time, Message: line.Message}:
+			case <-ctx.Done():
+			}
+		}
+		if err := s.Err(); err != nil {
+			log.Errorf("error reading container logs: %s", err)
+		}
+		log.Infof("log reader stopped for container: %s", l.id)
+	}()
+
+	go func() {
+		<-l.done
+		cancel()
+	}()
+
+	log.Infof("log reader started for container: %s", l.id)
+	return logCh
+}
+
+func (l *PluginLogs) Stop() { l.done <- true }
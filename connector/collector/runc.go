@@ -17,20 +17,21 @@ import (
 type Runc struct {
 	models.Metrics
 	id         string
-	libc       libcontainer.Container
+	stats      func() (*libcontainer.Stats, error)
 	stream     chan models.Metrics
 	done       bool
 	running    bool
 	interval   int // collection interval, in seconds
 	lastCpu    float64
 	lastSysCpu float64
+	lastUsage  cpuUsage
 }
 
 func NewRunc(libc libcontainer.Container) *Runc {
 	c := &Runc{
 		Metrics:  models.Metrics{},
 		id:       libc.ID(),
-		libc:     libc,
+		stats:    libc.Stats,
 		interval: 1,
 	}
 	return c
@@ -65,7 +66,7 @@ func (c *Runc) run() {
 	log.Debugf("collector started for container: %s", c.id)
 
 	for {
-		stats, err := c.libc.Stats()
+		stats, err := c.stats()
 		if err != nil {
 			log.Errorf("failed to collect stats for container %s:\n%s", c.id, err)
 			break
@@ -74,6 +75,7 @@ func (c *Runc) run() {
 		c.ReadCPU(stats.CgroupStats)
 		c.ReadMem(stats.CgroupStats)
 		c.ReadNet(stats.Interfaces)
+		c.ReadIO(stats.CgroupStats)
 
 		c.stream <- c.Metrics
 		if c.done {
@@ -98,10 +100,24 @@ func (c *Runc) ReadCPU(stats *cgroups.Stats) {
 	c.CPUUtil = percent(cpudiff, syscpudiff)
 	c.lastCpu = total
 	c.lastSysCpu = system
+
+	t := stats.CpuStats.ThrottlingData
+	c.CPUPeriods = int64(t.Periods)
+	c.CPUThrottledPeriods = int64(t.ThrottledPeriods)
+	c.CPUThrottledTime = int64(t.ThrottledTime)
+	readCPUBreakdown(&c.Metrics, &c.lastUsage, cpuUsage{
+		user:      u.UsageInUsermode,
+		system:    u.UsageInKernelmode,
+		percpu:    u.PercpuUsage,
+		host:      uint64(system),
+		periods:   t.Periods,
+		throttled: t.ThrottledPeriods,
+	})
 	c.Pids = int(stats.PidsStats.Current)
 }
 
 func (c *Runc) ReadMem(stats *cgroups.Stats) {
+	readMemStats(&c.Metrics, stats.MemoryStats.Stats)
 	c.MemUsage = int64(stats.MemoryStats.Usage.Usage)
 	c.MemLimit = int64(stats.MemoryStats.Usage.Limit)
 	if c.MemLimit > sysMemTotal && sysMemTotal > 0 {
@@ -112,22 +128,39 @@ func (c *Runc) ReadMem(stats *cgroups.Stats) {
 
 func (c *Runc) ReadNet(interfaces []*types.NetworkInterface) {
 	var rx, tx int64
+	ifaces := make([]models.NetInterface, 0, len(interfaces))
 	for _, network := range interfaces {
 		rx += int64(network.RxBytes)
 		tx += int64(network.TxBytes)
+		ifaces = append(ifaces, models.NetInterface{
+			Name:      network.Name,
+			Rx:        int64(network.RxBytes),
+			Tx:        int64(network.TxBytes),
+			RxErrors:  int64(network.RxErrors),
+			TxErrors:  int64(network.TxErrors),
+			RxDropped: int64(network.RxDropped),
+			TxDropped: int64(network.TxDropped),
+		})
 	}
 	c.NetRx, c.NetTx = rx, tx
+	c.NetInterfaces = ifaces
 }
 
 func (c *Runc) ReadIO(stats *cgroups.Stats) {
-	var read, write int64
-	for _, blk := range stats.BlkioStats.IoServiceBytesRecursive {
-		if blk.Op == "Read" {
-			read = int64(blk.Value)
-		}
-		if blk.Op == "Write" {
-			write = int64(blk.Value)
+	conv := func(entries []cgroups.BlkioStatEntry) []blkioEntry {
+		l := make([]blkioEntry, len(entries))
+		for i, e := range entries {
+			l[i] = blkioEntry{e.Major, e.Minor, e.Op, e.Value}
 		}
+		return l
+	}
+
+	var read, write int64
+	devs := readBlockDevices(conv(stats.BlkioStats.IoServiceBytesRecursive), conv(stats.BlkioStats.IoServicedRecursive))
+	for _, d := range devs {
+		read += d.ReadBytes
+		write += d.WriteBytes
 	}
 	c.IOBytesRead, c.IOBytesWrite = read, write
+	c.BlockDevices = devs
 }
@@ -2,6 +2,8 @@ package connector
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -12,6 +14,7 @@ import (
 	"github.com/Betzalel75/ctop/connector/collector"
 	"github.com/Betzalel75/ctop/connector/manager"
 	"github.com/Betzalel75/ctop/container"
+	"github.com/Betzalel75/ctop/models"
 	api "github.com/fsouza/go-dockerclient"
 )
 
@@ -25,9 +28,20 @@ var actionToStatus = map[string]string{
 	"unpause": "running",
 }
 
+// a container dying crashLoopRestarts times within crashLoopWindow is
+// marked as crash-looping
+const (
+	crashLoopRestarts = 3
+	crashLoopWindow   = 5 * time.Minute
+)
+
+// containers are refreshed after a health probe at most once per
+// probeRefreshInterval, health status changes being refreshed at once
+const probeRefreshInterval = 10 * time.Second
+
 type StatusUpdate struct {
 	Cid    string
-	Field  string // "status" or "health"
+	Field  string // "status", "health", "oom" or "die"
 	Status string
 }
 
@@ -37,6 +51,11 @@ type Docker struct {
 	needsRefresh chan string // container IDs requiring refresh
 	statuses     chan StatusUpdate
 	closed       chan struct{}
+	dies         map[string][]time.Time // recent die events, by container ID
+	probeCmds    map[string]string      // health probe commands, by container ID
+	probes       map[string]string      // exec IDs of running probes, by container ID
+	probeRefresh map[string]time.Time   // last refresh after a probe, by container ID
+	events       *EventLog
 	lock         sync.RWMutex
 }
 
@@ -52,6 +71,11 @@ func NewDocker() (Connector, error) {
 		needsRefresh: make(chan string, 60),
 		statuses:     make(chan StatusUpdate, 60),
 		closed:       make(chan struct{}),
+		dies:         make(map[string][]time.Time),
+		probeCmds:    make(map[string]string),
+		probes:       make(map[string]string),
+		probeRefresh: make(map[string]time.Time),
+		events:       NewEventLog(eventLogSize),
 		lock:         sync.RWMutex{},
 	}
 
@@ -79,18 +103,68 @@ func (cm *Docker) Wait() struct{} { return <-cm.closed }
 func (cm *Docker) GetClient() *api.Client {
 	return cm.client
 }
+
+// Docker implements EventSource
+func (cm *Docker) Events() *EventLog { return cm.events }
+
+// Docker implements HostSource
+func (cm *Docker) Daemon() (models.Daemon, error) {
+	info, err := cm.client.Info()
+	if err != nil {
+		return models.Daemon{}, err
+	}
+	return models.Daemon{
+		Name:       info.Name,
+		Version:    info.ServerVersion,
+		Driver:     info.Driver,
+		OS:         info.OperatingSystem,
+		Kernel:     info.KernelVersion,
+		RootDir:    info.DockerRootDir,
+		NCpus:      info.NCPU,
+		MemTotal:   info.MemTotal,
+		Containers: info.Containers,
+		Running:    info.ContainersRunning,
+		Images:     info.Images,
+		Local:      cm.local(info.Name),
+	}, nil
+}
+
+// local returns whether the daemon named name runs on this machine: it
+// listens on a unix socket and has the hostname of this machine, unlike
+// Docker Desktop VMs or daemons seen from inside a container
+func (cm *Docker) local(name string) bool {
+	if !strings.HasPrefix(cm.client.Endpoint(), "unix://") {
+		return false
+	}
+	hostname, err := os.Hostname()
+	return err == nil && hostname == name
+}
+
 // Docker events watcher
 func (cm *Docker) watchEvents() {
 	log.Info("docker event listener starting")
 	events := make(chan *api.APIEvents)
 	opts := api.EventsOptions{Filters: map[string][]string{
-		"type":  {"container"},
-		"event": {"create", "start", "health_status", "pause", "unpause", "stop", "die", "destroy"},
+		"type": {"container", "image", "network", "volume"},
 	},
 	}
 	cm.client.AddEventListenerWithOptions(opts, events)
 
 	for e := range events {
+		probe, refresh := cm.probeEvent(e)
+		ev := eventFormat(e)
+		ev.Probe = probe
+		cm.events.Add(ev)
+		if probe {
+			if refresh {
+				cm.needsRefresh <- e.ID // probe log, failing streak
+			}
+			continue
+		}
+		if e.Type != "container" {
+			continue
+		}
+
 		actionName := e.Action
 		switch actionName {
 		// most frequent event is a health checks
@@ -101,6 +175,7 @@ func (cm *Docker) watchEvents() {
 				log.Debugf("handling docker event: action=health_status id=%s %s", e.ID, healthStatus)
 			}
 			cm.statuses <- StatusUpdate{e.ID, "health", healthStatus}
+			cm.needsRefresh <- e.ID // probe log
 		case "create":
 			if log.IsEnabledFor(logging.DEBUG) {
 				log.Debugf("handling docker event: action=create id=%s", e.ID)
@@ -111,6 +186,23 @@ func (cm *Docker) watchEvents() {
 				log.Debugf("handling docker event: action=destroy id=%s", e.ID)
 			}
 			cm.delByID(e.ID)
+		case "oom":
+			if log.IsEnabledFor(logging.DEBUG) {
+				log.Debugf("handling docker event: action=oom id=%s", e.ID)
+			}
+			cm.statuses <- StatusUpdate{e.ID, "oom", ""}
+		case "die":
+			if log.IsEnabledFor(logging.DEBUG) {
+				log.Debugf("handling docker event: action=die id=%s", e.ID)
+			}
+			cm.statuses <- StatusUpdate{e.ID, "die", actionToStatus[actionName]}
+			cm.needsRefresh <- e.ID // exit code, restart count
+		case "start":
+			if log.IsEnabledFor(logging.DEBUG) {
+				log.Debugf("handling docker event: action=start id=%s", e.ID)
+			}
+			cm.statuses <- StatusUpdate{e.ID, "status", actionToStatus[actionName]}
+			cm.needsRefresh <- e.ID
 		default:
 			// check if this action changes status e.g. start -> running
 			status := actionToStatus[actionName]
@@ -126,6 +218,25 @@ func (cm *Docker) watchEvents() {
 	close(cm.closed)
 }
 
+func eventFormat(e *api.APIEvents) models.Event {
+	id := e.Actor.ID
+	if id == "" {
+		id = e.ID
+	}
+	t := time.Unix(0, e.TimeNano)
+	if e.TimeNano == 0 {
+		t = time.Unix(e.Time, 0)
+	}
+	return models.Event{
+		Time:       t,
+		Type:       e.Type,
+		Action:     e.Action,
+		ID:         id,
+		Name:       e.Actor.Attributes["name"],
+		Attributes: e.Actor.Attributes,
+	}
+}
+
 func portsFormat(ports map[api.Port][]api.PortBinding) string {
 	var exposed []string
 	var published []string
@@ -191,10 +302,98 @@ func (cm *Docker) refresh(c *container.Container) {
 	c.SetMeta("created", insp.Created.Format("Mon Jan 02 15:04:05 2006"))
 	c.SetMeta("uptime", calcUptime(insp))
 	c.SetMeta("health", insp.State.Health.Status)
+	c.SetMeta("failing streak", strconv.Itoa(insp.State.Health.FailingStreak))
+	c.SetHealthLog(healthChecks(insp.State.Health.Log))
+	cm.setProbeCmd(c.Id, probeCmd(insp.Config))
+	c.SetMeta("oomkilled", strconv.FormatBool(insp.State.OOMKilled))
+	c.SetMeta("restarts", strconv.Itoa(insp.RestartCount))
+	c.SetMeta("exit code", strconv.Itoa(insp.State.ExitCode))
+	c.SetMeta("error", insp.State.Error)
+	if !insp.State.FinishedAt.IsZero() {
+		c.SetMeta("finished", insp.State.FinishedAt.Local().Format("Mon Jan 02 15:04:05 2006"))
+	}
 	c.SetMeta("[ENV-VAR]", strings.Join(insp.Config.Env, ";"))
 	c.SetState(insp.State.Status)
 }
 
+// healthChecks converts health probe results for the single view
+func healthChecks(checks []api.HealthCheck) []models.HealthCheck {
+	out := make([]models.HealthCheck, len(checks))
+	for i, c := range checks {
+		out[i] = models.HealthCheck{Start: c.Start, End: c.End, ExitCode: c.ExitCode, Output: c.Output}
+	}
+	return out
+}
+
+// probeCmd returns the command line of the health probe of a container,
+// as named by exec events, or "" if it has no health check
+func probeCmd(cfg *api.Config) string {
+	if cfg == nil || cfg.Healthcheck == nil || len(cfg.Healthcheck.Test) < 2 {
+		return ""
+	}
+	test := cfg.Healthcheck.Test
+	switch test[0] {
+	case "CMD":
+		return strings.Join(test[1:], " ")
+	case "CMD-SHELL":
+		shell := "/bin/sh -c"
+		if len(cfg.Shell) > 0 {
+			shell = strings.Join(cfg.Shell, " ")
+		}
+		return shell + " " + strings.Join(test[1:], " ")
+	}
+	return ""
+}
+
+func (cm *Docker) setProbeCmd(id, cmd string) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	if cmd == "" {
+		delete(cm.probeCmds, id)
+		delete(cm.probes, id)
+		delete(cm.probeRefresh, id)
+	} else {
+		cm.probeCmds[id] = cmd
+	}
+}
+
+// probeEvent returns whether e is an exec event of a health probe, and
+// whether the container should be refreshed as the probe ended. Docker
+// only reports health status changes, so the end of each probe is
+// watched to follow the probe log
+func (cm *Docker) probeEvent(e *api.APIEvents) (probe, refresh bool) {
+	if e.Type != "container" {
+		return false, false
+	}
+	execID := e.Actor.Attributes["execID"]
+	if execID == "" {
+		return false, false
+	}
+
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	switch {
+	case strings.HasPrefix(e.Action, "exec_create: "):
+		cmd := strings.TrimPrefix(e.Action, "exec_create: ")
+		if c, ok := cm.probeCmds[e.ID]; ok && c == cmd {
+			cm.probes[e.ID] = execID
+			return true, false
+		}
+	case strings.HasPrefix(e.Action, "exec_start: "):
+		return cm.probes[e.ID] == execID, false
+	case e.Action == "exec_die":
+		if cm.probes[e.ID] == execID {
+			delete(cm.probes, e.ID)
+			if time.Since(cm.probeRefresh[e.ID]) < probeRefreshInterval {
+				return true, false
+			}
+			cm.probeRefresh[e.ID] = time.Now()
+			return true, true
+		}
+	}
+	return false, false
+}
+
 func (cm *Docker) inspect(id string) (insp *api.Container, found bool, failed bool) {
 	c, err := cm.client.InspectContainerWithOptions(api.InspectContainerOptions{ID: id})
 	if err != nil {
@@ -247,6 +446,8 @@ func (cm *Docker) Loop() {
 }
 
 func (cm *Docker) LoopStatuses() {
+	expire := time.NewTicker(30 * time.Second)
+	defer expire.Stop()
 	for {
 		select {
 		case statusUpdate := <-cm.statuses:
@@ -254,16 +455,52 @@ func (cm *Docker) LoopStatuses() {
 			if c != nil {
 				if statusUpdate.Field == "health" {
 					c.SetMeta("health", statusUpdate.Status)
+				} else if statusUpdate.Field == "oom" {
+					// count OOM kills seen since ctop started
+					n, _ := strconv.Atoi(c.GetMeta("oomkills"))
+					c.SetMeta("oomkills", strconv.Itoa(n+1))
+				} else if statusUpdate.Field == "die" {
+					cm.dies[c.Id] = append(cm.dies[c.Id], time.Now())
+					cm.checkCrashLoop(c)
+					c.SetState(statusUpdate.Status)
 				} else {
 					c.SetState(statusUpdate.Status)
 				}
 			}
+		case <-expire.C:
+			for id := range cm.dies {
+				if c, ok := cm.Get(id); ok {
+					cm.checkCrashLoop(c)
+				} else {
+					delete(cm.dies, id)
+				}
+			}
 		case <-cm.closed:
 			return
 		}
 	}
 }
 
+// checkCrashLoop drops die events of c older than crashLoopWindow and
+// updates its "crashloop" meta from the remaining count
+func (cm *Docker) checkCrashLoop(c *container.Container) {
+	since := time.Now().Add(-crashLoopWindow)
+	dies := cm.dies[c.Id]
+	for len(dies) > 0 && dies[0].Before(since) {
+		dies = dies[1:]
+	}
+
+	looping := len(dies) >= crashLoopRestarts
+	if len(dies) == 0 {
+		delete(cm.dies, c.Id)
+	} else {
+		cm.dies[c.Id] = dies
+	}
+	if looping != (c.GetMeta("crashloop") == "true") {
+		c.SetMeta("crashloop", strconv.FormatBool(looping))
+	}
+}
+
 // MustGet gets a single container, creating one anew if not existing
 func (cm *Docker) MustGet(id string) *container.Container {
 	c, ok := cm.Get(id)
@@ -294,6 +531,9 @@ func (cm *Docker) Get(id string) (*container.Container, bool) {
 func (cm *Docker) delByID(id string) {
 	cm.lock.Lock()
 	delete(cm.containers, id)
+	delete(cm.probeCmds, id)
+	delete(cm.probes, id)
+	delete(cm.probeRefresh, id)
 	cm.lock.Unlock()
 	log.Infof("removed dead container: %s", id)
 }
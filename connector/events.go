@@ -0,0 +1,60 @@
+package connector
+
+import (
+	"sync"
+
+	"github.com/Betzalel75/ctop/models"
+)
+
+// maximum number of events kept by a connector
+const eventLogSize = 1000
+
+// EventSource is implemented by connectors keeping a log of runtime events
+type EventSource interface {
+	Events() *EventLog
+}
+
+// EventLog is a bounded buffer of the most recent runtime events
+type EventLog struct {
+	events []models.Event
+	next   int // index of the oldest event, once full
+	lock   sync.RWMutex
+}
+
+func NewEventLog(size int) *EventLog {
+	return &EventLog{events: make([]models.Event, 0, size)}
+}
+
+// Add appends an event, dropping the oldest one if the log is full
+func (l *EventLog) Add(e models.Event) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if len(l.events) < cap(l.events) {
+		l.events = append(l.events, e)
+		return
+	}
+	l.events[l.next] = e
+	l.next = (l.next + 1) % len(l.events)
+}
+
+// All returns a copy of logged events, oldest first
+func (l *EventLog) All() []models.Event {
+	return l.filter(func(models.Event) bool { return true })
+}
+
+// ForContainer returns logged events related to a container, oldest first
+func (l *EventLog) ForContainer(id string) []models.Event {
+	return l.filter(func(e models.Event) bool { return e.ContainerID() == id })
+}
+
+func (l *EventLog) filter(keep func(models.Event) bool) (a []models.Event) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	n := len(l.events)
+	for i := 0; i < n; i++ {
+		if e := l.events[(l.next+i)%n]; keep(e) {
+			a = append(a, e)
+		}
+	}
+	return a
+}
@@ -0,0 +1,247 @@
+package connector
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Betzalel75/ctop/connector/collector"
+	"github.com/Betzalel75/ctop/connector/manager"
+	"github.com/Betzalel75/ctop/connector/pluginapi"
+	"github.com/Betzalel75/ctop/container"
+	"github.com/Betzalel75/ctop/models"
+)
+
+func init() { enabled["exec"] = NewExec }
+
+// interval between container listings, events trigger one at once
+const execRefresh = 5 * time.Second
+
+type ExecOpts struct {
+	command []string // plugin command line
+}
+
+func NewExecOpts() (ExecOpts, error) {
+	var opts ExecOpts
+	opts.command = strings.Fields(os.Getenv("EXEC_PLUGIN"))
+	if len(opts.command) == 0 {
+		return opts, fmt.Errorf("EXEC_PLUGIN must be set to a plugin command")
+	}
+	return opts, nil
+}
+
+// Exec adapts a connector plugin, a program speaking the pluginapi
+// protocol over its stdin and stdout
+type Exec struct {
+	opts         ExecOpts
+	client       *pluginapi.Client
+	containers   map[string]*container.Container
+	events       *EventLog
+	needsRefresh chan struct{}
+	closed       chan struct{}
+	lock         sync.RWMutex
+}
+
+func NewExec() (Connector, error) {
+	opts, err := NewExecOpts()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := pluginapi.Start(opts.command, pluginLogger())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var hello pluginapi.Hello
+	if err := client.Call(ctx, pluginapi.MethodHello, pluginapi.HelloParams{Version: pluginapi.Version}, &hello); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("plugin handshake failed: %v", err)
+	}
+	if hello.Version != pluginapi.Version {
+		client.Close()
+		return nil, fmt.Errorf("plugin %s speaks protocol version %d, expected %d", hello.Name, hello.Version, pluginapi.Version)
+	}
+	log.Infof("connected to plugin %s", hello.Name)
+
+	cm := &Exec{
+		opts:         opts,
+		client:       client,
+		containers:   make(map[string]*container.Container),
+		events:       NewEventLog(eventLogSize),
+		needsRefresh: make(chan struct{}, 1),
+		closed:       make(chan struct{}),
+	}
+
+	go func() {
+		<-client.Done()
+		log.Errorf("%s", client.Err())
+		close(cm.closed)
+	}()
+
+	cm.refreshAll()
+	go cm.watchEvents()
+	go cm.Loop()
+
+	return cm, nil
+}
+
+// pluginLogger returns a writer logging each line written to it, for
+// the plugin stderr
+func pluginLogger() io.Writer {
+	r, w := io.Pipe()
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			log.Infof("plugin: %s", scanner.Text())
+		}
+	}()
+	return w
+}
+
+// Exec implements EventSource
+func (cm *Exec) Events() *EventLog { return cm.events }
+
+// watchEvents logs plugin events, refreshing containers on container
+// events; plugins not implementing events are only polled
+func (cm *Exec) watchEvents() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-cm.closed
+		cancel()
+	}()
+
+	s, err := cm.client.Stream(ctx, pluginapi.MethodEvents, nil)
+	if err != nil {
+		return
+	}
+	for raw := range s.C {
+		var e pluginapi.Event
+		if err := json.Unmarshal(raw, &e); err != nil {
+			log.Warningf("invalid plugin event: %s", err)
+			continue
+		}
+		cm.events.Add(models.Event{
+			Time:       e.Time,
+			Type:       e.Type,
+			Action:     e.Action,
+			ID:         e.ID,
+			Name:       e.Name,
+			Attributes: e.Attributes,
+		})
+		if e.Type == "container" {
+			cm.refresh()
+		}
+	}
+	if err := s.Err(); pluginapi.IsNotImplemented(err) {
+		log.Infof("plugin does not implement events, polling containers")
+	} else if err != nil {
+		log.Warningf("plugin event stream ended: %s", err)
+	}
+}
+
+// refresh queues a container listing, unless one is already queued
+func (cm *Exec) refresh() {
+	select {
+	case cm.needsRefresh <- struct{}{}:
+	default:
+	}
+}
+
+func (cm *Exec) Loop() {
+	for {
+		select {
+		case <-cm.closed:
+			return
+		case <-cm.needsRefresh:
+		case <-time.After(execRefresh):
+		}
+		cm.refreshAll()
+	}
+}
+
+// List plugin containers, creating, updating and removing containers
+func (cm *Exec) refreshAll() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var list []pluginapi.Container
+	if err := cm.client.Call(ctx, pluginapi.MethodList, nil, &list); err != nil {
+		log.Errorf("cannot list plugin containers: %s", err)
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, pc := range list {
+		seen[pc.ID] = true
+		c := cm.MustGet(pc.ID)
+		for k, v := range pc.Meta {
+			c.SetMeta(k, v)
+		}
+		name := pc.Name
+		if name == "" {
+			name = pc.ID
+		}
+		c.SetMeta("name", name)
+		c.SetState(pc.State)
+	}
+
+	cm.lock.Lock()
+	for id, c := range cm.containers {
+		if !seen[id] {
+			c.SetState("exited") // stops the collector
+			delete(cm.containers, id)
+			log.Infof("removed dead container: %s", id)
+		}
+	}
+	cm.lock.Unlock()
+}
+
+// MustGet gets a single ctop container in the map matching a plugin
+// container, creating one anew if not existing
+func (cm *Exec) MustGet(id string) *container.Container {
+	c, ok := cm.Get(id)
+	if !ok {
+		collector := collector.NewPlugin(cm.client, id)
+		manager := manager.NewPlugin(cm.client, id)
+		c = container.New(id, collector, manager)
+
+		cm.lock.Lock()
+		cm.containers[id] = c
+		cm.lock.Unlock()
+		log.Debugf("saw new container: %s", id)
+	}
+
+	return c
+}
+
+// Exec implements Connector
+func (cm *Exec) Wait() struct{} { return <-cm.closed }
+
+// Exec implements Connector
+func (cm *Exec) Get(id string) (*container.Container, bool) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	c, ok := cm.containers[id]
+	return c, ok
+}
+
+// Exec implements Connector
+func (cm *Exec) All() (containers container.Containers) {
+	cm.lock.Lock()
+	for _, c := range cm.containers {
+		containers = append(containers, c)
+	}
+	containers.Sort()
+	containers.Filter()
+	cm.lock.Unlock()
+	return containers
+}
@@ -0,0 +1,9 @@
+package connector
+
+import "github.com/Betzalel75/ctop/models"
+
+// HostSource is implemented by connectors able to describe the daemon
+// running the containers
+type HostSource interface {
+	Daemon() (models.Daemon, error)
+}
@@ -0,0 +1,193 @@
+package kubeapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StatusError is an error response of the API server
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s (%d)", e.Message, e.Code)
+}
+
+// IsNotFound reports whether err is a 404 response of the API server
+func IsNotFound(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.Code == http.StatusNotFound
+}
+
+// Client performs requests against the API server of a Config
+type Client struct {
+	config *Config
+	http   *http.Client
+}
+
+func NewClient(config *Config) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config.TLS
+	return &Client{
+		config: config,
+		http:   &http.Client{Transport: transport},
+	}
+}
+
+// Config returns the config the client was created with
+func (c *Client) Config() *Config { return c.config }
+
+func (c *Client) authorize(h http.Header) {
+	switch {
+	case c.config.Token != "":
+		h.Set("Authorization", "Bearer "+c.config.Token)
+	case c.config.Username != "":
+		req := http.Request{Header: h}
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+}
+
+// do performs a request, returning the response body to be closed by
+// the caller
+func (c *Client) do(ctx context.Context, method, path string, query url.Values) (io.ReadCloser, error) {
+	u := c.config.Server + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	c.authorize(req.Header)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		var status Status
+		msg := strings.TrimSpace(string(body))
+		if json.Unmarshal(body, &status) == nil && status.Message != "" {
+			msg = status.Message
+		}
+		return nil, &StatusError{Code: resp.StatusCode, Message: fmt.Sprintf("%s %s: %s", method, path, msg)}
+	}
+	return resp.Body, nil
+}
+
+// get decodes the JSON response of a GET request into v
+func (c *Client) get(ctx context.Context, path string, query url.Values, v any) error {
+	body, err := c.do(ctx, http.MethodGet, path, query)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(v)
+}
+
+func podPath(namespace, name string) string {
+	return fmt.Sprintf("/api/v1/namespaces/%s/pods/%s", url.PathEscape(namespace), url.PathEscape(name))
+}
+
+// ListPods lists the pods of a namespace, or of all namespaces if empty
+func (c *Client) ListPods(ctx context.Context, namespace string) ([]Pod, error) {
+	path := "/api/v1/pods"
+	if namespace != "" {
+		path = fmt.Sprintf("/api/v1/namespaces/%s/pods", url.PathEscape(namespace))
+	}
+	var list PodList
+	if err := c.get(ctx, path, nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// GetPod returns the full document of a pod
+func (c *Client) GetPod(ctx context.Context, namespace, name string) (map[string]any, error) {
+	var pod map[string]any
+	err := c.get(ctx, podPath(namespace, name), nil, &pod)
+	return pod, err
+}
+
+// DeletePod deletes a pod, to be recreated by its controller if any
+func (c *Client) DeletePod(ctx context.Context, namespace, name string) error {
+	body, err := c.do(ctx, http.MethodDelete, podPath(namespace, name), nil)
+	if err != nil {
+		return err
+	}
+	return body.Close()
+}
+
+// ListNodes lists the nodes of the cluster
+func (c *Client) ListNodes(ctx context.Context) ([]Node, error) {
+	var list NodeList
+	if err := c.get(ctx, "/api/v1/nodes", nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// HasMetricsAPI reports whether the metrics API is served, by
+// metrics-server or an equivalent
+func (c *Client) HasMetricsAPI(ctx context.Context) (bool, error) {
+	var group map[string]any
+	err := c.get(ctx, "/apis/metrics.k8s.io/v1beta1", nil, &group)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// PodMetrics returns the latest usage sample of a pod from the metrics API
+func (c *Client) PodMetrics(ctx context.Context, namespace, name string) (*PodMetrics, error) {
+	var m PodMetrics
+	path := fmt.Sprintf("/apis/metrics.k8s.io/v1beta1/namespaces/%s/pods/%s", url.PathEscape(namespace), url.PathEscape(name))
+	if err := c.get(ctx, path, nil, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// NodeSummary returns the kubelet stats summary of a node
+func (c *Client) NodeSummary(ctx context.Context, node string) (*Summary, error) {
+	var s Summary
+	path := fmt.Sprintf("/api/v1/nodes/%s/proxy/stats/summary", url.PathEscape(node))
+	if err := c.get(ctx, path, nil, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Logs follows the logs of a pod container from its last tail lines,
+// each prefixed with an RFC3339 timestamp
+func (c *Client) Logs(ctx context.Context, namespace, pod, container string, tail int) (io.ReadCloser, error) {
+	query := url.Values{
+		"container":  {container},
+		"follow":     {"true"},
+		"timestamps": {"true"},
+		"tailLines":  {strconv.Itoa(tail)},
+	}
+	return c.do(ctx, http.MethodGet, podPath(namespace, pod)+"/log", query)
+}
+
+// ParseLogLine splits a timestamped log line into its time and message
+func ParseLogLine(line string) (time.Time, string) {
+	ts, msg, ok := strings.Cut(line, " ")
+	if t, err := time.Parse(time.RFC3339Nano, ts); ok && err == nil {
+		return t, msg
+	}
+	return time.Now(), line
+}
@@ -0,0 +1,228 @@
+// Package kubeapi performs the few Kubernetes API requests needed by the
+// kubernetes connector, configured from a kubeconfig file
+package kubeapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config holds the API server and credentials of a kubeconfig context
+type Config struct {
+	Server    string
+	Namespace string // namespace of the context, if any
+	TLS       *tls.Config
+	Token     string
+	Username  string
+	Password  string
+}
+
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+			TLSServerName            string `yaml:"tls-server-name"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string `yaml:"token"`
+			TokenFile             string `yaml:"tokenFile"`
+			ClientCertificate     string `yaml:"client-certificate"`
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKey             string `yaml:"client-key"`
+			ClientKeyData         string `yaml:"client-key-data"`
+			Username              string `yaml:"username"`
+			Password              string `yaml:"password"`
+			Exec                  *struct {
+				Command string `yaml:"command"`
+			} `yaml:"exec"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster   string `yaml:"cluster"`
+			User      string `yaml:"user"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+
+	dir string // directory relative file paths are resolved against
+}
+
+// DefaultPaths returns the kubeconfig files to load, from $KUBECONFIG
+// or ~/.kube/config
+func DefaultPaths() []string {
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		return filepath.SplitList(env)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return []string{filepath.Join(home, ".kube", "config")}
+}
+
+// LoadConfig reads the given kubeconfig files, merged as kubectl does
+// with the first file setting a value winning, and returns the config of
+// the named context, or of the current context if empty
+func LoadConfig(paths []string, context string) (*Config, error) {
+	var files []*kubeconfig
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) && len(paths) > 1 {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		kc := &kubeconfig{dir: filepath.Dir(path)}
+		if err := yaml.Unmarshal(data, kc); err != nil {
+			return nil, fmt.Errorf("invalid kubeconfig %s: %v", path, err)
+		}
+		files = append(files, kc)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no kubeconfig found")
+	}
+
+	if context == "" {
+		for _, kc := range files {
+			if kc.CurrentContext != "" {
+				context = kc.CurrentContext
+				break
+			}
+		}
+	}
+	if context == "" {
+		return nil, fmt.Errorf("no current context in kubeconfig")
+	}
+
+	var (
+		clusterName, userName string
+		config                = &Config{}
+		found                 bool
+	)
+	for _, kc := range files {
+		for _, c := range kc.Contexts {
+			if c.Name == context && !found {
+				clusterName, userName = c.Context.Cluster, c.Context.User
+				config.Namespace = c.Context.Namespace
+				found = true
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("context %q not found in kubeconfig", context)
+	}
+
+	config.TLS = &tls.Config{}
+	if err := setCluster(config, files, clusterName); err != nil {
+		return nil, err
+	}
+	if err := setUser(config, files, userName); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func setCluster(config *Config, files []*kubeconfig, name string) error {
+	for _, kc := range files {
+		for _, c := range kc.Clusters {
+			if c.Name != name {
+				continue
+			}
+			config.Server = strings.TrimRight(c.Cluster.Server, "/")
+			config.TLS.InsecureSkipVerify = c.Cluster.InsecureSkipTLSVerify
+			config.TLS.ServerName = c.Cluster.TLSServerName
+
+			ca, err := readData(kc.dir, c.Cluster.CertificateAuthorityData, c.Cluster.CertificateAuthority)
+			if err != nil {
+				return fmt.Errorf("cluster %s: %v", name, err)
+			}
+			if ca != nil {
+				pool := x509.NewCertPool()
+				if !pool.AppendCertsFromPEM(ca) {
+					return fmt.Errorf("cluster %s: invalid certificate authority", name)
+				}
+				config.TLS.RootCAs = pool
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("cluster %q not found in kubeconfig", name)
+}
+
+func setUser(config *Config, files []*kubeconfig, name string) error {
+	for _, kc := range files {
+		for _, u := range kc.Users {
+			if u.Name != name {
+				continue
+			}
+			if u.User.Exec != nil {
+				return fmt.Errorf("user %s: exec credential plugins (%s) are not supported", name, u.User.Exec.Command)
+			}
+			config.Username, config.Password = u.User.Username, u.User.Password
+
+			token, err := readData(kc.dir, "", u.User.TokenFile)
+			if err != nil {
+				return fmt.Errorf("user %s: %v", name, err)
+			}
+			config.Token = u.User.Token
+			if token != nil {
+				config.Token = strings.TrimSpace(string(token))
+			}
+
+			cert, err := readData(kc.dir, u.User.ClientCertificateData, u.User.ClientCertificate)
+			if err != nil {
+				return fmt.Errorf("user %s: %v", name, err)
+			}
+			key, err := readData(kc.dir, u.User.ClientKeyData, u.User.ClientKey)
+			if err != nil {
+				return fmt.Errorf("user %s: %v", name, err)
+			}
+			if cert != nil && key != nil {
+				pair, err := tls.X509KeyPair(cert, key)
+				if err != nil {
+					return fmt.Errorf("user %s: %v", name, err)
+				}
+				config.TLS.Certificates = []tls.Certificate{pair}
+			}
+			return nil
+		}
+	}
+	// contexts may omit the user for unauthenticated clusters
+	if name != "" {
+		return fmt.Errorf("user %q not found in kubeconfig", name)
+	}
+	return nil
+}
+
+// readData returns base64 inline data if set, or the content of path,
+// relative to dir; nil if neither is set
+func readData(dir, data, path string) ([]byte, error) {
+	if data != "" {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	if path == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,164 @@
+package kubeapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/websocket"
+)
+
+// exec subprotocols, v5 adding the closing of stdin
+const (
+	execProtocolV5 = "v5.channel.k8s.io"
+	execProtocolV4 = "v4.channel.k8s.io"
+)
+
+// exec stream channels, prefixing each websocket message
+const (
+	stdinChannel  = 0
+	stdoutChannel = 1
+	stderrChannel = 2
+	errorChannel  = 3
+	closeChannel  = 255
+)
+
+// ExecOptions sets the command and streams of an exec session
+type ExecOptions struct {
+	Command []string
+	Stdin   io.Reader // nil to not attach stdin
+	Stdout  io.Writer
+	Stderr  io.Writer // unused with TTY, output is merged in stdout
+	TTY     bool
+}
+
+// Exec runs a command in a pod container over the exec subresource,
+// returning once the command exits or ctx is done
+func (c *Client) Exec(ctx context.Context, namespace, pod, container string, opts ExecOptions) error {
+	query := url.Values{
+		"container": {container},
+		"command":   opts.Command,
+		"stdout":    {"true"},
+		"stderr":    {strconv.FormatBool(!opts.TTY)},
+		"stdin":     {strconv.FormatBool(opts.Stdin != nil)},
+		"tty":       {strconv.FormatBool(opts.TTY)},
+	}
+	location, err := url.Parse(c.config.Server + podPath(namespace, pod) + "/exec?" + query.Encode())
+	if err != nil {
+		return err
+	}
+	origin := *location
+	switch location.Scheme {
+	case "https":
+		location.Scheme = "wss"
+	default:
+		location.Scheme = "ws"
+	}
+
+	config, err := websocket.NewConfig(location.String(), origin.String())
+	if err != nil {
+		return err
+	}
+	config.Protocol = []string{execProtocolV5, execProtocolV4}
+	config.TlsConfig = c.config.TLS
+	config.Header = http.Header{}
+	c.authorize(config.Header)
+
+	ws, err := websocket.DialConfig(config)
+	if err != nil {
+		return fmt.Errorf("cannot exec in pod %s: %v", pod, err)
+	}
+	defer ws.Close()
+	ws.PayloadType = websocket.BinaryFrame
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			ws.Close()
+		case <-done:
+		}
+	}()
+
+	if opts.Stdin != nil {
+		canClose := len(config.Protocol) == 1 && config.Protocol[0] == execProtocolV5
+		go sendStdin(ws, opts.Stdin, canClose)
+	}
+
+	for {
+		var msg []byte
+		if err := websocket.Message.Receive(ws, &msg); err == io.EOF {
+			return nil
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		} else if err != nil {
+			return err
+		}
+		if len(msg) < 2 {
+			continue // initial empty frame of a channel
+		}
+
+		var w io.Writer
+		switch msg[0] {
+		case stdoutChannel:
+			w = opts.Stdout
+		case stderrChannel:
+			w = opts.Stderr
+		case errorChannel:
+			return execStatus(msg[1:])
+		}
+		if w != nil {
+			if _, err := w.Write(msg[1:]); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendStdin forwards r to the stdin channel, closing it at the end of r
+// if supported by the negotiated protocol
+func sendStdin(ws *websocket.Conn, r io.Reader, canClose bool) {
+	buf := make([]byte, 32*1024)
+	buf[0] = stdinChannel
+	for {
+		n, err := r.Read(buf[1:])
+		if n > 0 {
+			if websocket.Message.Send(ws, buf[:n+1]) != nil {
+				return
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	if canClose {
+		websocket.Message.Send(ws, []byte{closeChannel, stdinChannel})
+	}
+}
+
+// execStatus returns the error of the status sent on the error channel
+// at the end of a session, nil on success
+func execStatus(data []byte) error {
+	var status Status
+	if err := json.Unmarshal(data, &status); err != nil {
+		// v1 protocols send the error message as plain text
+		return fmt.Errorf("%s", strings.TrimSpace(string(data)))
+	}
+	if status.Status == "Success" {
+		return nil
+	}
+	if status.Reason == "NonZeroExitCode" && status.Details != nil {
+		for _, cause := range status.Details.Causes {
+			if cause.Reason == "ExitCode" {
+				return fmt.Errorf("command exited with code %s", cause.Message)
+			}
+		}
+	}
+	return fmt.Errorf("%s", status.Message)
+}
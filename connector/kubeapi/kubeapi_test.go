@@ -0,0 +1,228 @@
+package kubeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+const testToken = "s3cr3t"
+
+func writeKubeconfig(t *testing.T, server string) string {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte(testToken+"\n"), 0600))
+
+	path := filepath.Join(dir, "config")
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: local
+  cluster:
+    server: %s/
+contexts:
+- name: dev
+  context:
+    cluster: local
+    user: dev
+    namespace: apps
+- name: other
+  context:
+    cluster: local
+    user: missing
+users:
+- name: dev
+  user:
+    tokenFile: token
+`, server)
+	require.NoError(t, os.WriteFile(path, []byte(kubeconfig), 0600))
+	return path
+}
+
+// fakeAPIServer serves the API endpoints read by the connector,
+// rejecting requests without the kubeconfig token
+func fakeAPIServer(t *testing.T) *Client {
+	mux := http.NewServeMux()
+	reply := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	}
+
+	mux.HandleFunc("/api/v1/namespaces/apps/pods", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, map[string]any{"items": []any{map[string]any{
+			"metadata": map[string]any{"name": "web-1", "namespace": "apps"},
+			"spec": map[string]any{
+				"nodeName":   "node-a",
+				"containers": []any{map[string]any{"name": "nginx", "image": "nginx:1.27"}},
+			},
+			"status": map[string]any{
+				"phase": "Running",
+				"containerStatuses": []any{map[string]any{
+					"name":         "nginx",
+					"restartCount": 2,
+					"state":        map[string]any{"running": map[string]any{"startedAt": "2024-05-01T10:00:00Z"}},
+				}},
+			},
+		}}})
+	})
+	mux.HandleFunc("/apis/metrics.k8s.io/v1beta1/namespaces/apps/pods/web-1", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, map[string]any{"containers": []any{map[string]any{
+			"name":  "nginx",
+			"usage": map[string]any{"cpu": "250m", "memory": "64Mi"},
+		}}})
+	})
+	mux.HandleFunc("/api/v1/namespaces/apps/pods/web-1/log", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "nginx", r.URL.Query().Get("container"))
+		assert.Equal(t, "true", r.URL.Query().Get("follow"))
+		io.WriteString(w, "2024-05-01T10:00:00.5Z first line\n2024-05-01T10:00:01Z second line\n")
+	})
+	mux.Handle("/api/v1/namespaces/apps/pods/web-1/exec", websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			config.Protocol = []string{execProtocolV4}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			cmd := ws.Request().URL.Query()["command"]
+			websocket.Message.Send(ws, []byte{stdoutChannel})
+			websocket.Message.Send(ws, append([]byte{stdoutChannel}, strings.Join(cmd, " ")...))
+			websocket.Message.Send(ws, append([]byte{stderrChannel}, "warning"...))
+			if cmd[0] == "false" {
+				websocket.Message.Send(ws, append([]byte{errorChannel},
+					`{"status":"Failure","reason":"NonZeroExitCode","details":{"causes":[{"reason":"ExitCode","message":"1"}]}}`...))
+				return
+			}
+			websocket.Message.Send(ws, append([]byte{errorChannel}, `{"status":"Success"}`...))
+		},
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+testToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			reply(w, Status{Status: "Failure", Message: "Unauthorized", Code: 401})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	config, err := LoadConfig([]string{writeKubeconfig(t, server.URL)}, "")
+	require.NoError(t, err)
+	return NewClient(config)
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeKubeconfig(t, "https://127.0.0.1:6443")
+
+	config, err := LoadConfig([]string{path}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://127.0.0.1:6443", config.Server)
+	assert.Equal(t, "apps", config.Namespace)
+	assert.Equal(t, testToken, config.Token, "token file is relative to the kubeconfig")
+
+	_, err = LoadConfig([]string{path}, "other")
+	assert.Error(t, err, "user of the context is missing")
+	_, err = LoadConfig([]string{path}, "unknown")
+	assert.Error(t, err)
+}
+
+func TestListPods(t *testing.T) {
+	client := fakeAPIServer(t)
+
+	pods, err := client.ListPods(context.Background(), "apps")
+	require.NoError(t, err)
+	require.Len(t, pods, 1)
+	assert.Equal(t, "web-1", pods[0].Metadata.Name)
+	assert.Equal(t, "node-a", pods[0].Spec.NodeName)
+
+	status, ok := pods[0].ContainerStatus("nginx")
+	require.True(t, ok)
+	assert.Equal(t, 2, status.RestartCount)
+	assert.NotNil(t, status.State.Running)
+
+	_, err = client.ListPods(context.Background(), "kube-system")
+	assert.True(t, IsNotFound(err))
+
+	client.config.Token = "wrong"
+	_, err = client.ListPods(context.Background(), "apps")
+	assert.EqualError(t, err, "GET /api/v1/namespaces/apps/pods: Unauthorized (401)")
+}
+
+func TestPodMetrics(t *testing.T) {
+	client := fakeAPIServer(t)
+
+	m, err := client.PodMetrics(context.Background(), "apps", "web-1")
+	require.NoError(t, err)
+	require.Len(t, m.Containers, 1)
+
+	cpu, err := ParseQuantity(m.Containers[0].Usage["cpu"])
+	require.NoError(t, err)
+	assert.InDelta(t, 0.25, cpu, 1e-9)
+	mem, err := ParseQuantity(m.Containers[0].Usage["memory"])
+	require.NoError(t, err)
+	assert.Equal(t, float64(64<<20), mem)
+}
+
+func TestParseQuantity(t *testing.T) {
+	for s, want := range map[string]float64{
+		"2":         2,
+		"1500m":     1.5,
+		"12345678n": 0.012345678,
+		"1Ki":       1024,
+		"2G":        2e9,
+		"1.5Gi":     1.5 * (1 << 30),
+	} {
+		v, err := ParseQuantity(s)
+		require.NoError(t, err, s)
+		assert.InDelta(t, want, v, 1e-9, s)
+	}
+	_, err := ParseQuantity("lots")
+	assert.Error(t, err)
+}
+
+func TestLogs(t *testing.T) {
+	client := fakeAPIServer(t)
+
+	body, err := client.Logs(context.Background(), "apps", "web-1", "nginx", 20)
+	require.NoError(t, err)
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	ts, msg := ParseLogLine(lines[0])
+	assert.Equal(t, "first line", msg)
+	assert.Equal(t, 500, ts.Nanosecond()/1e6)
+}
+
+func TestExec(t *testing.T) {
+	client := fakeAPIServer(t)
+
+	var stdout, stderr bytes.Buffer
+	err := client.Exec(context.Background(), "apps", "web-1", "nginx", ExecOptions{
+		Command: []string{"echo", "hello"},
+		Stdout:  &stdout,
+		Stderr:  &stderr,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "echo hello", stdout.String())
+	assert.Equal(t, "warning", stderr.String())
+
+	err = client.Exec(context.Background(), "apps", "web-1", "nginx", ExecOptions{
+		Command: []string{"false"},
+		Stdout:  io.Discard,
+	})
+	assert.EqualError(t, err, "command exited with code 1")
+}
@@ -0,0 +1,37 @@
+package kubeapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// quantity suffixes, binary first so that "Mi" is not read as "M"
+var quantitySuffixes = []struct {
+	suffix string
+	mult   float64
+}{
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30},
+	{"Ti", 1 << 40}, {"Pi", 1 << 50}, {"Ei", 1 << 60},
+	{"n", 1e-9}, {"u", 1e-6}, {"m", 1e-3},
+	{"k", 1e3}, {"M", 1e6}, {"G", 1e9},
+	{"T", 1e12}, {"P", 1e15}, {"E", 1e18},
+}
+
+// ParseQuantity parses a resource quantity such as "250m", "12345n"
+// or "512Mi"
+func ParseQuantity(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	mult := 1.0
+	for _, q := range quantitySuffixes {
+		if strings.HasSuffix(s, q.suffix) {
+			s, mult = strings.TrimSuffix(s, q.suffix), q.mult
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity %q", s)
+	}
+	return v * mult, nil
+}
@@ -0,0 +1,160 @@
+package kubeapi
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// The subset of Kubernetes API objects read by the connector
+
+type ObjectMeta struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	UID               string            `json:"uid"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+	Labels            map[string]string `json:"labels"`
+}
+
+type Pod struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Spec     PodSpec    `json:"spec"`
+	Status   PodStatus  `json:"status"`
+}
+
+// ContainerStatus returns the status of the named container, if
+// reported yet
+func (p Pod) ContainerStatus(name string) (ContainerStatus, bool) {
+	for _, s := range p.Status.ContainerStatuses {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return ContainerStatus{}, false
+}
+
+type PodList struct {
+	Items []Pod `json:"items"`
+}
+
+type PodSpec struct {
+	NodeName   string      `json:"nodeName"`
+	Containers []Container `json:"containers"`
+}
+
+type Container struct {
+	Name           string               `json:"name"`
+	Image          string               `json:"image"`
+	Resources      ResourceRequirements `json:"resources"`
+	ReadinessProbe json.RawMessage      `json:"readinessProbe"`
+}
+
+type ResourceRequirements struct {
+	Limits   map[string]string `json:"limits"`
+	Requests map[string]string `json:"requests"`
+}
+
+type PodStatus struct {
+	Phase             string            `json:"phase"`
+	Reason            string            `json:"reason"`
+	Message           string            `json:"message"`
+	PodIP             string            `json:"podIP"`
+	ContainerStatuses []ContainerStatus `json:"containerStatuses"`
+}
+
+type ContainerStatus struct {
+	Name         string         `json:"name"`
+	Image        string         `json:"image"`
+	ContainerID  string         `json:"containerID"`
+	Ready        bool           `json:"ready"`
+	RestartCount int            `json:"restartCount"`
+	State        ContainerState `json:"state"`
+	LastState    ContainerState `json:"lastState"`
+}
+
+// ContainerState holds one of its members, according to the state of
+// the container
+type ContainerState struct {
+	Waiting *struct {
+		Reason  string `json:"reason"`
+		Message string `json:"message"`
+	} `json:"waiting"`
+	Running *struct {
+		StartedAt time.Time `json:"startedAt"`
+	} `json:"running"`
+	Terminated *struct {
+		ExitCode   int       `json:"exitCode"`
+		Reason     string    `json:"reason"`
+		Message    string    `json:"message"`
+		StartedAt  time.Time `json:"startedAt"`
+		FinishedAt time.Time `json:"finishedAt"`
+	} `json:"terminated"`
+}
+
+type Node struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Status   struct {
+		Capacity map[string]string `json:"capacity"`
+	} `json:"status"`
+}
+
+type NodeList struct {
+	Items []Node `json:"items"`
+}
+
+// PodMetrics is a usage sample of the metrics API (metrics-server)
+type PodMetrics struct {
+	Metadata   ObjectMeta `json:"metadata"`
+	Timestamp  time.Time  `json:"timestamp"`
+	Containers []struct {
+		Name  string            `json:"name"`
+		Usage map[string]string `json:"usage"`
+	} `json:"containers"`
+}
+
+// Summary is the stats summary of a kubelet, read through the API
+// server node proxy
+type Summary struct {
+	Pods []PodStats `json:"pods"`
+}
+
+type PodStats struct {
+	PodRef struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"podRef"`
+	Containers []struct {
+		Name string `json:"name"`
+		CPU  *struct {
+			UsageNanoCores *uint64 `json:"usageNanoCores"`
+		} `json:"cpu"`
+		Memory *struct {
+			UsageBytes      *uint64 `json:"usageBytes"`
+			WorkingSetBytes *uint64 `json:"workingSetBytes"`
+			RSSBytes        *uint64 `json:"rssBytes"`
+			MajorPageFaults *uint64 `json:"majorPageFaults"`
+		} `json:"memory"`
+	} `json:"containers"`
+	Network *struct {
+		Interfaces []struct {
+			Name     string  `json:"name"`
+			RxBytes  *uint64 `json:"rxBytes"`
+			RxErrors *uint64 `json:"rxErrors"`
+			TxBytes  *uint64 `json:"txBytes"`
+			TxErrors *uint64 `json:"txErrors"`
+		} `json:"interfaces"`
+	} `json:"network"`
+}
+
+// Status is the error document returned by the API server
+type Status struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Reason  string `json:"reason"`
+	Code    int    `json:"code"`
+	Details *struct {
+		Causes []struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"causes"`
+	} `json:"details"`
+}
@@ -0,0 +1,296 @@
+package connector
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Betzalel75/ctop/connector/collector"
+	"github.com/Betzalel75/ctop/connector/kubeapi"
+	"github.com/Betzalel75/ctop/connector/manager"
+	"github.com/Betzalel75/ctop/container"
+)
+
+func init() { enabled["kubernetes"] = NewKubernetes }
+
+// interval between pod listings
+const kubernetesRefresh = 5 * time.Second
+
+type KubernetesOpts struct {
+	kubeconfig []string // kubeconfig files
+	context    string   // kubeconfig context, current if empty
+	namespaces []string // namespaces to list pods of, "" for all
+}
+
+func NewKubernetesOpts() KubernetesOpts {
+	opts := KubernetesOpts{
+		kubeconfig: kubeapi.DefaultPaths(),
+		context:    os.Getenv("KUBE_CONTEXT"),
+	}
+	// comma separated namespaces, "*" for all, the context namespace
+	// if unset
+	switch env := os.Getenv("KUBE_NAMESPACE"); env {
+	case "":
+	case "*":
+		opts.namespaces = []string{""}
+	default:
+		for _, ns := range strings.Split(env, ",") {
+			if ns = strings.TrimSpace(ns); ns != "" {
+				opts.namespaces = append(opts.namespaces, ns)
+			}
+		}
+	}
+	return opts
+}
+
+// nodeResources holds the capacity of a node containers are measured
+// against
+type nodeResources struct {
+	cpus float64
+	mem  int64
+}
+
+type Kubernetes struct {
+	opts       KubernetesOpts
+	client     *kubeapi.Client
+	metricsAPI bool
+	containers map[string]*container.Container
+	nodes      map[string]nodeResources
+	closed     chan struct{}
+	lock       sync.RWMutex
+}
+
+func NewKubernetes() (Connector, error) {
+	opts := NewKubernetesOpts()
+	config, err := kubeapi.LoadConfig(opts.kubeconfig, opts.context)
+	if err != nil {
+		return nil, err
+	}
+	if len(opts.namespaces) == 0 {
+		ns := config.Namespace
+		if ns == "" {
+			ns = "default"
+		}
+		opts.namespaces = []string{ns}
+	}
+
+	cm := &Kubernetes{
+		opts:       opts,
+		client:     kubeapi.NewClient(config),
+		containers: make(map[string]*container.Container),
+		nodes:      make(map[string]nodeResources),
+		closed:     make(chan struct{}),
+	}
+
+	// fail early on unreachable clusters, for the connector to retry
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := cm.client.ListPods(ctx, opts.namespaces[0]); err != nil {
+		return nil, err
+	}
+	cm.metricsAPI, err = cm.client.HasMetricsAPI(ctx)
+	if err != nil {
+		log.Warningf("cannot check for the metrics API: %s", err)
+	}
+	if !cm.metricsAPI {
+		log.Infof("metrics API not available, reading usage from kubelet summaries")
+	}
+
+	cm.refreshAll()
+	go func() {
+		for {
+			select {
+			case <-cm.closed:
+				return
+			case <-time.After(kubernetesRefresh):
+				cm.refreshAll()
+			}
+		}
+	}()
+
+	return cm, nil
+}
+
+// refreshNodes reads node capacities, if allowed to list nodes
+func (cm *Kubernetes) refreshNodes(ctx context.Context) {
+	nodes, err := cm.client.ListNodes(ctx)
+	if err != nil {
+		log.Debugf("cannot list nodes: %s", err)
+		return
+	}
+	for _, n := range nodes {
+		cpus, _ := kubeapi.ParseQuantity(n.Status.Capacity["cpu"])
+		mem, _ := kubeapi.ParseQuantity(n.Status.Capacity["memory"])
+		cm.nodes[n.Metadata.Name] = nodeResources{cpus, int64(mem)}
+	}
+}
+
+// List pods of the selected namespaces, creating, updating and
+// removing containers
+func (cm *Kubernetes) refreshAll() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cm.refreshNodes(ctx)
+
+	seen := make(map[string]bool)
+	for _, ns := range cm.opts.namespaces {
+		pods, err := cm.client.ListPods(ctx, ns)
+		if err != nil {
+			log.Errorf("%s (%T)", err.Error(), err)
+			select {
+			case <-cm.closed:
+			default:
+				close(cm.closed)
+			}
+			return
+		}
+		for _, pod := range pods {
+			for _, spec := range pod.Spec.Containers {
+				id := kubernetesID(pod, spec.Name)
+				seen[id] = true
+				cm.refresh(cm.MustGet(id, pod, spec), pod, spec)
+			}
+		}
+	}
+
+	cm.lock.Lock()
+	for id, c := range cm.containers {
+		if !seen[id] {
+			c.SetState("exited") // stops the collector
+			delete(cm.containers, id)
+			log.Infof("removed dead container: %s", id)
+		}
+	}
+	cm.lock.Unlock()
+}
+
+// kubernetesID identifies a pod container across restarts
+func kubernetesID(pod kubeapi.Pod, container string) string {
+	return pod.Metadata.Namespace + "/" + pod.Metadata.Name + "/" + container
+}
+
+// update a ctop container from its pod
+func (cm *Kubernetes) refresh(c *container.Container, pod kubeapi.Pod, spec kubeapi.Container) {
+	c.SetMeta("namespace", pod.Metadata.Namespace)
+	c.SetMeta("pod", pod.Metadata.Name)
+	c.SetMeta("node", pod.Spec.NodeName)
+	c.SetMeta("IPs", pod.Status.PodIP)
+	c.SetMeta("created", pod.Metadata.CreationTimestamp.Local().Format("Mon Jan 02 15:04:05 2006"))
+
+	status, ok := pod.ContainerStatus(spec.Name)
+	if !ok {
+		c.SetMeta("image", spec.Image)
+		c.SetMeta("error", pod.Status.Message)
+		c.SetState("created")
+		return
+	}
+	c.SetMeta("image", status.Image)
+	if id := status.ContainerID; id != "" {
+		// strip the runtime scheme, as in containerd://<id>
+		if i := strings.Index(id, "://"); i >= 0 {
+			id = id[i+3:]
+		}
+		if len(id) > 12 {
+			id = id[:12]
+		}
+		c.SetMeta("id", id)
+	}
+	c.SetMeta("restarts", strconv.Itoa(status.RestartCount))
+	if len(spec.ReadinessProbe) > 0 {
+		if status.Ready {
+			c.SetMeta("health", "healthy")
+		} else {
+			c.SetMeta("health", "unhealthy")
+		}
+	}
+
+	// last termination, if the container is not terminated
+	term := status.State.Terminated
+	if term == nil {
+		term = status.LastState.Terminated
+	}
+	if term != nil {
+		c.SetMeta("exit code", strconv.Itoa(term.ExitCode))
+		c.SetMeta("finished", term.FinishedAt.Local().Format("Mon Jan 02 15:04:05 2006"))
+	}
+
+	switch s := status.State; {
+	case s.Running != nil:
+		c.SetMeta("error", "")
+		c.SetMeta("crashloop", "false")
+		c.SetState("running")
+	case s.Terminated != nil:
+		c.SetMeta("error", s.Terminated.Reason)
+		c.SetState("exited")
+	case s.Waiting != nil:
+		c.SetMeta("error", strings.TrimSpace(s.Waiting.Reason+" "+s.Waiting.Message))
+		c.SetMeta("crashloop", strconv.FormatBool(s.Waiting.Reason == "CrashLoopBackOff"))
+		c.SetState("created")
+	}
+}
+
+// MustGet gets a single ctop container in the map matching a pod
+// container, creating one anew if not existing
+func (cm *Kubernetes) MustGet(id string, pod kubeapi.Pod, spec kubeapi.Container) *container.Container {
+	c, ok := cm.Get(id)
+	if !ok {
+		target := collector.KubernetesTarget{
+			Namespace:  pod.Metadata.Namespace,
+			Pod:        pod.Metadata.Name,
+			Container:  spec.Name,
+			Node:       pod.Spec.NodeName,
+			CPUs:       1,
+			MetricsAPI: cm.metricsAPI,
+		}
+		if node, ok := cm.nodes[pod.Spec.NodeName]; ok {
+			target.CPUs, target.MemLimit = node.cpus, node.mem
+		}
+		if limit, err := kubeapi.ParseQuantity(spec.Resources.Limits["memory"]); err == nil {
+			target.MemLimit = int64(limit)
+		}
+
+		collector := collector.NewKubernetes(cm.client, target)
+		manager := manager.NewKubernetes(cm.client, target.Namespace, target.Pod, target.Container)
+		c = container.New(id, collector, manager)
+
+		name := pod.Metadata.Name
+		if len(pod.Spec.Containers) > 1 {
+			name += "/" + spec.Name
+		}
+		c.SetMeta("name", name)
+
+		cm.lock.Lock()
+		cm.containers[id] = c
+		cm.lock.Unlock()
+		log.Debugf("saw new container: %s", id)
+	}
+
+	return c
+}
+
+// Kubernetes implements Connector
+func (cm *Kubernetes) Wait() struct{} { return <-cm.closed }
+
+// Kubernetes implements Connector
+func (cm *Kubernetes) Get(id string) (*container.Container, bool) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	c, ok := cm.containers[id]
+	return c, ok
+}
+
+// Kubernetes implements Connector
+func (cm *Kubernetes) All() (containers container.Containers) {
+	cm.lock.Lock()
+	for _, c := range cm.containers {
+		containers = append(containers, c)
+	}
+	containers.Sort()
+	containers.Filter()
+	cm.lock.Unlock()
+	return containers
+}
@@ -0,0 +1,154 @@
+//go:build linux
+// +build linux
+
+package manager
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/Betzalel75/ctop/models"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/opencontainers/runc/libcontainer/configs"
+)
+
+// Cgroup manages a plain cgroup, through systemctl when the cgroup is
+// a systemd unit
+type Cgroup struct {
+	cg   cgroups.Manager
+	path string // cgroup path, relative to the hierarchy root
+	unit string // systemd unit name, if any
+}
+
+func NewCgroup(cg cgroups.Manager, path, unit string) *Cgroup {
+	return &Cgroup{cg: cg, path: path, unit: unit}
+}
+
+func systemctl(args ...string) error {
+	out, err := exec.Command("systemctl", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("systemctl %s: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// only services can be started again, scopes are created by the
+// processes they hold
+func (cm *Cgroup) isService() bool {
+	return strings.HasSuffix(cm.unit, ".service")
+}
+
+func (cm *Cgroup) Start() error {
+	if !cm.isService() {
+		return ActionNotImplErr
+	}
+	return systemctl("start", cm.unit)
+}
+
+func (cm *Cgroup) Stop() error {
+	if cm.unit == "" {
+		return ActionNotImplErr
+	}
+	return systemctl("stop", cm.unit)
+}
+
+func (cm *Cgroup) Remove() error {
+	return ActionNotImplErr
+}
+
+func (cm *Cgroup) Pause() error {
+	if err := cm.cg.Freeze(configs.Frozen); err != nil {
+		return fmt.Errorf("cannot freeze cgroup: %v", err)
+	}
+	return nil
+}
+
+func (cm *Cgroup) Unpause() error {
+	if err := cm.cg.Freeze(configs.Thawed); err != nil {
+		return fmt.Errorf("cannot thaw cgroup: %v", err)
+	}
+	return nil
+}
+
+func (cm *Cgroup) Restart() error {
+	if !cm.isService() {
+		return ActionNotImplErr
+	}
+	return systemctl("restart", cm.unit)
+}
+
+// Exec runs cmd in the namespaces of the first process of the cgroup
+func (cm *Cgroup) Exec(cmd []string) error {
+	pids, err := cm.cg.GetAllPids()
+	if err != nil {
+		return fmt.Errorf("cannot list cgroup processes: %v", err)
+	}
+	if len(pids) == 0 {
+		return fmt.Errorf("no process in cgroup %s", cm.path)
+	}
+
+	return nsenterExec(pids[0], cmd)
+}
+
+// Inspect returns the cgroup stats, along with the unit properties
+func (cm *Cgroup) Inspect() (any, error) {
+	stats, err := cm.cg.GetStats()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read cgroup stats: %v", err)
+	}
+	pids, _ := cm.cg.GetAllPids()
+	doc := map[string]any{
+		"Path":  cm.path,
+		"Pids":  pids,
+		"Stats": stats,
+	}
+
+	if cm.unit != "" {
+		out, err := exec.Command("systemctl", "show", cm.unit).Output()
+		if err == nil {
+			props := make(map[string]string)
+			scanner := bufio.NewScanner(bytes.NewReader(out))
+			for scanner.Scan() {
+				if k, v, ok := strings.Cut(scanner.Text(), "="); ok && v != "" {
+					props[k] = v
+				}
+			}
+			doc["Unit"] = props
+		}
+	}
+	return doc, nil
+}
+
+func (cm *Cgroup) Changes() ([]models.FileChange, error) {
+	return nil, ActionNotImplErr
+}
+
+func (cm *Cgroup) Download(ctx context.Context, path string, w io.Writer) error {
+	return ActionNotImplErr
+}
+
+func (cm *Cgroup) Upload(ctx context.Context, path string, r io.Reader) error {
+	return ActionNotImplErr
+}
+
+func (cm *Cgroup) Top() ([]models.Process, error) {
+	pids, err := cm.cg.GetAllPids()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list cgroup processes: %v", err)
+	}
+	return procTop(pids)
+}
+
+func (cm *Cgroup) Signal(pid int, sig syscall.Signal) error {
+	pids, err := cm.cg.GetAllPids()
+	if err != nil {
+		return fmt.Errorf("cannot list cgroup processes: %v", err)
+	}
+	return procSignal(pids, pid, sig)
+}
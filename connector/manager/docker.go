@@ -1,13 +1,22 @@
 package manager
 
 import (
+	"context"
 	"fmt"
+	"github.com/Betzalel75/ctop/models"
 	api "github.com/fsouza/go-dockerclient"
 	"github.com/pkg/errors"
 	"io"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"syscall"
 )
 
+// ps options used to list container processes
+const psArgs = "-o pid,user,pcpu,pmem,rss,args"
+
 type Docker struct {
 	id     string
 	client *api.Client
@@ -145,3 +154,140 @@ func (dc *Docker) Restart() error {
 	}
 	return nil
 }
+
+func (dc *Docker) Inspect() (any, error) {
+	c, err := dc.client.InspectContainer(dc.id)
+	if err != nil {
+		return nil, fmt.Errorf("cannot inspect container: %v", err)
+	}
+	return c, nil
+}
+
+func (dc *Docker) Changes() ([]models.FileChange, error) {
+	changes, err := dc.client.ContainerChanges(dc.id)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list container changes: %v", err)
+	}
+
+	var fc []models.FileChange
+	for _, ch := range changes {
+		kind := models.FileChanged
+		switch ch.Kind {
+		case api.ChangeAdd:
+			kind = models.FileAdded
+		case api.ChangeDelete:
+			kind = models.FileDeleted
+		}
+		fc = append(fc, models.FileChange{Path: ch.Path, Kind: kind})
+	}
+	return fc, nil
+}
+
+func (dc *Docker) Download(ctx context.Context, path string, w io.Writer) error {
+	err := dc.client.DownloadFromContainer(dc.id, api.DownloadFromContainerOptions{
+		OutputStream: w,
+		Path:         path,
+		Context:      ctx,
+	})
+	if e, ok := err.(*api.Error); ok && e.Status == http.StatusNotFound {
+		return fmt.Errorf("cannot download %s: %w", path, os.ErrNotExist)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot download %s: %v", path, err)
+	}
+	return nil
+}
+
+func (dc *Docker) Upload(ctx context.Context, path string, r io.Reader) error {
+	err := dc.client.UploadToContainer(dc.id, api.UploadToContainerOptions{
+		InputStream: r,
+		Path:        path,
+		Context:     ctx,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot upload to %s: %v", path, err)
+	}
+	return nil
+}
+
+func (dc *Docker) Top() ([]models.Process, error) {
+	top, err := dc.client.TopContainer(dc.id, psArgs)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list container processes: %v", err)
+	}
+
+	// locate columns by title, ps implementations vary in naming
+	cols := make(map[string]int)
+	for i, t := range top.Titles {
+		cols[strings.ToUpper(t)] = i
+	}
+	field := func(row []string, names ...string) string {
+		for _, n := range names {
+			if i, ok := cols[n]; ok && i < len(row) {
+				return row[i]
+			}
+		}
+		return ""
+	}
+
+	var procs []models.Process
+	for _, row := range top.Processes {
+		pid, err := strconv.Atoi(field(row, "PID"))
+		if err != nil {
+			continue
+		}
+		cpu, _ := strconv.ParseFloat(field(row, "%CPU"), 64)
+		mem, _ := strconv.ParseFloat(field(row, "%MEM"), 64)
+		rss, _ := strconv.ParseInt(field(row, "RSS", "RSZ"), 10, 64)
+		procs = append(procs, models.Process{
+			PID:     pid,
+			User:    field(row, "USER", "UID"),
+			CPU:     cpu,
+			Mem:     mem,
+			RSS:     rss * 1024, // ps reports kilobytes
+			Command: field(row, "COMMAND", "ARGS", "CMD"),
+		})
+	}
+	return procs, nil
+}
+
+func (dc *Docker) Signal(pid int, sig syscall.Signal) error {
+	c, err := dc.client.InspectContainer(dc.id)
+	if err != nil {
+		return fmt.Errorf("cannot inspect container: %v", err)
+	}
+
+	// the main process is signaled through the daemon, which also works
+	// for remote hosts; other processes are signaled from this host, only
+	// if they are processes of the container seen from it
+	switch {
+	case pid == c.State.Pid:
+		err = dc.client.KillContainer(api.KillContainerOptions{ID: dc.id, Signal: api.Signal(sig)})
+	case dc.localProcess(pid, c.ID):
+		var p *os.Process
+		if p, err = os.FindProcess(pid); err == nil {
+			err = p.Signal(sig)
+		}
+	default:
+		return ActionNotImplErr
+	}
+	if err != nil {
+		return fmt.Errorf("cannot signal process %d: %v", pid, err)
+	}
+	return nil
+}
+
+// localProcess returns whether pid, as reported by the daemon, is a
+// process of container id on this host. PIDs of a remote daemon, of the
+// VM of Docker Desktop or seen from another PID namespace name unrelated
+// local processes, if any
+func (dc *Docker) localProcess(pid int, id string) bool {
+	if !strings.HasPrefix(dc.client.Endpoint(), "unix://") {
+		return false
+	}
+	cgroup, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(cgroup), id)
+}
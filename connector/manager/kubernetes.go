@@ -0,0 +1,122 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"syscall"
+
+	"github.com/Betzalel75/ctop/connector/kubeapi"
+	"github.com/Betzalel75/ctop/models"
+)
+
+type Kubernetes struct {
+	client    *kubeapi.Client
+	namespace string
+	pod       string
+	container string
+}
+
+func NewKubernetes(client *kubeapi.Client, namespace, pod, container string) *Kubernetes {
+	return &Kubernetes{
+		client:    client,
+		namespace: namespace,
+		pod:       pod,
+		container: container,
+	}
+}
+
+func (kc *Kubernetes) Start() error {
+	return ActionNotImplErr
+}
+
+func (kc *Kubernetes) Stop() error {
+	return ActionNotImplErr
+}
+
+// Remove deletes the pod of the container, which its controller, if
+// any, replaces
+func (kc *Kubernetes) Remove() error {
+	if err := kc.client.DeletePod(context.Background(), kc.namespace, kc.pod); err != nil {
+		return fmt.Errorf("cannot delete pod: %v", err)
+	}
+	return nil
+}
+
+func (kc *Kubernetes) Pause() error {
+	return ActionNotImplErr
+}
+
+func (kc *Kubernetes) Unpause() error {
+	return ActionNotImplErr
+}
+
+func (kc *Kubernetes) Restart() error {
+	return ActionNotImplErr
+}
+
+func (kc *Kubernetes) Exec(cmd []string) error {
+	return kc.client.Exec(context.Background(), kc.namespace, kc.pod, kc.container, kubeapi.ExecOptions{
+		Command: cmd,
+		Stdin:   &noClosableReader{os.Stdin},
+		Stdout:  os.Stdout,
+		TTY:     true,
+	})
+}
+
+func (kc *Kubernetes) Inspect() (any, error) {
+	pod, err := kc.client.GetPod(context.Background(), kc.namespace, kc.pod)
+	if err != nil {
+		return nil, fmt.Errorf("cannot inspect pod: %v", err)
+	}
+	return pod, nil
+}
+
+func (kc *Kubernetes) Changes() ([]models.FileChange, error) {
+	return nil, ActionNotImplErr
+}
+
+// Download archives path with tar in the container, as kubectl cp does
+func (kc *Kubernetes) Download(ctx context.Context, p string, w io.Writer) error {
+	var stderr bytes.Buffer
+	err := kc.client.Exec(ctx, kc.namespace, kc.pod, kc.container, kubeapi.ExecOptions{
+		Command: []string{"tar", "cf", "-", "-C", path.Dir(p), path.Base(p)},
+		Stdout:  w,
+		Stderr:  &stderr,
+	})
+	if strings.Contains(stderr.String(), "No such file") {
+		return fmt.Errorf("cannot download %s: %w", p, os.ErrNotExist)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot download %s: %v %s", p, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// Upload extracts the archive with tar in the container, which must
+// provide it
+func (kc *Kubernetes) Upload(ctx context.Context, p string, r io.Reader) error {
+	var stderr bytes.Buffer
+	err := kc.client.Exec(ctx, kc.namespace, kc.pod, kc.container, kubeapi.ExecOptions{
+		Command: []string{"tar", "xf", "-", "-C", p},
+		Stdin:   r,
+		Stdout:  io.Discard,
+		Stderr:  &stderr,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot upload to %s: %v %s", p, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func (kc *Kubernetes) Top() ([]models.Process, error) {
+	return nil, ActionNotImplErr
+}
+
+func (kc *Kubernetes) Signal(pid int, sig syscall.Signal) error {
+	return ActionNotImplErr
+}
@@ -1,6 +1,13 @@
 package manager
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"io"
+	"syscall"
+
+	"github.com/Betzalel75/ctop/models"
+)
 
 var ActionNotImplErr = errors.New("action not implemented")
 
@@ -12,4 +19,14 @@ type Manager interface {
 	Unpause() error
 	Restart() error
 	Exec(cmd []string) error
+	Inspect() (any, error)
+	Changes() ([]models.FileChange, error)
+	// Download writes a tar archive of path in the container filesystem to w
+	Download(ctx context.Context, path string, w io.Writer) error
+	// Upload extracts a tar archive read from r into directory path
+	// of the container filesystem
+	Upload(ctx context.Context, path string, r io.Reader) error
+	Top() ([]models.Process, error)
+	// Signal sends sig to a process of the container, by host PID
+	Signal(pid int, sig syscall.Signal) error
 }
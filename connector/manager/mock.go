@@ -1,5 +1,13 @@
 package manager
 
+import (
+	"context"
+	"io"
+	"syscall"
+
+	"github.com/Betzalel75/ctop/models"
+)
+
 type Mock struct{}
 
 func NewMock() *Mock {
@@ -33,3 +41,27 @@ func (m *Mock) Restart() error {
 func (m *Mock) Exec(cmd []string) error {
 	return ActionNotImplErr
 }
+
+func (m *Mock) Inspect() (any, error) {
+	return nil, ActionNotImplErr
+}
+
+func (m *Mock) Changes() ([]models.FileChange, error) {
+	return nil, ActionNotImplErr
+}
+
+func (m *Mock) Download(ctx context.Context, path string, w io.Writer) error {
+	return ActionNotImplErr
+}
+
+func (m *Mock) Upload(ctx context.Context, path string, r io.Reader) error {
+	return ActionNotImplErr
+}
+
+func (m *Mock) Top() ([]models.Process, error) {
+	return nil, ActionNotImplErr
+}
+
+func (m *Mock) Signal(pid int, sig syscall.Signal) error {
+	return ActionNotImplErr
+}
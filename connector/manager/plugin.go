@@ -0,0 +1,127 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/Betzalel75/ctop/connector/pluginapi"
+	"github.com/Betzalel75/ctop/models"
+)
+
+// Plugin manages containers through the actions of a connector plugin
+type Plugin struct {
+	client *pluginapi.Client
+	id     string
+}
+
+func NewPlugin(client *pluginapi.Client, id string) *Plugin {
+	return &Plugin{client: client, id: id}
+}
+
+// action runs a plugin action, decoding its result into result, if not
+// nil
+func (pm *Plugin) action(action string, args, result any) error {
+	params := pluginapi.ActionParams{Container: pm.id, Action: action}
+	if args != nil {
+		b, err := json.Marshal(args)
+		if err != nil {
+			return err
+		}
+		params.Args = b
+	}
+	err := pm.client.Call(context.Background(), pluginapi.MethodAction, params, result)
+	if pluginapi.IsNotImplemented(err) {
+		return ActionNotImplErr
+	}
+	if err != nil {
+		return fmt.Errorf("cannot %s container: %v", action, err)
+	}
+	return nil
+}
+
+func (pm *Plugin) Start() error {
+	return pm.action(pluginapi.ActionStart, nil, nil)
+}
+
+func (pm *Plugin) Stop() error {
+	return pm.action(pluginapi.ActionStop, nil, nil)
+}
+
+func (pm *Plugin) Remove() error {
+	return pm.action(pluginapi.ActionRemove, nil, nil)
+}
+
+func (pm *Plugin) Pause() error {
+	return pm.action(pluginapi.ActionPause, nil, nil)
+}
+
+func (pm *Plugin) Unpause() error {
+	return pm.action(pluginapi.ActionUnpause, nil, nil)
+}
+
+func (pm *Plugin) Restart() error {
+	return pm.action(pluginapi.ActionRestart, nil, nil)
+}
+
+// Exec runs the command the plugin returns, attached to the terminal,
+// as plugins have no terminal of their own
+func (pm *Plugin) Exec(cmd []string) error {
+	var ec pluginapi.ExecCommand
+	if err := pm.action(pluginapi.ActionExec, pluginapi.ExecArgs{Command: cmd}, &ec); err != nil {
+		return err
+	}
+	if len(ec.Command) == 0 {
+		return fmt.Errorf("no exec command returned by plugin")
+	}
+	c := exec.Command(ec.Command[0], ec.Command[1:]...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return c.Run()
+}
+
+func (pm *Plugin) Inspect() (any, error) {
+	var doc any
+	if err := pm.action(pluginapi.ActionInspect, nil, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (pm *Plugin) Changes() ([]models.FileChange, error) {
+	return nil, ActionNotImplErr
+}
+
+func (pm *Plugin) Download(ctx context.Context, path string, w io.Writer) error {
+	return ActionNotImplErr
+}
+
+func (pm *Plugin) Upload(ctx context.Context, path string, r io.Reader) error {
+	return ActionNotImplErr
+}
+
+func (pm *Plugin) Top() ([]models.Process, error) {
+	var procs []pluginapi.Process
+	if err := pm.action(pluginapi.ActionTop, nil, &procs); err != nil {
+		return nil, err
+	}
+	a := make([]models.Process, 0, len(procs))
+	for _, p := range procs {
+		a = append(a, models.Process{
+			PID:     p.PID,
+			User:    p.User,
+			CPU:     p.CPU,
+			Mem:     p.Mem,
+			RSS:     p.RSS,
+			Command: p.Command,
+		})
+	}
+	return a, nil
+}
+
+func (pm *Plugin) Signal(pid int, sig syscall.Signal) error {
+	return pm.action(pluginapi.ActionSignal, pluginapi.SignalArgs{PID: pid, Signal: int(sig)}, nil)
+}
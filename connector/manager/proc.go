@@ -0,0 +1,91 @@
+//go:build linux
+// +build linux
+
+package manager
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/Betzalel75/ctop/models"
+	linuxproc "github.com/c9s/goprocinfo/linux"
+)
+
+const clockTicksPerSecond = 100
+
+// size of the memory pages RSS is counted in, 64K on some arm64 and
+// ppc64 kernels
+var pageSize = int64(os.Getpagesize())
+
+// procTop reads /proc for the given processes, computing CPU and
+// memory usage the way ps does
+func procTop(pids []int) ([]models.Process, error) {
+	uptime, err := linuxproc.ReadUptime("/proc/uptime")
+	if err != nil {
+		return nil, err
+	}
+	mem, err := linuxproc.ReadMemInfo("/proc/meminfo")
+	if err != nil {
+		return nil, err
+	}
+
+	var procs []models.Process
+	for _, pid := range pids {
+		dir := filepath.Join("/proc", strconv.Itoa(pid))
+		stat, err := linuxproc.ReadProcessStat(filepath.Join(dir, "stat"))
+		if err != nil {
+			continue // process exited
+		}
+		p := models.Process{
+			PID:     pid,
+			RSS:     stat.Rss * pageSize,
+			Command: stat.Comm,
+		}
+
+		elapsed := uptime.Total - float64(stat.Starttime)/clockTicksPerSecond
+		if elapsed > 0 {
+			p.CPU = float64(stat.Utime+stat.Stime) / clockTicksPerSecond / elapsed * 100
+		}
+		if mem.MemTotal > 0 {
+			p.Mem = float64(p.RSS) / float64(mem.MemTotal*1024) * 100
+		}
+		if status, err := linuxproc.ReadProcessStatus(filepath.Join(dir, "status")); err == nil {
+			p.User = strconv.FormatUint(status.EffectiveUid, 10)
+			if u, err := user.LookupId(p.User); err == nil {
+				p.User = u.Username
+			}
+		}
+		if cmd, err := linuxproc.ReadProcessCmdline(filepath.Join(dir, "cmdline")); err == nil && strings.TrimSpace(cmd) != "" {
+			p.Command = cmd
+		}
+		procs = append(procs, p)
+	}
+	return procs, nil
+}
+
+// procSignal sends sig to pid, if one of the container processes pids
+func procSignal(pids []int, pid int, sig syscall.Signal) error {
+	if !slices.Contains(pids, pid) {
+		return fmt.Errorf("process %d not found in container", pid)
+	}
+	if err := syscall.Kill(pid, sig); err != nil {
+		return fmt.Errorf("cannot signal process %d: %v", pid, err)
+	}
+	return nil
+}
+
+// nsenterExec runs cmd in the namespaces of pid, attached to the
+// terminal
+func nsenterExec(pid int, cmd []string) error {
+	args := append([]string{"-t", strconv.Itoa(pid), "-m", "-u", "-i", "-n", "-p", "--"}, cmd...)
+	c := exec.Command("nsenter", args...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return c.Run()
+}
@@ -1,35 +1,128 @@
+//go:build linux
+// +build linux
+
 package manager
 
-type Runc struct{}
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/Betzalel75/ctop/models"
+	"github.com/opencontainers/runc/libcontainer"
+)
+
+// time given to containers to exit on SIGTERM before being killed
+const stopTimeout = 3 * time.Second
 
-func NewRunc() *Runc {
-	return &Runc{}
+type Runc struct {
+	libc libcontainer.Container
+}
+
+func NewRunc(libc libcontainer.Container) *Runc {
+	return &Runc{libc: libc}
 }
 
 func (rc *Runc) Start() error {
 	return ActionNotImplErr
 }
 
+// Stop sends SIGTERM to the container init, killing all processes if
+// it is still running after stopTimeout
 func (rc *Runc) Stop() error {
-	return ActionNotImplErr
+	if err := rc.libc.Signal(syscall.SIGTERM, false); err != nil {
+		return fmt.Errorf("cannot stop container: %v", err)
+	}
+	for deadline := time.Now().Add(stopTimeout); time.Now().Before(deadline); {
+		if status, err := rc.libc.Status(); err != nil || status == libcontainer.Stopped {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err := rc.libc.Signal(syscall.SIGKILL, true); err != nil {
+		return fmt.Errorf("cannot kill container: %v", err)
+	}
+	return nil
 }
 
+// Remove destroys a stopped container, removing its state
 func (rc *Runc) Remove() error {
-	return ActionNotImplErr
+	if err := rc.libc.Destroy(); err != nil {
+		return fmt.Errorf("cannot destroy container: %v", err)
+	}
+	return nil
 }
 
 func (rc *Runc) Pause() error {
-	return ActionNotImplErr
+	if err := rc.libc.Pause(); err != nil {
+		return fmt.Errorf("cannot pause container: %v", err)
+	}
+	return nil
 }
 
 func (rc *Runc) Unpause() error {
-	return ActionNotImplErr
+	if err := rc.libc.Resume(); err != nil {
+		return fmt.Errorf("cannot resume container: %v", err)
+	}
+	return nil
 }
 
 func (rc *Runc) Restart() error {
 	return ActionNotImplErr
 }
 
+// Exec runs cmd in the namespaces of the container init
 func (rc *Runc) Exec(cmd []string) error {
+	state, err := rc.libc.State()
+	if err != nil {
+		return fmt.Errorf("cannot read container state: %v", err)
+	}
+	return nsenterExec(state.InitProcessPid, cmd)
+}
+
+func (rc *Runc) Inspect() (any, error) {
+	state, err := rc.libc.State()
+	if err != nil {
+		return nil, fmt.Errorf("cannot inspect container: %v", err)
+	}
+	return state, nil
+}
+
+func (rc *Runc) Changes() ([]models.FileChange, error) {
+	return nil, ActionNotImplErr
+}
+
+func (rc *Runc) Download(ctx context.Context, path string, w io.Writer) error {
 	return ActionNotImplErr
 }
+
+func (rc *Runc) Upload(ctx context.Context, path string, r io.Reader) error {
+	return ActionNotImplErr
+}
+
+// Top reads /proc for the processes of the container cgroup
+func (rc *Runc) Top() ([]models.Process, error) {
+	pids, err := rc.libc.Processes()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list container processes: %v", err)
+	}
+	return procTop(pids)
+}
+
+func (rc *Runc) Signal(pid int, sig syscall.Signal) error {
+	pids, err := rc.libc.Processes()
+	if err != nil {
+		return fmt.Errorf("cannot list container processes: %v", err)
+	}
+	// signal init through libcontainer, guarding against PID reuse
+	if state, err := rc.libc.State(); err == nil && state.InitProcessPid == pid && slices.Contains(pids, pid) {
+		if err := rc.libc.Signal(sig, false); err != nil {
+			return fmt.Errorf("cannot signal process %d: %v", pid, err)
+		}
+		return nil
+	}
+	return procSignal(pids, pid, sig)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/Betzalel75/ctop/connector/collector"
 	"github.com/Betzalel75/ctop/connector/manager"
 	"github.com/Betzalel75/ctop/container"
+	"github.com/Betzalel75/ctop/models"
 	"github.com/jgautheron/codename-generator"
 	"github.com/nu7hatch/gouuid"
 )
@@ -19,10 +20,11 @@ func init() { enabled["mock"] = NewMock }
 
 type Mock struct {
 	containers container.Containers
+	events     *EventLog
 }
 
 func NewMock() (Connector, error) {
-	cs := &Mock{}
+	cs := &Mock{events: NewEventLog(eventLogSize)}
 	go cs.Init()
 	go cs.Loop()
 	return cs, nil
@@ -69,6 +71,11 @@ func (cs *Mock) makeContainer(aggression int64, health bool) {
 					i = 0
 				}
 				c.SetMeta("health", healthStates[i])
+				if healthStates[i] == "unhealthy" {
+					c.SetMeta("failing streak", "3")
+				} else {
+					c.SetMeta("failing streak", "0")
+				}
 				time.Sleep(12 * time.Second)
 			}
 		}()
@@ -76,13 +83,27 @@ func (cs *Mock) makeContainer(aggression int64, health bool) {
 	cs.containers = append(cs.containers, c)
 }
 
+// Mock implements EventSource
+func (cs *Mock) Events() *EventLog { return cs.events }
+
+var mockActions = map[string]string{"running": "start", "exited": "die", "paused": "pause"}
+
 func (cs *Mock) Loop() {
 	iter := 0
 	for {
 		// Change state for random container
 		if iter%5 == 0 && len(cs.containers) > 0 {
 			randC := cs.containers[rand.Intn(len(cs.containers))]
-			randC.SetState(makeState())
+			state := makeState()
+			randC.SetState(state)
+			cs.events.Add(models.Event{
+				Time:       time.Now(),
+				Type:       "container",
+				Action:     mockActions[state],
+				ID:         randC.Id,
+				Name:       randC.GetMeta("name"),
+				Attributes: map[string]string{"name": randC.GetMeta("name"), "image": "mock"},
+			})
 		}
 		iter++
 		time.Sleep(3 * time.Second)
@@ -0,0 +1,292 @@
+package pluginapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+)
+
+// maximum size of a message, as inspect documents can be large
+const maxMessageSize = 4 << 20
+
+// responses buffered per request, a stream whose consumer falls further
+// behind is dropped
+const callBuffer = 64
+
+var (
+	errClosed   = errors.New("plugin closed")
+	errOverflow = errors.New("plugin stream dropped, its reader fell behind")
+)
+
+// call is a pending request, receiving responses until unregistered
+type call struct {
+	ch       chan Response
+	done     chan struct{}
+	overflow chan struct{} // closed once dropped for a full buffer
+}
+
+// Client sends requests to a plugin
+type Client struct {
+	w       io.Writer
+	wlock   sync.Mutex
+	cmd     *exec.Cmd
+	nextID  int64
+	pending map[int64]*call
+	lock    sync.Mutex
+	closed  chan struct{}
+	err     error // reason the client closed, once closed
+}
+
+// Start runs a plugin command, writing its stderr to stderr
+func Start(command []string, stderr io.Writer) (*Client, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("no plugin command")
+	}
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stderr = stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("cannot start plugin: %v", err)
+	}
+
+	c := newClient(stdout, stdin)
+	c.cmd = cmd
+	go c.read(stdout)
+	return c, nil
+}
+
+// NewClient returns a client exchanging messages with a plugin over r
+// and w
+func NewClient(r io.Reader, w io.Writer) *Client {
+	c := newClient(r, w)
+	go c.read(r)
+	return c
+}
+
+func newClient(r io.Reader, w io.Writer) *Client {
+	return &Client{
+		w:       w,
+		pending: make(map[int64]*call),
+		closed:  make(chan struct{}),
+	}
+}
+
+// read responses until the plugin exits, dispatching them to pending
+// requests; never blocking on one of them, as others would stall
+func (c *Client) read(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+	for scanner.Scan() {
+		var resp Response
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			c.close(fmt.Errorf("invalid plugin message: %v", err))
+			return
+		}
+		c.lock.Lock()
+		call, ok := c.pending[resp.ID]
+		c.lock.Unlock()
+		if !ok {
+			continue // answer to a canceled request
+		}
+		select {
+		case call.ch <- resp:
+		case <-call.done:
+		default:
+			c.lock.Lock()
+			delete(c.pending, resp.ID)
+			c.lock.Unlock()
+			close(call.overflow)
+		}
+	}
+
+	err := scanner.Err()
+	if err == nil {
+		err = io.EOF
+	}
+	if c.cmd != nil {
+		if werr := c.cmd.Wait(); werr != nil {
+			err = werr
+		}
+	}
+	c.close(fmt.Errorf("plugin exited: %v", err))
+}
+
+func (c *Client) close(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	select {
+	case <-c.closed:
+	default:
+		c.err = err
+		close(c.closed)
+	}
+}
+
+// Done returns a channel closed once the plugin exits
+func (c *Client) Done() <-chan struct{} { return c.closed }
+
+// Err returns the reason the client closed, nil if not closed
+func (c *Client) Err() error {
+	select {
+	case <-c.closed:
+		return c.err
+	default:
+		return nil
+	}
+}
+
+// Close closes the plugin stdin, killing the plugin if started by the
+// client
+func (c *Client) Close() error {
+	if wc, ok := c.w.(io.Closer); ok {
+		wc.Close()
+	}
+	if c.cmd != nil && c.cmd.Process != nil {
+		c.cmd.Process.Kill()
+	}
+	c.close(errClosed)
+	return nil
+}
+
+func (c *Client) register() (int64, *call) {
+	id := atomic.AddInt64(&c.nextID, 1)
+	call := &call{
+		ch:       make(chan Response, callBuffer),
+		done:     make(chan struct{}),
+		overflow: make(chan struct{}),
+	}
+	c.lock.Lock()
+	c.pending[id] = call
+	c.lock.Unlock()
+	return id, call
+}
+
+func (c *Client) unregister(id int64) {
+	c.lock.Lock()
+	if call, ok := c.pending[id]; ok {
+		close(call.done)
+		delete(c.pending, id)
+	}
+	c.lock.Unlock()
+}
+
+func (c *Client) send(req Request) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	c.wlock.Lock()
+	defer c.wlock.Unlock()
+	if _, err := c.w.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("cannot write to plugin: %v", err)
+	}
+	return nil
+}
+
+// Call sends a request answered by a single response, decoding its
+// result into result, if not nil
+func (c *Client) Call(ctx context.Context, method string, params, result any) error {
+	id, call := c.register()
+	defer c.unregister(id)
+	if err := c.send(Request{ID: id, Method: method, Params: params}); err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-call.ch:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result != nil && len(resp.Result) > 0 {
+			if err := json.Unmarshal(resp.Result, result); err != nil {
+				return fmt.Errorf("invalid %s result: %v", method, err)
+			}
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.closed:
+		return c.err
+	}
+}
+
+// Stream is a stream of results, closed once the plugin ends it or the
+// stream context is canceled
+type Stream struct {
+	C   <-chan json.RawMessage
+	err error
+}
+
+// Err returns the error the stream ended with, once C is closed
+func (s *Stream) Err() error { return s.err }
+
+// Stream sends a request opening a stream, canceled along with ctx
+func (c *Client) Stream(ctx context.Context, method string, params any) (*Stream, error) {
+	id, call := c.register()
+	if err := c.send(Request{ID: id, Method: method, Params: params}); err != nil {
+		c.unregister(id)
+		return nil, err
+	}
+
+	out := make(chan json.RawMessage)
+	s := &Stream{C: out}
+	go func() {
+		defer close(out)
+		defer c.unregister(id)
+		for {
+			select {
+			case resp := <-call.ch:
+				if resp.Error != nil {
+					s.err = resp.Error
+					return
+				}
+				if resp.End {
+					return
+				}
+				select {
+				case out <- resp.Result:
+				case <-call.overflow:
+					s.err = errOverflow
+					c.cancel(id)
+					return
+				case <-ctx.Done():
+					c.cancel(id)
+					return
+				}
+			case <-call.overflow:
+				s.err = errOverflow
+				c.cancel(id)
+				return
+			case <-ctx.Done():
+				c.cancel(id)
+				return
+			case <-c.closed:
+				s.err = c.err
+				return
+			}
+		}
+	}()
+	return s, nil
+}
+
+// cancel a stream, ignoring the answer
+func (c *Client) cancel(stream int64) {
+	id := atomic.AddInt64(&c.nextID, 1)
+	c.send(Request{ID: id, Method: MethodCancel, Params: CancelParams{Stream: stream}})
+}
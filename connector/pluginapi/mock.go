@@ -0,0 +1,283 @@
+//go:build !release
+// +build !release
+
+package pluginapi
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jgautheron/codename-generator"
+	"github.com/nu7hatch/gouuid"
+)
+
+const mockLog = "Cura ob pro qui tibi inveni dum qua fit donec amare illic mea, regem falli contexo pro peregrinorum heremo absconditi araneae meminerim deliciosas actionibus facere modico dura sonuerunt psalmi contra rerum, tempus mala anima volebant dura quae o modis."
+
+var (
+	mockHealthStates = []string{"starting", "healthy", "unhealthy"}
+	mockActions      = map[string]string{"running": "start", "exited": "die", "paused": "pause"}
+)
+
+type mockContainer struct {
+	Container
+	aggression int64
+}
+
+// Mock is a reference plugin, mirroring the mock connector: random
+// containers changing state every 15 seconds, with random usage
+type Mock struct {
+	containers []*mockContainer
+	subs       map[chan Event]bool // event subscribers
+	lock       sync.Mutex
+}
+
+func NewMock() *Mock {
+	m := &Mock{subs: make(map[chan Event]bool)}
+	for i := 0; i < 4; i++ {
+		m.makeContainer(3, true)
+	}
+	for i := 0; i < 16; i++ {
+		m.makeContainer(1, false)
+	}
+	go m.loop()
+	return m
+}
+
+func (m *Mock) makeContainer(aggression int64, health bool) {
+	c := &mockContainer{
+		Container: Container{
+			ID:    mockID(),
+			Name:  mockName(),
+			State: mockState(),
+			Meta:  map[string]string{"image": "mock"},
+		},
+		aggression: aggression,
+	}
+	if health {
+		c.Meta["health"] = mockHealthStates[0]
+	}
+	m.containers = append(m.containers, c)
+}
+
+// loop changes the state of a random container, and cycles health
+// states
+func (m *Mock) loop() {
+	for iter := 1; ; iter++ {
+		time.Sleep(3 * time.Second)
+		m.lock.Lock()
+		if iter%5 == 0 {
+			m.setState(m.containers[rand.Intn(len(m.containers))], mockState())
+		}
+		if iter%4 == 0 {
+			for _, c := range m.containers {
+				if h, ok := c.Meta["health"]; ok {
+					c.Meta["health"] = mockHealthStates[(indexOf(mockHealthStates, h)+1)%len(mockHealthStates)]
+				}
+			}
+		}
+		m.lock.Unlock()
+	}
+}
+
+func indexOf(a []string, s string) int {
+	for i, v := range a {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// setState sets the state of a container, notifying subscribers; the
+// lock must be held
+func (m *Mock) setState(c *mockContainer, state string) {
+	c.State = state
+	m.publish(Event{
+		Time:       time.Now(),
+		Type:       "container",
+		Action:     mockActions[state],
+		ID:         c.ID,
+		Name:       c.Name,
+		Attributes: map[string]string{"name": c.Name, "image": "mock"},
+	})
+}
+
+func (m *Mock) publish(e Event) {
+	for ch := range m.subs {
+		select {
+		case ch <- e:
+		default: // slow subscriber
+		}
+	}
+}
+
+func (m *Mock) get(id string) (*mockContainer, error) {
+	for _, c := range m.containers {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, &Error{Code: CodeNotFound, Message: "no such container: " + id}
+}
+
+// Mock implements Handler
+func (m *Mock) List() ([]Container, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	a := make([]Container, 0, len(m.containers))
+	for _, c := range m.containers {
+		meta := make(map[string]string)
+		for k, v := range c.Meta {
+			meta[k] = v
+		}
+		a = append(a, Container{ID: c.ID, Name: c.Name, State: c.State, Meta: meta})
+	}
+	return a, nil
+}
+
+// Mock implements Handler
+func (m *Mock) Events(ctx context.Context, send func(Event)) error {
+	ch := make(chan Event, 16)
+	m.lock.Lock()
+	m.subs[ch] = true
+	m.lock.Unlock()
+	defer func() {
+		m.lock.Lock()
+		delete(m.subs, ch)
+		m.lock.Unlock()
+	}()
+
+	for {
+		select {
+		case e := <-ch:
+			send(e)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Mock implements Handler
+func (m *Mock) Metrics(ctx context.Context, id string, send func(Metrics)) error {
+	m.lock.Lock()
+	c, err := m.get(id)
+	m.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	a := c.aggression
+	s := Metrics{CPUs: 4, MemLimit: 2147483648, Pids: rand.Intn(12)}
+	s.IOBytesRead = rand.Int63n(8098) * a
+	s.IOBytesWrite = rand.Int63n(8098) * a
+	s.BlockDevices = []BlockDevice{{Name: "sda", ReadBytes: s.IOBytesRead, WriteBytes: s.IOBytesWrite}}
+	for {
+		s.CPUUtil += rand.Intn(2) * int(a)
+		if s.CPUUtil >= 100 {
+			s.CPUUtil = 0
+		}
+		s.CPUUser = s.CPUUtil * 3 / 4
+		s.CPUSystem = s.CPUUtil - s.CPUUser
+		s.NetTx += rand.Int63n(60) * a
+		s.NetRx += rand.Int63n(60) * a
+		s.NetInterfaces = []NetInterface{{Name: "eth0", Rx: s.NetRx, Tx: s.NetTx}}
+		s.MemUsage += rand.Int63n(s.MemLimit/512) * a
+		if s.MemUsage > s.MemLimit {
+			s.MemUsage = 0
+		}
+		s.MemRSS = s.MemUsage * 2 / 3
+		s.MemCache = s.MemUsage - s.MemRSS
+		send(s)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// Mock implements Handler
+func (m *Mock) Logs(ctx context.Context, id string, send func(LogLine)) error {
+	m.lock.Lock()
+	_, err := m.get(id)
+	m.lock.Unlock()
+	if err != nil {
+		return err
+	}
+	for {
+		send(LogLine{Time: time.Now(), Message: mockLog})
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// Mock implements Handler; lifecycle actions change the container
+// state, other actions are not implemented
+func (m *Mock) Action(id, action string, args json.RawMessage) (any, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	c, err := m.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch action {
+	case ActionStart, ActionUnpause, ActionRestart:
+		m.setState(c, "running")
+	case ActionStop:
+		m.setState(c, "exited")
+	case ActionPause:
+		m.setState(c, "paused")
+	case ActionRemove:
+		for i, mc := range m.containers {
+			if mc == c {
+				m.containers = append(m.containers[:i], m.containers[i+1:]...)
+				break
+			}
+		}
+		m.publish(Event{Time: time.Now(), Type: "container", Action: "destroy", ID: c.ID, Name: c.Name})
+	case ActionInspect:
+		return c.Container, nil
+	default:
+		return nil, ErrNotImplemented
+	}
+	return nil, nil
+}
+
+func mockID() string {
+	u, err := uuid.NewV4()
+	if err != nil {
+		panic(err)
+	}
+	return strings.Replace(u.String(), "-", "", -1)[:12]
+}
+
+func mockName() string {
+	n, err := codename.Get(codename.Sanitized)
+	if err != nil {
+		panic(err)
+	}
+	nsp := strings.Split(n, "-")
+	if len(nsp) > 2 {
+		n = strings.Join(nsp[:2], "-")
+	}
+	return strings.Replace(n, "-", "_", -1)
+}
+
+func mockState() string {
+	switch rand.Intn(10) {
+	case 0, 1, 2:
+		return "exited"
+	case 3:
+		return "paused"
+	}
+	return "running"
+}
@@ -0,0 +1,23 @@
+//go:build !release
+// +build !release
+
+// mockplugin is the reference ctop connector plugin, serving mock
+// containers. Run ctop with it as:
+//
+//	go build -o ctop-mockplugin ./connector/pluginapi/mockplugin
+//	EXEC_PLUGIN=./ctop-mockplugin ctop -connector exec
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Betzalel75/ctop/connector/pluginapi"
+)
+
+func main() {
+	if err := pluginapi.Serve("mock", pluginapi.NewMock(), os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,151 @@
+package pluginapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// servePlugin connects a client to the reference plugin, served in
+// process
+func servePlugin(t *testing.T) *Client {
+	reqR, reqW := io.Pipe()
+	respR, respW := io.Pipe()
+	done := make(chan error)
+	go func() {
+		done <- Serve("mock", NewMock(), reqR, respW)
+		respW.Close()
+	}()
+
+	client := NewClient(respR, reqW)
+	t.Cleanup(func() {
+		client.Close()
+		assert.NoError(t, <-done)
+	})
+	return client
+}
+
+func TestHelloAndList(t *testing.T) {
+	client := servePlugin(t)
+	ctx := context.Background()
+
+	var hello Hello
+	require.NoError(t, client.Call(ctx, MethodHello, HelloParams{Version: Version}, &hello))
+	assert.Equal(t, Hello{Name: "mock", Version: Version}, hello)
+
+	var list []Container
+	require.NoError(t, client.Call(ctx, MethodList, nil, &list))
+	require.Len(t, list, 20)
+	for _, c := range list {
+		assert.Len(t, c.ID, 12)
+		assert.Contains(t, []string{"running", "exited", "paused"}, c.State)
+	}
+
+	err := client.Call(ctx, "unknown", nil, nil)
+	assert.True(t, IsNotImplemented(err))
+}
+
+func TestMetricsStream(t *testing.T) {
+	client := servePlugin(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var list []Container
+	require.NoError(t, client.Call(ctx, MethodList, nil, &list))
+	s, err := client.Stream(ctx, MethodMetrics, ContainerParams{Container: list[0].ID})
+	require.NoError(t, err)
+
+	var m Metrics
+	require.NoError(t, json.Unmarshal(<-s.C, &m))
+	assert.Equal(t, int64(2147483648), m.MemLimit)
+
+	cancel()
+	for range s.C {
+	}
+	assert.NoError(t, s.Err())
+
+	s, err = client.Stream(context.Background(), MethodLogs, ContainerParams{Container: "missing"})
+	require.NoError(t, err)
+	_, ok := <-s.C
+	assert.False(t, ok)
+	assert.EqualError(t, s.Err(), "no such container: missing")
+}
+
+func TestActions(t *testing.T) {
+	client := servePlugin(t)
+	ctx := context.Background()
+
+	events, err := client.Stream(ctx, MethodEvents, nil)
+	require.NoError(t, err)
+
+	var list []Container
+	require.NoError(t, client.Call(ctx, MethodList, nil, &list))
+	id := list[0].ID
+
+	// events are subscribed to asynchronously, pause until one is seen
+	var e Event
+	for i := 0; e.Action == "" && i < 20; i++ {
+		require.NoError(t, client.Call(ctx, MethodAction, ActionParams{Container: id, Action: ActionPause}, nil))
+		select {
+		case raw := <-events.C:
+			require.NoError(t, json.Unmarshal(raw, &e))
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	assert.Equal(t, "pause", e.Action)
+	assert.Equal(t, id, e.ID)
+
+	var inspected Container
+	require.NoError(t, client.Call(ctx, MethodAction, ActionParams{Container: id, Action: ActionInspect}, &inspected))
+	assert.Equal(t, "paused", inspected.State)
+
+	err = client.Call(ctx, MethodAction, ActionParams{Container: id, Action: ActionSignal}, nil)
+	assert.True(t, IsNotImplemented(err))
+}
+
+func TestStalledStream(t *testing.T) {
+	reqR, reqW := io.Pipe()
+	respR, respW := io.Pipe()
+	client := NewClient(respR, reqW)
+	t.Cleanup(func() { client.Close() })
+
+	// a plugin flooding streams, answering calls
+	go func() {
+		dec, enc := json.NewDecoder(reqR), json.NewEncoder(respW)
+		for {
+			var req Request
+			if err := dec.Decode(&req); err != nil {
+				return
+			}
+			switch req.Method {
+			case MethodLogs:
+				for i := 0; i < 2*callBuffer; i++ {
+					enc.Encode(Response{ID: req.ID, Result: json.RawMessage(`"line"`)})
+				}
+			case MethodHello:
+				enc.Encode(Response{ID: req.ID, Result: json.RawMessage(`{}`)})
+			}
+		}
+	}()
+
+	// never read
+	s, err := client.Stream(context.Background(), MethodLogs, ContainerParams{Container: "c"})
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() { done <- client.Call(context.Background(), MethodHello, nil, nil) }()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("call blocked by a stalled stream")
+	}
+
+	for range s.C {
+	}
+	assert.Equal(t, errOverflow, s.Err())
+}
@@ -0,0 +1,210 @@
+// Package pluginapi implements the protocol of ctop connector plugins,
+// programs adapting a container runtime to ctop over their standard
+// input and output.
+//
+// Messages are JSON objects, one per line. ctop writes requests to the
+// plugin stdin, each with a unique id, a method and its parameters:
+//
+//	{"id":1,"method":"hello","params":{"version":1}}
+//	{"id":2,"method":"list"}
+//	{"id":3,"method":"metrics","params":{"container":"f00"}}
+//	{"id":4,"method":"action","params":{"container":"f00","action":"stop"}}
+//	{"id":5,"method":"cancel","params":{"stream":3}}
+//
+// and the plugin writes responses to its stdout, carrying the id of the
+// request they answer:
+//
+//	{"id":1,"result":{"name":"myruntime","version":1}}
+//	{"id":4,"error":{"code":"not_implemented","message":"stop"}}
+//
+// Requests may be answered in any order. hello, list and action are
+// answered by a single response. events, metrics and logs open streams,
+// answered by any number of responses, until the plugin ends the
+// stream with {"id":3,"end":true} or an error, or ctop cancels it. Once
+// a stream is canceled, the plugin ends it and sends no more results.
+//
+// Anything the plugin writes to its stderr is logged by ctop.
+package pluginapi
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Version of the protocol
+const Version = 1
+
+// Methods
+const (
+	MethodHello   = "hello"   // HelloParams, answered by Hello
+	MethodList    = "list"    // no params, answered by []Container
+	MethodEvents  = "events"  // no params, streams Event
+	MethodMetrics = "metrics" // ContainerParams, streams Metrics
+	MethodLogs    = "logs"    // ContainerParams, streams LogLine
+	MethodAction  = "action"  // ActionParams, answered per action
+	MethodCancel  = "cancel"  // CancelParams, answered by null
+)
+
+// Actions, answered by null unless noted
+const (
+	ActionStart   = "start"
+	ActionStop    = "stop"
+	ActionRemove  = "remove"
+	ActionPause   = "pause"
+	ActionUnpause = "unpause"
+	ActionRestart = "restart"
+	ActionInspect = "inspect" // answered by any JSON document
+	ActionTop     = "top"     // answered by []Process
+	ActionSignal  = "signal"  // args: SignalArgs
+	ActionExec    = "exec"    // args: ExecArgs, answered by ExecCommand
+)
+
+// Error codes
+const (
+	CodeNotImplemented = "not_implemented" // method or action not supported
+	CodeNotFound       = "not_found"       // no such container
+	CodeFailed         = "failed"          // any other error
+)
+
+type Request struct {
+	ID     int64  `json:"id"`
+	Method string `json:"method"`
+	Params any    `json:"params,omitempty"`
+}
+
+type Response struct {
+	ID     int64           `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *Error          `json:"error,omitempty"`
+	End    bool            `json:"end,omitempty"` // stream ended
+}
+
+// Error is an error returned by a plugin
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return e.Code
+	}
+	return e.Message
+}
+
+// IsNotImplemented returns whether err is a plugin error for a method or
+// action the plugin does not support
+func IsNotImplemented(err error) bool {
+	e, ok := err.(*Error)
+	return ok && e.Code == CodeNotImplemented
+}
+
+type HelloParams struct {
+	Version int `json:"version"`
+}
+
+// Hello describes a plugin
+type Hello struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+}
+
+type ContainerParams struct {
+	Container string `json:"container"`
+}
+
+type ActionParams struct {
+	Container string          `json:"container"`
+	Action    string          `json:"action"`
+	Args      json.RawMessage `json:"args,omitempty"`
+}
+
+type CancelParams struct {
+	Stream int64 `json:"stream"`
+}
+
+type SignalArgs struct {
+	PID    int `json:"pid"`
+	Signal int `json:"signal"`
+}
+
+type ExecArgs struct {
+	Command []string `json:"command"`
+}
+
+// ExecCommand is a command ctop runs attached to the terminal, on the
+// host, to execute a command in a container
+type ExecCommand struct {
+	Command []string `json:"command"`
+}
+
+// Container is a container as listed by a plugin. State is one of
+// created, running, paused or exited
+type Container struct {
+	ID    string            `json:"id"`
+	Name  string            `json:"name"`
+	State string            `json:"state"`
+	Meta  map[string]string `json:"meta,omitempty"` // e.g. image, created, health
+}
+
+// Event is a runtime event; container events trigger a new listing
+type Event struct {
+	Time       time.Time         `json:"time"`
+	Type       string            `json:"type"`
+	Action     string            `json:"action"`
+	ID         string            `json:"id"`
+	Name       string            `json:"name,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Metrics is a usage sample of a container, as absolute values. ctop
+// computes memory percentages itself
+type Metrics struct {
+	CPUs      int   `json:"cpus"`
+	CPUUtil   int   `json:"cpu_util"` // percent of host total
+	CPUUser   int   `json:"cpu_user,omitempty"`
+	CPUSystem int   `json:"cpu_system,omitempty"`
+	PerCPU    []int `json:"per_cpu,omitempty"`
+
+	MemUsage int64 `json:"mem_usage"`
+	MemLimit int64 `json:"mem_limit"`
+	MemRSS   int64 `json:"mem_rss,omitempty"`
+	MemCache int64 `json:"mem_cache,omitempty"`
+	MemSwap  int64 `json:"mem_swap,omitempty"`
+
+	// cumulative counters
+	NetRx        int64 `json:"net_rx"`
+	NetTx        int64 `json:"net_tx"`
+	IOBytesRead  int64 `json:"io_read"`
+	IOBytesWrite int64 `json:"io_write"`
+	Pids         int   `json:"pids"`
+
+	NetInterfaces []NetInterface `json:"net_interfaces,omitempty"`
+	BlockDevices  []BlockDevice  `json:"block_devices,omitempty"`
+}
+
+type NetInterface struct {
+	Name string `json:"name"`
+	Rx   int64  `json:"rx"`
+	Tx   int64  `json:"tx"`
+}
+
+type BlockDevice struct {
+	Name       string `json:"name"`
+	ReadBytes  int64  `json:"read_bytes"`
+	WriteBytes int64  `json:"write_bytes"`
+}
+
+type LogLine struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+type Process struct {
+	PID     int     `json:"pid"`
+	User    string  `json:"user"`
+	CPU     float64 `json:"cpu"` // percent of a single CPU
+	Mem     float64 `json:"mem"` // percent of host memory
+	RSS     int64   `json:"rss"`
+	Command string  `json:"command"`
+}
@@ -0,0 +1,178 @@
+package pluginapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ErrNotImplemented is returned by handlers for unsupported methods or
+// actions
+var ErrNotImplemented = &Error{Code: CodeNotImplemented, Message: "not implemented"}
+
+// Handler implements a plugin in Go. Stream methods send results until
+// ctx is canceled or the stream ends, returning nil to end it
+type Handler interface {
+	List() ([]Container, error)
+	Events(ctx context.Context, send func(Event)) error
+	Metrics(ctx context.Context, id string, send func(Metrics)) error
+	Logs(ctx context.Context, id string, send func(LogLine)) error
+	// Action runs an action on a container, returning its result
+	Action(id, action string, args json.RawMessage) (any, error)
+}
+
+// server answers requests read from a client with a Handler
+type server struct {
+	name    string
+	h       Handler
+	w       io.Writer
+	wlock   sync.Mutex
+	streams map[int64]context.CancelFunc // cancel functions of requests in progress
+	lock    sync.Mutex
+	wg      sync.WaitGroup
+}
+
+// Serve answers requests read from r with h, writing responses to w,
+// until r is closed
+func Serve(name string, h Handler, r io.Reader, w io.Writer) error {
+	s := &server{name: name, h: h, w: w, streams: make(map[int64]context.CancelFunc)}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+	for scanner.Scan() {
+		var req struct {
+			ID     int64           `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return fmt.Errorf("invalid request: %v", err)
+		}
+		// registered before reading on, for cancel requests to find
+		// the streams they follow
+		ctx, cancel := context.WithCancel(context.Background())
+		s.lock.Lock()
+		s.streams[req.ID] = cancel
+		s.lock.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(ctx, req.ID, req.Method, req.Params)
+			s.lock.Lock()
+			delete(s.streams, req.ID)
+			s.lock.Unlock()
+			cancel()
+		}()
+	}
+
+	// client gone, end all streams
+	s.lock.Lock()
+	for _, cancel := range s.streams {
+		cancel()
+	}
+	s.lock.Unlock()
+	s.wg.Wait()
+	return scanner.Err()
+}
+
+func (s *server) reply(resp Response) {
+	b, _ := json.Marshal(resp)
+	s.wlock.Lock()
+	s.w.Write(append(b, '\n'))
+	s.wlock.Unlock()
+}
+
+func (s *server) result(id int64, v any, err error) {
+	if err != nil {
+		s.reply(Response{ID: id, Error: toError(err)})
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		s.reply(Response{ID: id, Error: toError(err)})
+		return
+	}
+	s.reply(Response{ID: id, Result: b})
+}
+
+func toError(err error) *Error {
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	return &Error{Code: CodeFailed, Message: err.Error()}
+}
+
+func (s *server) handle(ctx context.Context, id int64, method string, params json.RawMessage) {
+	switch method {
+	case MethodHello:
+		s.result(id, Hello{Name: s.name, Version: Version}, nil)
+	case MethodList:
+		containers, err := s.h.List()
+		s.result(id, containers, err)
+	case MethodAction:
+		var p ActionParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			s.result(id, nil, err)
+			return
+		}
+		v, err := s.h.Action(p.Container, p.Action, p.Args)
+		s.result(id, v, err)
+	case MethodCancel:
+		var p CancelParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			s.result(id, nil, err)
+			return
+		}
+		s.lock.Lock()
+		if cancel, ok := s.streams[p.Stream]; ok {
+			cancel()
+		}
+		s.lock.Unlock()
+		s.result(id, nil, nil)
+	case MethodEvents:
+		s.stream(ctx, id, func(ctx context.Context, send func(any)) error {
+			return s.h.Events(ctx, func(e Event) { send(e) })
+		})
+	case MethodMetrics, MethodLogs:
+		var p ContainerParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			s.result(id, nil, err)
+			return
+		}
+		s.stream(ctx, id, func(ctx context.Context, send func(any)) error {
+			if method == MethodLogs {
+				return s.h.Logs(ctx, p.Container, func(l LogLine) { send(l) })
+			}
+			return s.h.Metrics(ctx, p.Container, func(m Metrics) { send(m) })
+		})
+	default:
+		s.result(id, nil, &Error{Code: CodeNotImplemented, Message: "unknown method " + method})
+	}
+}
+
+// stream runs fn until it returns or the stream is canceled, then ends
+// the stream
+func (s *server) stream(ctx context.Context, id int64, fn func(ctx context.Context, send func(any)) error) {
+	var mu sync.Mutex // no results once the stream ended
+	ended := false
+	err := fn(ctx, func(v any) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !ended && ctx.Err() == nil {
+			s.result(id, v, nil)
+		}
+	})
+	mu.Lock()
+	ended = true
+	mu.Unlock()
+
+	if err != nil && ctx.Err() == nil {
+		s.reply(Response{ID: id, Error: toError(err)})
+		return
+	}
+	s.reply(Response{ID: id, End: true})
+}
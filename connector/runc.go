@@ -5,15 +5,21 @@ package connector
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+	"unsafe"
 
 	"github.com/Betzalel75/ctop/connector/collector"
 	"github.com/Betzalel75/ctop/connector/manager"
 	"github.com/Betzalel75/ctop/container"
 	"github.com/opencontainers/runc/libcontainer"
+	"github.com/opencontainers/runc/libcontainer/configs"
+	"golang.org/x/sys/unix"
 )
 
 func init() { enabled["runc"] = NewRunc }
@@ -75,9 +81,15 @@ func NewRunc() (Connector, error) {
 		containers:    make(map[string]*container.Container),
 		libContainers: make(map[string]libcontainer.Container),
 		closed:        make(chan struct{}),
+		needsRefresh:  make(chan string, 128),
 		lock:          sync.RWMutex{},
 	}
 
+	if err := cm.watch(); err != nil {
+		return nil, err
+	}
+	// processes exiting leave no trace in the runc root, refresh the
+	// status of known containers periodically
 	go func() {
 		for {
 			select {
@@ -93,9 +105,141 @@ func NewRunc() (Connector, error) {
 	return cm, nil
 }
 
+// watch the runc root for containers being created and destroyed, and
+// container directories for state changes, queueing any container
+// found for refresh
+func (cm *Runc) watch() error {
+	// non-blocking for the runtime poller to unblock reads on close
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return fmt.Errorf("cannot watch runc root: %v", err)
+	}
+	f := os.NewFile(uintptr(fd), "inotify")
+
+	rootWd, err := unix.InotifyAddWatch(fd, cm.opts.root,
+		unix.IN_CREATE|unix.IN_DELETE|unix.IN_MOVED_TO|unix.IN_MOVED_FROM|unix.IN_DELETE_SELF|unix.IN_ONLYDIR)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("cannot watch runc root: %v", err)
+	}
+
+	// container directories by watch descriptor; runc writes state.json
+	// anew on each state change
+	dirs := make(map[int]string)
+	watchDir := func(id string) {
+		wd, err := unix.InotifyAddWatch(fd, filepath.Join(cm.opts.root, id),
+			unix.IN_MOVED_TO|unix.IN_CLOSE_WRITE|unix.IN_ONLYDIR)
+		if err != nil {
+			log.Debugf("cannot watch container %s: %s", id, err)
+			return
+		}
+		dirs[wd] = id
+	}
+
+	// discover container directories, watching them
+	scan := func() ([]string, error) {
+		list, err := os.ReadDir(cm.opts.root)
+		if err != nil {
+			return nil, err
+		}
+		var ids []string
+		for _, i := range list {
+			if i.IsDir() {
+				watchDir(i.Name())
+				ids = append(ids, i.Name())
+			}
+		}
+		return ids, nil
+	}
+
+	// initial discovery, after the watch is set to not miss any container
+	initial, err := scan()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	go func() {
+		<-cm.closed
+		f.Close()
+	}()
+
+	go func() {
+		for _, id := range initial {
+			cm.queue(id)
+		}
+		log.Debugf("queued %d containers for refresh", len(initial))
+
+		buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+		for {
+			n, err := f.Read(buf)
+			if err != nil {
+				return // closed
+			}
+			for off := 0; off+unix.SizeofInotifyEvent <= n; {
+				ev := (*unix.InotifyEvent)(unsafe.Pointer(&buf[off]))
+				start := off + unix.SizeofInotifyEvent
+				off = start + int(ev.Len)
+				name := strings.TrimRight(string(buf[start:off]), "\x00")
+				wd := int(ev.Wd)
+
+				switch {
+				case ev.Mask&unix.IN_Q_OVERFLOW != 0:
+					// events were dropped, rediscover all containers
+					log.Warningf("runc root %s: inotify queue overflow, rescanning", cm.opts.root)
+					ids, err := scan()
+					if err != nil {
+						log.Errorf("cannot rescan runc root: %s", err)
+					}
+					for _, id := range ids {
+						cm.queue(id)
+					}
+					cm.refreshAll() // removed containers
+				case wd == rootWd && ev.Mask&(unix.IN_DELETE_SELF|unix.IN_IGNORED) != 0:
+					log.Errorf("runc root %s removed", cm.opts.root)
+					cm.close()
+					return
+				case wd == rootWd && ev.Mask&unix.IN_ISDIR != 0:
+					if ev.Mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 {
+						watchDir(name)
+					}
+					cm.queue(name)
+				case ev.Mask&unix.IN_IGNORED != 0:
+					delete(dirs, wd) // directory removed
+				case name == "state.json":
+					if id, ok := dirs[wd]; ok {
+						cm.queue(id)
+					}
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// queue a container for refresh, unless closed
+func (cm *Runc) queue(id string) {
+	select {
+	case <-cm.closed:
+	case cm.needsRefresh <- id:
+	}
+}
+
+// close the connector, for it to be reconnected
+func (cm *Runc) close() {
+	select {
+	case <-cm.closed:
+	default:
+		close(cm.closed)
+	}
+}
+
 func (cm *Runc) GetLibc(id string) libcontainer.Container {
 	// return previously loaded container
+	cm.lock.RLock()
 	libc, ok := cm.libContainers[id]
+	cm.lock.RUnlock()
 	if ok {
 		return libc
 	}
@@ -115,6 +259,12 @@ func (cm *Runc) GetLibc(id string) libcontainer.Container {
 
 // update a ctop container from libcontainer
 func (cm *Runc) refresh(id string) {
+	// remove container once its state directory is gone
+	if _, err := os.Stat(filepath.Join(cm.opts.root, id)); errors.Is(err, os.ErrNotExist) {
+		cm.delByID(id)
+		return
+	}
+
 	libc := cm.GetLibc(id)
 	if libc == nil {
 		return
@@ -132,7 +282,7 @@ func (cm *Runc) refresh(id string) {
 	if err != nil {
 		log.Warningf("failed to read status for container: %s\n", err)
 	} else {
-		c.SetState(status.String())
+		c.SetState(runcState(status))
 	}
 
 	state, err := libc.State()
@@ -140,43 +290,85 @@ func (cm *Runc) refresh(id string) {
 		log.Warningf("failed to read state for container: %s\n", err)
 	} else {
 		c.SetMeta("created", state.BaseState.Created.Format("Mon Jan 2 15:04:05 2006"))
+		c.SetMeta("cgroup", runcCgroup(state.CgroupPaths))
 	}
 
 	conf := libc.Config()
 	c.SetMeta("rootfs", conf.Rootfs)
+	c.SetMeta("namespaces", namespacesFormat(conf.Namespaces))
+	c.SetMeta("mounts", mountsFormat(conf.Mounts))
+	if conf.Capabilities != nil {
+		c.SetMeta("capabilities", strings.Join(conf.Capabilities.Effective, "\n"))
+	}
 }
 
-// Read runc root, creating any new containers
-func (cm *Runc) refreshAll() {
-	list, err := os.ReadDir(cm.opts.root)
-	if err != nil {
-		log.Errorf("%s (%T)", err.Error(), err)
-		close(cm.closed)
-		return
+// runcState maps a libcontainer status to a ctop container state
+func runcState(status libcontainer.Status) string {
+	switch status {
+	case libcontainer.Stopped:
+		return "exited"
+	case libcontainer.Pausing:
+		return "paused"
 	}
+	return status.String()
+}
 
-	for _, i := range list {
-		if i.IsDir() {
-			name := i.Name()
-			// attempt to load
-			libc := cm.GetLibc(name)
-			if libc == nil {
-				continue
-			}
-			_ = cm.MustGet(i.Name()) // ensure container exists
+// runcCgroup returns the cgroup path of a container, the unified one
+// on cgroup v2
+func runcCgroup(paths map[string]string) string {
+	for _, key := range []string{"", "memory", "cpu"} {
+		if p, ok := paths[key]; ok {
+			return p
+		}
+	}
+	return ""
+}
+
+func namespacesFormat(namespaces configs.Namespaces) string {
+	var s []string
+	for _, ns := range namespaces {
+		name := strings.ToLower(strings.TrimPrefix(string(ns.Type), "NEW"))
+		if ns.Path != "" {
+			name += " " + ns.Path // joined namespace
 		}
+		s = append(s, name)
+	}
+	sort.Strings(s)
+	return strings.Join(s, ", ")
+}
+
+func mountsFormat(mounts []*configs.Mount) string {
+	var s []string
+	for _, m := range mounts {
+		s = append(s, fmt.Sprintf("%s -> %s (%s)", m.Source, m.Destination, m.Device))
 	}
+	return strings.Join(s, "\n")
+}
 
-	// queue all existing containers for refresh
+// Queue all known containers for refresh
+func (cm *Runc) refreshAll() {
+	cm.lock.RLock()
+	ids := make([]string, 0, len(cm.containers))
 	for id := range cm.containers {
-		cm.needsRefresh <- id
+		ids = append(ids, id)
+	}
+	cm.lock.RUnlock()
+
+	for _, id := range ids {
+		cm.queue(id)
 	}
-	log.Debugf("queued %d containers for refresh", len(cm.containers))
 }
 
+// Loop refreshes queued containers, the only goroutine creating and
+// removing them
 func (cm *Runc) Loop() {
-	for id := range cm.needsRefresh {
-		cm.refresh(id)
+	for {
+		select {
+		case <-cm.closed:
+			return
+		case id := <-cm.needsRefresh:
+			cm.refresh(id)
+		}
 	}
 }
 
@@ -190,7 +382,7 @@ func (cm *Runc) MustGet(id string) *container.Container {
 		collector := collector.NewRunc(libc)
 
 		// create container
-		manager := manager.NewRunc()
+		manager := manager.NewRunc(libc)
 		c = container.New(id, collector, manager)
 
 		name := libc.ID()
@@ -214,10 +406,14 @@ func (cm *Runc) MustGet(id string) *container.Container {
 // Remove containers by ID
 func (cm *Runc) delByID(id string) {
 	cm.lock.Lock()
+	c, ok := cm.containers[id]
 	delete(cm.containers, id)
 	delete(cm.libContainers, id)
 	cm.lock.Unlock()
-	log.Infof("removed dead container: %s", id)
+	if ok {
+		c.SetState("exited") // stops the collector
+		log.Infof("removed dead container: %s", id)
+	}
 }
 
 // Runc implements Connector
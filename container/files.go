@@ -0,0 +1,345 @@
+package container
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Betzalel75/ctop/models"
+)
+
+// Changes returns the filesystem changes of this container relative to its image
+func (c *Container) Changes() ([]models.FileChange, error) {
+	return c.manager.Changes()
+}
+
+// ListFiles lists the container filesystem below root by streaming its
+// archive. At most limit entries are returned; the second return value
+// reports whether the listing was truncated
+func (c *Container) ListFiles(root string, limit int) ([]models.FileEntry, bool, error) {
+	root = path.Clean("/" + root)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(c.manager.Download(ctx, root, pw))
+	}()
+	defer pr.Close()
+
+	var files []models.FileEntry
+	tr := tar.NewReader(pr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, false, nil
+		}
+		if err != nil {
+			return files, false, err
+		}
+		if len(files) >= limit {
+			return files, true, nil
+		}
+
+		// archive entries are named relative to the parent of root
+		files = append(files, models.FileEntry{
+			Path:       path.Join(path.Dir(root), hdr.Name),
+			Size:       hdr.Size,
+			Mode:       hdr.FileInfo().Mode(),
+			IsDir:      hdr.Typeflag == tar.TypeDir,
+			LinkTarget: hdr.Linkname,
+		})
+	}
+}
+
+// Stat returns the entry of path p in the container filesystem
+func (c *Container) Stat(p string) (models.FileEntry, error) {
+	files, _, err := c.ListFiles(p, 1)
+	if err != nil {
+		return models.FileEntry{}, err
+	}
+	if len(files) == 0 {
+		return models.FileEntry{}, fmt.Errorf("%s: %w", p, os.ErrNotExist)
+	}
+	return files[0], nil
+}
+
+// CopyTarget resolves the destination of a copy into (in) or out of the
+// container the way `docker cp` does, and reports whether it already exists
+func (c *Container) CopyTarget(in bool, src, dst string) (target string, exists bool, err error) {
+	if in {
+		target = path.Clean("/" + dst)
+		st, err := c.Stat(target)
+		if errors.Is(err, os.ErrNotExist) {
+			return target, false, nil
+		}
+		if err != nil {
+			return "", false, err
+		}
+		if !st.IsDir {
+			return target, true, nil
+		}
+		target = path.Join(target, filepath.Base(src))
+		if _, err = c.Stat(target); errors.Is(err, os.ErrNotExist) {
+			return target, false, nil
+		}
+		return target, err == nil, err
+	}
+
+	target = filepath.Clean(dst)
+	fi, err := os.Stat(target)
+	if os.IsNotExist(err) {
+		return target, false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	if !fi.IsDir() {
+		return target, true, nil
+	}
+	target = filepath.Join(target, path.Base(path.Clean("/"+src)))
+	if _, err = os.Lstat(target); os.IsNotExist(err) {
+		return target, false, nil
+	}
+	return target, err == nil, err
+}
+
+// CopyTo copies a local file or directory into the container filesystem.
+// progress, if not nil, is called with the number of bytes copied so far
+func (c *Container) CopyTo(ctx context.Context, src, dst string, progress func(done, total int64)) error {
+	target, _, err := c.CopyTarget(true, src, dst)
+	if err != nil {
+		return err
+	}
+
+	p := &copyProgress{total: localSize(src), fn: progress}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeTar(pw, src, path.Base(target), p))
+	}()
+	defer pr.Close()
+
+	return c.manager.Upload(ctx, path.Dir(target), pr)
+}
+
+// CopyFrom copies a file or directory from the container filesystem to a
+// local path. If dst is an existing directory, src is copied into it.
+// progress, if not nil, is called with the number of bytes copied so far;
+// total is -1 when copying a directory
+func (c *Container) CopyFrom(ctx context.Context, src, dst string, progress func(done, total int64)) error {
+	src = path.Clean("/" + src)
+	if src == "/" {
+		return fmt.Errorf("cannot copy container root filesystem")
+	}
+	st, err := c.Stat(src)
+	if err != nil {
+		return err
+	}
+	target, _, err := c.CopyTarget(false, src, dst)
+	if err != nil {
+		return err
+	}
+
+	p := &copyProgress{total: st.Size, fn: progress}
+	if st.IsDir {
+		p.total = -1
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(c.manager.Download(ctx, src, pw))
+	}()
+	defer pr.Close()
+
+	return extractTar(pr, target, p)
+}
+
+// copyProgress accumulates the number of file content bytes copied
+type copyProgress struct {
+	done  int64
+	total int64
+	fn    func(done, total int64)
+}
+
+func (p *copyProgress) add(n int) {
+	p.done += int64(n)
+	if p.fn != nil && n > 0 {
+		p.fn(p.done, p.total)
+	}
+}
+
+// progressReader reports bytes read from a file to a copyProgress
+type progressReader struct {
+	r io.Reader
+	p *copyProgress
+}
+
+func (pr progressReader) Read(b []byte) (int, error) {
+	n, err := pr.r.Read(b)
+	pr.p.add(n)
+	return n, err
+}
+
+// size of the regular files below a local path
+func localSize(src string) (size int64) {
+	filepath.Walk(src, func(_ string, fi os.FileInfo, err error) error {
+		if err == nil && fi.Mode().IsRegular() {
+			size += fi.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// writeTar writes an archive of a local file or directory to w, naming
+// its top-level entry name
+func writeTar(w io.Writer, src, name string, p *copyProgress) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(src, func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(fi, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(name, filepath.ToSlash(rel))
+		if fi.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, progressReader{f, p})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// extractTar writes the archive of a single file or directory to dst,
+// renaming its top-level entry. Entries are never written through a
+// symlink leading out of dst, and symlinks pointing out of it, such as
+// absolute ones, are skipped
+func extractTar(r io.Reader, dst string, p *copyProgress) error {
+	dst = filepath.Clean(dst)
+	root, err := resolvePath(dst)
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, rel, _ := strings.Cut(path.Clean(hdr.Name), "/")
+		target := filepath.Join(dst, filepath.FromSlash(rel))
+		if !within(dst, target) {
+			return fmt.Errorf("invalid archive entry: %s", hdr.Name)
+		}
+		// parent directories and the entry itself may be symlinks
+		// created by earlier entries
+		real, err := resolvePath(target)
+		if err != nil {
+			return err
+		}
+		if !within(root, real) {
+			return fmt.Errorf("invalid archive entry: %s: leads out of %s", hdr.Name, dst)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, hdr.FileInfo().Mode().Perm()|0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(progressReader{tr, p}, target, hdr.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			link := filepath.FromSlash(hdr.Linkname)
+			if filepath.IsAbs(link) || !within(dst, filepath.Join(filepath.Dir(target), link)) {
+				log.Warningf("skipping symlink %s -> %s: target out of %s", hdr.Name, hdr.Linkname, dst)
+				continue
+			}
+			os.Remove(target)
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// within returns whether path p is dir or below it
+func within(dir, p string) bool {
+	return p == dir || strings.HasPrefix(p, dir+string(filepath.Separator))
+}
+
+// resolvePath returns p with symlinks evaluated; missing trailing
+// components, which cannot be symlinks, are kept as is
+func resolvePath(p string) (string, error) {
+	real, err := filepath.EvalSymlinks(p)
+	if err == nil {
+		return real, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	parent := filepath.Dir(p)
+	if parent == p {
+		return p, nil
+	}
+	real, err = resolvePath(parent)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(real, filepath.Base(p)), nil
+}
+
+func writeFile(r io.Reader, target string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
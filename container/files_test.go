@@ -0,0 +1,93 @@
+package container
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type tarEntry struct {
+	name string
+	typ  byte
+	link string
+	body string
+}
+
+func makeTar(t *testing.T, entries []tarEntry) *bytes.Buffer {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Typeflag: e.typ, Linkname: e.link, Mode: 0644, Size: int64(len(e.body))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestExtractTarSymlinks(t *testing.T) {
+	tmp := t.TempDir()
+	outside := filepath.Join(tmp, "outside")
+	if err := os.Mkdir(outside, 0755); err != nil {
+		t.Fatal(err)
+	}
+	dst := filepath.Join(tmp, "dst")
+
+	archive := makeTar(t, []tarEntry{
+		{name: "src/", typ: tar.TypeDir},
+		{name: "src/abs", typ: tar.TypeSymlink, link: outside},
+		{name: "src/abs/evil", typ: tar.TypeReg, body: "x"},
+		{name: "src/up", typ: tar.TypeSymlink, link: "../outside"},
+		{name: "src/up/evil", typ: tar.TypeReg, body: "x"},
+		{name: "src/file", typ: tar.TypeReg, body: "ok"},
+		{name: "src/rel", typ: tar.TypeSymlink, link: "file"},
+	})
+	if err := extractTar(archive, dst, &copyProgress{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if entries, _ := os.ReadDir(outside); len(entries) > 0 {
+		t.Fatalf("extraction wrote out of dst: %v", entries)
+	}
+	for _, name := range []string{"abs", "up"} {
+		if fi, err := os.Lstat(filepath.Join(dst, name)); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+			t.Errorf("symlink %s out of dst created", name)
+		}
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "rel")); err != nil || link != "file" {
+		t.Errorf("symlink in dst: got %q, %v", link, err)
+	}
+}
+
+func TestExtractTarThroughSymlink(t *testing.T) {
+	tmp := t.TempDir()
+	outside := filepath.Join(tmp, "outside")
+	dst := filepath.Join(tmp, "dst")
+	for _, dir := range []string{outside, dst} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a symlink already in dst leading out of it
+	if err := os.Symlink(outside, filepath.Join(dst, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := makeTar(t, []tarEntry{
+		{name: "src/link/evil", typ: tar.TypeReg, body: "x"},
+	})
+	if err := extractTar(archive, dst, &copyProgress{}); err == nil {
+		t.Fatal("expected an error writing through a symlink out of dst")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "evil")); err == nil {
+		t.Fatal("extraction wrote out of dst")
+	}
+}
@@ -0,0 +1,27 @@
+package container
+
+import (
+	"sync"
+
+	"github.com/Betzalel75/ctop/models"
+)
+
+// most recent health probe results, as reported by the runtime
+type healthLog struct {
+	sync.Mutex
+	checks []models.HealthCheck
+}
+
+// SetHealthLog sets the most recent health probe results
+func (c *Container) SetHealthLog(checks []models.HealthCheck) {
+	c.health.Lock()
+	c.health.checks = checks
+	c.health.Unlock()
+}
+
+// HealthLog returns the most recent health probe results, oldest first
+func (c *Container) HealthLog() []models.HealthCheck {
+	c.health.Lock()
+	defer c.health.Unlock()
+	return append([]models.HealthCheck(nil), c.health.checks...)
+}
@@ -0,0 +1,38 @@
+package container
+
+import (
+	"sync"
+
+	"github.com/Betzalel75/ctop/models"
+)
+
+// HistoryLen is the number of metrics samples kept per container
+const HistoryLen = 120
+
+// recent metrics samples of a container, oldest first
+type history struct {
+	sync.Mutex
+	samples []models.Metrics
+}
+
+func (h *history) add(m models.Metrics) {
+	h.Lock()
+	defer h.Unlock()
+	if len(h.samples) >= HistoryLen {
+		h.samples = append(h.samples[:0], h.samples[1:]...)
+	}
+	h.samples = append(h.samples, m)
+}
+
+func (h *history) clear() {
+	h.Lock()
+	defer h.Unlock()
+	h.samples = nil
+}
+
+// History returns recent metrics samples of the container, oldest first
+func (c *Container) History() []models.Metrics {
+	c.hist.Lock()
+	defer c.hist.Unlock()
+	return append([]models.Metrics(nil), c.hist.samples...)
+}
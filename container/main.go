@@ -1,6 +1,8 @@
 package container
 
 import (
+	"syscall"
+
 	"github.com/Betzalel75/ctop/connector/collector"
 	"github.com/Betzalel75/ctop/connector/manager"
 	"github.com/Betzalel75/ctop/cwidgets"
@@ -27,6 +29,8 @@ type Container struct {
 	updater   cwidgets.WidgetUpdater
 	collector collector.Collector
 	manager   manager.Manager
+	hist      *history
+	health    *healthLog
 }
 
 func New(id string, collector collector.Collector, manager manager.Manager) *Container {
@@ -43,6 +47,8 @@ func New(id string, collector collector.Collector, manager manager.Manager) *Con
 		updater:   widgets,
 		collector: collector,
 		manager:   manager,
+		hist:      &history{},
+		health:    &healthLog{},
 	}
 }
 
@@ -89,10 +95,12 @@ func (c *Container) Read(stream chan models.Metrics) {
 	go func() {
 		for metrics := range stream {
 			c.Metrics = metrics
+			c.hist.add(metrics)
 			c.updater.SetMetrics(metrics)
 		}
 		log.Infof("reader stopped for container: %s", c.Id)
 		c.Metrics = models.NewMetrics()
+		c.hist.clear()
 		c.Widgets.Reset()
 	}()
 	log.Infof("reader started for container: %s", c.Id)
@@ -162,3 +170,22 @@ func (c *Container) Restart() {
 func (c *Container) Exec(cmd []string) error {
 	return c.manager.Exec(cmd)
 }
+
+// Inspect returns the runtime's inspect document for this container
+func (c *Container) Inspect() (any, error) {
+	return c.manager.Inspect()
+}
+
+// Top lists the processes running in this container
+func (c *Container) Top() ([]models.Process, error) {
+	return c.manager.Top()
+}
+
+// Signal sends sig to a process of this container
+func (c *Container) Signal(pid int, sig syscall.Signal) error {
+	if err := c.manager.Signal(pid, sig); err != nil {
+		log.Warningf("container %s: %v", c.Id, err)
+		return err
+	}
+	return nil
+}
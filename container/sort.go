@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strconv"
 
 	"github.com/Betzalel75/ctop/config"
 )
@@ -79,6 +80,22 @@ var Sorters = map[string]sortMethod{
 		}
 		return stateMap[c1state] > stateMap[c2state]
 	},
+	"restarts": func(c1, c2 *Container) bool {
+		// Use secondary sort method if equal values
+		r1, _ := strconv.Atoi(c1.GetMeta("restarts"))
+		r2, _ := strconv.Atoi(c2.GetMeta("restarts"))
+		if r1 == r2 {
+			return nameSorter(c1, c2)
+		}
+		return r1 > r2
+	},
+	"throttle": func(c1, c2 *Container) bool {
+		// Use secondary sort method if equal values
+		if c1.CPUThrottledTime == c2.CPUThrottledTime {
+			return nameSorter(c1, c2)
+		}
+		return c1.CPUThrottledTime > c2.CPUThrottledTime
+	},
 	"uptime": func(c1, c2 *Container) bool {
 		// Use secondary sort method if equal values
 		c1Uptime := c1.GetMeta("uptime")
@@ -70,6 +70,32 @@ func (gc *GridCursor) Reset() {
 	}
 }
 
+// Select moves the cursor to the container with the given ID, returning
+// false if it is not displayed
+func (gc *GridCursor) Select(id string) bool {
+	for n, c := range gc.filtered {
+		if c.Id != id {
+			continue
+		}
+		if active := gc.Selected(); active != nil {
+			active.Widgets.UnHighlight()
+		}
+		gc.selectedID = id
+		c.Widgets.Highlight()
+
+		// scroll the row into view
+		if n < cGrid.Offset {
+			cGrid.Offset = n
+		}
+		if n >= cGrid.Offset+cGrid.MaxRows() {
+			cGrid.Offset = n - cGrid.MaxRows() + 1
+		}
+		cGrid.Align()
+		return true
+	}
+	return false
+}
+
 // Idx returns current cursor index
 func (gc *GridCursor) Idx() int {
 	for n, c := range gc.filtered {
@@ -195,3 +221,27 @@ func (gc *GridCursor) pgCount() int {
 	}
 	return pages
 }
+
+// Scroll moves the page by n rows, moving the cursor along if it would
+// leave the page
+func (gc *GridCursor) Scroll(n int) {
+	gc.isScrolling = true
+	defer func() { gc.isScrolling = false }()
+
+	maxOffset := int(math.Max(0, float64(gc.Len()-cGrid.MaxRows())))
+	offset := int(math.Min(math.Max(0, float64(cGrid.Offset+n)), float64(maxOffset)))
+	if offset == cGrid.Offset {
+		return
+	}
+	cGrid.Offset = offset
+
+	idx := gc.Idx()
+	if idx < offset {
+		idx = offset
+	}
+	if last := offset + cGrid.MaxRows() - 1; idx > last {
+		idx = last
+	}
+	gc.Select(gc.filtered[idx].Id)
+	ui.Render(cGrid)
+}
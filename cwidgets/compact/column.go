@@ -9,20 +9,24 @@ import (
 
 var (
 	allCols = map[string]NewCompactColFn{
-		"status":  NewStatus,
-		"name":    NewNameCol,
-		"id":      NewCIDCol,
-		"image":   NewImageCol,
-		"ports":   NewPortsCol,
-		"IPs":     NewIpsCol,
-		"created": NewCreatedCol,
-		"cpu":     NewCPUCol,
-		"cpus":    NewCpuScaledCol,
-		"mem":     NewMemCol,
-		"net":     NewNetCol,
-		"io":      NewIOCol,
-		"pids":    NewPIDCol,
-		"uptime":  NewUptimeCol,
+		"status":   NewStatus,
+		"name":     NewNameCol,
+		"id":       NewCIDCol,
+		"image":    NewImageCol,
+		"ports":    NewPortsCol,
+		"IPs":      NewIpsCol,
+		"created":  NewCreatedCol,
+		"cpu":      NewCPUCol,
+		"cpus":     NewCpuScaledCol,
+		"mem":      NewMemCol,
+		"net":      NewNetCol,
+		"io":       NewIOCol,
+		"pids":     NewPIDCol,
+		"uptime":   NewUptimeCol,
+		"restarts": NewRestartsCol,
+		"exit":     NewExitCol,
+		"health":   NewHealthCol,
+		"throttle": NewThrottleCol,
 	}
 )
 
@@ -38,6 +42,9 @@ func newRowWidgets() []CompactCol {
 			panic("no such widget name: %s" + name)
 		}
 		cols[n] = wFn()
+		if fc, ok := cols[n].(formattedCol); ok {
+			fc.setFormat(config.GetColumnFormat(name))
+		}
 	}
 
 	return cols
@@ -53,3 +60,10 @@ type CompactCol interface {
 	SetMeta(models.Meta)
 	SetMetrics(models.Metrics)
 }
+
+// formattedCol is a column with configurable width and display
+// settings, see config.ColumnFormat
+type formattedCol interface {
+	setFormat(config.ColumnFormat)
+	widthBounds() (min, max int) // bounds of an automatic width, 0 if none
+}
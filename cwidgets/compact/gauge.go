@@ -3,6 +3,7 @@ package compact
 import (
 	"fmt"
 
+	"github.com/Betzalel75/ctop/config"
 	"github.com/Betzalel75/ctop/cwidgets"
 	"github.com/Betzalel75/ctop/models"
 
@@ -46,7 +47,7 @@ func NewMemCol() CompactCol {
 
 func (w *MemCol) SetMetrics(m models.Metrics) {
 	w.BarColor = ui.ThemeAttr("gauge.bar.bg")
-	w.Label = fmt.Sprintf("%s / %s", cwidgets.ByteFormat64Short(m.MemUsage), cwidgets.ByteFormat64Short(m.MemLimit))
+	w.Label = fmt.Sprintf("%s / %s", w.bytes(m.MemUsage), w.bytes(m.MemLimit))
 	w.Percent = m.MemPercent
 }
 
@@ -54,10 +55,11 @@ type GaugeCol struct {
 	*ui.Gauge
 	header string
 	fWidth int
+	format config.ColumnFormat
 }
 
 func NewGaugeCol(header string) *GaugeCol {
-	g := &GaugeCol{ui.NewGauge(), header, 0}
+	g := &GaugeCol{Gauge: ui.NewGauge(), header: header}
 	g.Height = 1
 	g.Border = false
 	g.PaddingBottom = 0
@@ -71,6 +73,15 @@ func (w *GaugeCol) Reset() {
 }
 
 func (w *GaugeCol) Buffer() ui.Buffer {
+	// number style draws the label alone
+	if w.format.Style == "number" {
+		percent := w.Percent
+		w.Percent = 0
+		buf := w.Gauge.Buffer()
+		w.Percent = percent
+		return buf
+	}
+
 	// if bar would not otherwise be visible, set a minimum
 	// percentage value and low-contrast color for structure
 	if w.Percent < 5 {
@@ -81,6 +92,27 @@ func (w *GaugeCol) Buffer() ui.Buffer {
 	return w.Gauge.Buffer()
 }
 
+// GaugeCol implements formattedCol
+func (w *GaugeCol) setFormat(f config.ColumnFormat) {
+	w.format = f
+	if f.Width > 0 {
+		w.fWidth = f.Width
+	}
+	switch f.Align {
+	case "left":
+		w.LabelAlign = ui.AlignLeft
+	case "right":
+		w.LabelAlign = ui.AlignRight
+	}
+}
+
+func (w *GaugeCol) widthBounds() (int, int) { return w.format.MinWidth, w.format.MaxWidth }
+
+// format a byte count in configured units
+func (w *GaugeCol) bytes(n int64) string {
+	return cwidgets.ByteFormatUnits(n, w.format.Units == "si", w.format.Precision)
+}
+
 // GaugeCol implements CompactCol
 func (w *GaugeCol) SetMeta(models.Meta)       {}
 func (w *GaugeCol) SetMetrics(models.Metrics) {}
@@ -6,17 +6,25 @@ import (
 
 type CompactGrid struct {
 	ui.GridBufferer
-	header *CompactHeader
-	cols   []CompactCol // reference columns
-	Rows   []RowBufferer
-	X, Y   int
-	Width  int
-	Height int
-	Offset int // starting row offset
+	header  *CompactHeader
+	cols    []CompactCol // reference columns
+	newCols func() []CompactCol
+	Rows    []RowBufferer
+	X, Y    int
+	Width   int
+	Height  int
+	Offset  int // starting row offset
+	Reserve int // screen lines reserved below the grid
 }
 
 func NewCompactGrid() *CompactGrid {
-	cg := &CompactGrid{header: NewCompactHeader()}
+	return NewCompactGridCols(newRowWidgets)
+}
+
+// NewCompactGridCols returns a grid of rows built with newCols rather
+// than the configured container columns, see NewCompactRowCols
+func NewCompactGridCols(newCols func() []CompactCol) *CompactGrid {
+	cg := &CompactGrid{header: NewCompactHeader(), newCols: newCols}
 	cg.rebuildHeader()
 	return cg
 }
@@ -31,6 +39,7 @@ func (cg *CompactGrid) Align() {
 	// update row ypos, width recursively
 	colWidths := cg.calcWidths()
 	for _, r := range cg.pageRows() {
+		r.SetX(cg.X + rowPadding)
 		r.SetY(y)
 		y += r.GetHeight()
 		r.SetWidths(cg.Width, colWidths)
@@ -46,36 +55,104 @@ func (cg *CompactGrid) GetHeight() int { return len(cg.Rows) + cg.header.Height
 func (cg *CompactGrid) SetX(x int)     { cg.X = x }
 func (cg *CompactGrid) SetY(y int)     { cg.Y = y }
 func (cg *CompactGrid) SetWidth(w int) { cg.Width = w }
-func (cg *CompactGrid) MaxRows() int   { return ui.TermHeight() - cg.header.Height - cg.Y }
+func (cg *CompactGrid) MaxRows() int {
+	return ui.TermHeight() - cg.header.Height - cg.Y - cg.Reserve
+}
 
 // calculate and return per-column width
 func (cg *CompactGrid) calcWidths() []int {
-	var autoCols int
-	width := cg.Width
+	width := cg.Width - colSpacing*len(cg.cols)
 	colWidths := make([]int, len(cg.cols))
+	auto := make(map[int]bool)
 
 	for n, w := range cg.cols {
 		colWidths[n] = w.FixedWidth()
 		width -= w.FixedWidth()
 		if w.FixedWidth() == 0 {
-			autoCols++
+			auto[n] = true
 		}
 	}
 
-	spacing := colSpacing * len(cg.cols)
-	autoWidth := (width - spacing) / autoCols
-	for n, val := range colWidths {
-		if val == 0 {
-			colWidths[n] = autoWidth
+	// share remaining width among automatic columns; columns out of
+	// their bounds are set to them and the rest shared again
+	for len(auto) > 0 {
+		autoWidth := width / len(auto)
+		bounded := false
+		for n := range auto {
+			w := clampWidth(cg.cols[n], autoWidth)
+			if w != autoWidth {
+				colWidths[n] = w
+				width -= w
+				delete(auto, n)
+				bounded = true
+			}
+		}
+		if !bounded {
+			for n := range auto {
+				colWidths[n] = autoWidth
+			}
+			break
 		}
 	}
 	return colWidths
 }
 
+// clamp a width to the bounds of a column, if any
+func clampWidth(col CompactCol, w int) int {
+	fc, ok := col.(formattedCol)
+	if !ok {
+		return w
+	}
+	min, max := fc.widthBounds()
+	if max > 0 && w > max {
+		return max
+	}
+	if w < min {
+		return min
+	}
+	return w
+}
+
+// IsHeader reports whether screen line y is on the column header
+func (cg *CompactGrid) IsHeader(y int) bool {
+	return y >= cg.Y && y < cg.Y+cg.header.Height
+}
+
+// RowAt returns the index in Rows of the row displayed on screen line
+// y, -1 if none
+func (cg *CompactGrid) RowAt(y int) int {
+	n := y - cg.Y - cg.header.Height
+	if n < 0 || n >= cg.MaxRows() {
+		return -1
+	}
+	n += cg.Offset
+	if n >= len(cg.Rows) {
+		return -1
+	}
+	return n
+}
+
+// ColumnAt returns the index of the column displayed on screen column
+// x, -1 if none
+func (cg *CompactGrid) ColumnAt(x int) int {
+	pos := cg.X + rowPadding
+	for n, w := range cg.calcWidths() {
+		if x >= pos && x < pos+w {
+			return n
+		}
+		pos += w + colSpacing
+	}
+	return -1
+}
+
 func (cg *CompactGrid) pageRows() (rows []RowBufferer) {
 	rows = append(rows, cg.header)
-	rows = append(rows, cg.Rows[cg.Offset:]...)
-	return rows
+	page := cg.Rows[cg.Offset:]
+	// rows past the page would draw over the reserved lines
+	if cg.Reserve > 0 && len(page) > cg.MaxRows() {
+		page = page[:max(cg.MaxRows(), 0)]
+	}
+	return append(rows, page...)
 }
 
 func (cg *CompactGrid) Buffer() ui.Buffer {
@@ -91,7 +168,7 @@ func (cg *CompactGrid) AddRows(rows ...RowBufferer) {
 }
 
 func (cg *CompactGrid) rebuildHeader() {
-	cg.cols = newRowWidgets()
+	cg.cols = cg.newCols()
 	cg.header.clearFieldPars()
 	for _, col := range cg.cols {
 		cg.header.addFieldPar(col.Header())
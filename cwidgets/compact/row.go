@@ -13,6 +13,7 @@ const rowPadding = 1
 var log = logging.Init()
 
 type RowBufferer interface {
+	SetX(int)
 	SetY(int)
 	SetWidths(int, []int)
 	GetHeight() int
@@ -25,12 +26,20 @@ type CompactRow struct {
 	X, Y   int
 	Height int
 	widths []int // column widths
+	marked bool  // marked for comparison
+	markX  int   // position of the mark, between the first columns
 }
 
 func NewCompactRow() *CompactRow {
+	return NewCompactRowCols(newRowWidgets())
+}
+
+// NewCompactRowCols returns a row of the given columns; the second
+// column is highlighted when fullRowCursor is disabled
+func NewCompactRowCols(cols []CompactCol) *CompactRow {
 	row := &CompactRow{
 		Bg:     NewRowBg(),
-		Cols:   newRowWidgets(),
+		Cols:   cols,
 		X:      rowPadding,
 		Height: 1,
 	}
@@ -59,7 +68,7 @@ func (row *CompactRow) Reset() {
 
 func (row *CompactRow) GetHeight() int { return row.Height }
 
-//func (row *CompactRow) SetX(x int)     { row.X = x }
+func (row *CompactRow) SetX(x int) { row.X = x }
 
 func (row *CompactRow) SetY(y int) {
 	if y == row.Y {
@@ -78,6 +87,9 @@ func (row *CompactRow) SetWidths(totalWidth int, widths []int) {
 
 	row.Bg.SetX(x)
 	row.Bg.SetWidth(totalWidth)
+	if len(widths) > 0 {
+		row.markX = x + widths[0]
+	}
 
 	for n, w := range row.Cols {
 		w.SetX(x)
@@ -92,9 +104,15 @@ func (row *CompactRow) Buffer() ui.Buffer {
 	for _, w := range row.Cols {
 		buf.Merge(w.Buffer())
 	}
+	if row.marked {
+		buf.Set(row.markX, row.Y, ui.Cell{Ch: '●', Fg: ui.ThemeAttr("status.ok"), Bg: row.Bg.Bg})
+	}
 	return buf
 }
 
+// SetMarked shows or hides the mark of a row selected for comparison
+func (row *CompactRow) SetMarked(marked bool) { row.marked = marked }
+
 func (row *CompactRow) Highlight() {
 	row.Cols[1].Highlight()
 	if config.GetSwitchVal("fullRowCursor") {
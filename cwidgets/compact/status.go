@@ -9,8 +9,11 @@ import (
 // Status indicator
 type Status struct {
 	*ui.Block
-	status []ui.Cell
-	health []ui.Cell
+	status  []ui.Cell
+	health  []ui.Cell
+	oomMeta bool // container was OOM killed, per runtime state or oom events
+	oomKill bool // OOM kills reported by cgroup memory stats
+	looping bool // container is crash-looping
 }
 
 func NewStatus() CompactCol {
@@ -27,22 +30,34 @@ func NewStatus() CompactCol {
 func (s *Status) Buffer() ui.Buffer {
 	buf := s.Block.Buffer()
 	buf.Set(s.InnerX(), s.InnerY(), s.health[0])
-	buf.Set(s.InnerX()+2, s.InnerY(), s.status[0])
+	if s.oomMeta || s.oomKill {
+		buf.Set(s.InnerX()+1, s.InnerY(), ui.Cell{Ch: '!', Fg: ui.ThemeAttr("status.danger") | ui.AttrBold, Bg: ui.ColorDefault})
+	}
+	if s.looping {
+		buf.Set(s.InnerX()+2, s.InnerY(), ui.Cell{Ch: '↻', Fg: ui.ThemeAttr("status.danger") | ui.AttrBold, Bg: ui.ColorDefault})
+	} else {
+		buf.Set(s.InnerX()+2, s.InnerY(), s.status[0])
+	}
 	return buf
 }
 
 func (s *Status) SetMeta(m models.Meta) {
 	s.setState(m.Get("state"))
 	s.setHealth(m.Get("health"))
+	s.oomMeta = m.Get("oomkilled") == "true" || m.Get("oomkills") != ""
+	s.looping = m.Get("crashloop") == "true"
+}
+
+func (s *Status) SetMetrics(m models.Metrics) {
+	s.oomKill = m.OOMKills > 0
 }
 
 // Status implements CompactCol
-func (s *Status) Reset()                    {}
-func (s *Status) SetMetrics(models.Metrics) {}
-func (s *Status) Highlight()                {}
-func (s *Status) UnHighlight()              {}
-func (s *Status) Header() string            { return "" }
-func (s *Status) FixedWidth() int           { return 3 }
+func (s *Status) Reset()          {}
+func (s *Status) Highlight()      {}
+func (s *Status) UnHighlight()    {}
+func (s *Status) Header() string  { return "" }
+func (s *Status) FixedWidth() int { return 3 }
 
 func (s *Status) setState(val string) {
 	color := ui.ColorDefault
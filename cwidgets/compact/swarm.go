@@ -0,0 +1,32 @@
+package compact
+
+// NewServiceCols returns the columns of a swarm services grid, reading
+// the meta set for each service
+func NewServiceCols() []CompactCol {
+	return []CompactCol{
+		NewStatus(),
+		NewNameCol(),
+		newMetaCol("MODE", "mode", 14),
+		newMetaCol("REPLICAS", "replicas", 8),
+		NewImageCol(),
+		newMetaCol("UPDATE", "update", 18),
+	}
+}
+
+// NewTaskCols returns the columns of a swarm tasks grid
+func NewTaskCols() []CompactCol {
+	return []CompactCol{
+		NewStatus(),
+		NewNameCol(),
+		newMetaCol("NODE", "node", 0),
+		newMetaCol("DESIRED", "desired", 9),
+		newMetaCol("CURRENT STATE", "current", 0),
+		newMetaCol("ERROR", "error", 0),
+	}
+}
+
+func newMetaCol(header, metaName string, width int) CompactCol {
+	c := &MetaCol{NewTextCol(header), metaName}
+	c.fWidth = width
+	return c
+}
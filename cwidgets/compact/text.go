@@ -2,11 +2,15 @@ package compact
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/Betzalel75/ctop/config"
 	"github.com/Betzalel75/ctop/cwidgets"
 	"github.com/Betzalel75/ctop/models"
 
 	ui "github.com/gizak/termui"
+	"github.com/mattn/go-runewidth"
 )
 
 // Column that shows container's meta property i.e. name, id, image tc.
@@ -49,6 +53,51 @@ func NewCreatedCol() CompactCol {
 	return c
 }
 
+func NewRestartsCol() CompactCol {
+	c := &MetaCol{NewTextCol("RESTARTS"), "restarts"}
+	c.fWidth = 8
+	return c
+}
+
+type ExitCol struct {
+	*TextCol
+}
+
+func NewExitCol() CompactCol {
+	c := &ExitCol{NewTextCol("EXIT")}
+	c.fWidth = 4
+	return c
+}
+
+func (w *ExitCol) SetMeta(m models.Meta) {
+	// exit code of the last run, meaningless while running
+	if m.Get("state") == "running" {
+		w.setText("-")
+		return
+	}
+	w.setText(m.Get("exit code"))
+}
+
+type HealthCol struct {
+	*TextCol
+}
+
+func NewHealthCol() CompactCol {
+	return &HealthCol{NewTextCol("HEALTH")}
+}
+
+func (w *HealthCol) SetMeta(m models.Meta) {
+	status := m.Get("health")
+	if status == "" {
+		w.setText("-") // no health check
+		return
+	}
+	if n := m.Get("failing streak"); n != "" && n != "0" {
+		status = fmt.Sprintf("%s (%s fails)", status, n)
+	}
+	w.setText(status)
+}
+
 type NetCol struct {
 	*TextCol
 }
@@ -58,7 +107,7 @@ func NewNetCol() CompactCol {
 }
 
 func (w *NetCol) SetMetrics(m models.Metrics) {
-	label := fmt.Sprintf("%s / %s", cwidgets.ByteFormat64Short(m.NetRx), cwidgets.ByteFormat64Short(m.NetTx))
+	label := fmt.Sprintf("%s / %s", w.bytes(m.NetRx), w.bytes(m.NetTx))
 	w.setText(label)
 }
 
@@ -71,7 +120,7 @@ func NewIOCol() CompactCol {
 }
 
 func (w *IOCol) SetMetrics(m models.Metrics) {
-	label := fmt.Sprintf("%s / %s", cwidgets.ByteFormat64Short(m.IOBytesRead), cwidgets.ByteFormat64Short(m.IOBytesWrite))
+	label := fmt.Sprintf("%s / %s", w.bytes(m.IOBytesRead), w.bytes(m.IOBytesWrite))
 	w.setText(label)
 }
 
@@ -89,6 +138,23 @@ func (w *PIDCol) SetMetrics(m models.Metrics) {
 	w.setText(fmt.Sprintf("%d", m.Pids))
 }
 
+type ThrottleCol struct {
+	*TextCol
+}
+
+func NewThrottleCol() CompactCol {
+	return &ThrottleCol{NewTextCol("THROTTLE")}
+}
+
+func (w *ThrottleCol) SetMetrics(m models.Metrics) {
+	if m.CPUPeriods == 0 {
+		w.setText("-") // no cpu quota set
+		return
+	}
+	t := time.Duration(m.CPUThrottledTime).Round(time.Millisecond)
+	w.setText(fmt.Sprintf("%d%% / %s", m.CPUThrottled, t))
+}
+
 type UptimeCol struct {
 	*TextCol
 }
@@ -105,6 +171,7 @@ type TextCol struct {
 	*ui.Par
 	header string
 	fWidth int
+	format config.ColumnFormat
 }
 
 func NewTextCol(header string) *TextCol {
@@ -139,6 +206,39 @@ func (w *TextCol) SetMetrics(models.Metrics) {}
 func (w *TextCol) Header() string            { return w.header }
 func (w *TextCol) FixedWidth() int           { return w.fWidth }
 
+func (w *TextCol) Buffer() ui.Buffer {
+	pad := w.Width - runewidth.StringWidth(w.Text)
+	if pad <= 0 || w.format.Align == "" || w.format.Align == "left" {
+		return w.Par.Buffer()
+	}
+	if w.format.Align == "center" {
+		pad /= 2
+	}
+
+	text := w.Text
+	w.Text = strings.Repeat(" ", pad) + text
+	buf := w.Par.Buffer()
+	w.Text = text
+	return buf
+}
+
+// TextCol implements formattedCol
+func (w *TextCol) setFormat(f config.ColumnFormat) {
+	w.format = f
+	if f.Width > 0 {
+		w.fWidth = f.Width
+	} else if f.MinWidth > 0 || f.MaxWidth > 0 {
+		w.fWidth = 0 // bounded automatic width
+	}
+}
+
+func (w *TextCol) widthBounds() (int, int) { return w.format.MinWidth, w.format.MaxWidth }
+
+// format a byte count in configured units
+func (w *TextCol) bytes(n int64) string {
+	return cwidgets.ByteFormatUnits(n, w.format.Units == "si", w.format.Precision)
+}
+
 func (w *TextCol) setText(s string) {
 	if w.fWidth > 0 && len(s) > w.fWidth {
 		s = s[0:w.fWidth]
@@ -12,6 +12,23 @@ type WidgetUpdater interface {
 	SetMetrics(models.Metrics)
 }
 
+// MultiUpdater updates several widgets with the same data
+type MultiUpdater []WidgetUpdater
+
+// MultiUpdater implements WidgetUpdater
+func (mu MultiUpdater) SetMeta(m models.Meta) {
+	for _, u := range mu {
+		u.SetMeta(m)
+	}
+}
+
+// MultiUpdater implements WidgetUpdater
+func (mu MultiUpdater) SetMetrics(m models.Metrics) {
+	for _, u := range mu {
+		u.SetMetrics(m)
+	}
+}
+
 type NullWidgetUpdater struct{}
 
 // NullWidgetUpdater implements WidgetUpdater
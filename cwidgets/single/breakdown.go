@@ -0,0 +1,102 @@
+package single
+
+import (
+	ui "github.com/gizak/termui"
+)
+
+// breakdown displays a table of per-item counters, e.g. per network
+// interface, above a pair of rate graphs for each item
+type breakdown struct {
+	*ui.Block
+	Table  *ui.Table
+	Graphs *ui.Sparklines
+	header []string
+	colors [2]ui.Attribute
+	hists  map[string][]*DiffHist
+}
+
+// breakdownItem is a table row and graph titles of a single item
+type breakdownItem struct {
+	Name   string
+	Row    []string
+	Titles [2]string
+}
+
+func newBreakdown(label string, header []string) *breakdown {
+	w := &breakdown{
+		Block:  ui.NewBlock(),
+		Table:  ui.NewTable(),
+		Graphs: ui.NewSparklines(),
+		header: header,
+		colors: [2]ui.Attribute{ui.ColorGreen, ui.ColorYellow},
+		hists:  make(map[string][]*DiffHist),
+	}
+	w.BorderLabel = label
+	w.Width = colWidth[0]
+
+	w.Table.Border = false
+	w.Table.Separator = false
+	w.Table.FgColor = ui.ThemeAttr("par.text.fg")
+	w.Graphs.Border = false
+	w.setItems(nil)
+	return w
+}
+
+// rates appends cumulative counters of an item to its history,
+// returning the latest per-interval differences
+func (w *breakdown) rates(name string, vals ...int64) []int {
+	h, ok := w.hists[name]
+	if !ok {
+		for range vals {
+			h = append(h, NewDiffHist(60))
+		}
+		w.hists[name] = h
+	}
+	r := make([]int, len(vals))
+	for i, v := range vals {
+		h[i].Append(int(v))
+		r[i] = h[i].Val
+	}
+	return r
+}
+
+// setItems rebuilds the table and graphs; the first two counters of
+// each item are graphed
+func (w *breakdown) setItems(items []breakdownItem) {
+	w.Table.Rows = [][]string{w.header}
+	w.Table.FgColors = nil
+	w.Graphs.Lines = w.Graphs.Lines[:0]
+	for _, item := range items {
+		w.Table.Rows = append(w.Table.Rows, item.Row)
+		for i, title := range item.Titles {
+			sl := ui.NewSparkline()
+			sl.Title = title
+			sl.LineColor = w.colors[i]
+			if h := w.hists[item.Name]; len(h) > i {
+				sl.Data = h[i].Data
+			}
+			w.Graphs.Lines = append(w.Graphs.Lines, sl)
+		}
+	}
+
+	w.Table.Height = len(w.Table.Rows)
+	w.Graphs.Height = len(w.Graphs.Lines) * 2
+	w.Height = w.Table.Height + w.Graphs.Height + 2
+	w.Align()
+}
+
+func (w *breakdown) Align() {
+	w.Table.X, w.Graphs.X = w.X+1, w.X+1
+	w.Table.SetY(w.Y + 1)
+	w.Graphs.SetY(w.Y + 1 + w.Table.Height)
+	w.Table.SetWidth(w.Width - 2)
+	w.Graphs.SetWidth(w.Width - 2)
+}
+
+func (w *breakdown) Buffer() ui.Buffer {
+	buf := ui.NewBuffer()
+	buf.Merge(w.Block.Buffer())
+	buf.Merge(w.Table.Buffer())
+	buf.Merge(w.Graphs.Buffer())
+	return buf
+}
@@ -0,0 +1,207 @@
+package single
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Betzalel75/ctop/cwidgets"
+	"github.com/Betzalel75/ctop/models"
+	ui "github.com/gizak/termui"
+)
+
+// MaxCompare is the number of containers compared at most
+const MaxCompare = 4
+
+// graph points kept per container
+const compareHistLen = 300
+
+// series colors of compared containers, in marking order
+var compareColors = []string{"green", "yellow", "cyan", "magenta"}
+
+var compareAttrs = map[string]ui.Attribute{
+	"green":   ui.ColorGreen,
+	"yellow":  ui.ColorYellow,
+	"cyan":    ui.ColorCyan,
+	"magenta": ui.ColorMagenta,
+}
+
+// Compare graphs metrics of several containers on shared time axes and
+// scales, with their meta side by side
+type Compare struct {
+	Meta   *ui.Table
+	Cpu    *ui.LineChart
+	Mem    *ui.LineChart
+	Net    *ui.LineChart
+	IO     *ui.LineChart
+	X, Y   int
+	Width  int
+	Height int
+	names  []string
+	keys   []string // series names, unique per container
+	meta   []models.Meta
+	last   []models.Metrics
+	sample int // number of samples added
+}
+
+// NewCompare returns a comparison of containers with the given names
+func NewCompare(names []string) *Compare {
+	c := &Compare{
+		Meta:   ui.NewTable(),
+		Cpu:    newCompareChart("CPU %"),
+		Mem:    newCompareChart("MEM MiB"),
+		Net:    newCompareChart("NET RX+TX KiB/s"),
+		IO:     newCompareChart("IO R+W KiB/s"),
+		Width:  ui.TermWidth(),
+		Height: ui.TermHeight(),
+		names:  names,
+		meta:   make([]models.Meta, len(names)),
+		last:   make([]models.Metrics, len(names)),
+	}
+	c.Meta.BorderLabel = "Compare"
+	c.Meta.FgColor = ui.ThemeAttr("par.text.fg")
+	c.Meta.Separator = false
+
+	for n, name := range names {
+		key := fmt.Sprintf("%d %s", n, name)
+		c.keys = append(c.keys, key)
+		for _, lc := range c.charts() {
+			lc.Data[key] = []float64{}
+			lc.LineColor[key] = compareAttrs[compareColors[n%len(compareColors)]]
+		}
+	}
+	c.setMetaRows()
+	return c
+}
+
+func newCompareChart(label string) *ui.LineChart {
+	lc := ui.NewLineChart()
+	lc.BorderLabel = label
+	lc.YFloor = 0
+	return lc
+}
+
+func (c *Compare) charts() []*ui.LineChart {
+	return []*ui.LineChart{c.Cpu, c.Mem, c.Net, c.IO}
+}
+
+// Add appends a sample of each container, in the order of their names;
+// samples are taken together, sharing the time axis
+func (c *Compare) Add(samples []models.Metrics) {
+	for n, m := range samples {
+		key := c.keys[n]
+		c.appendPoint(c.Cpu, key, float64(m.CPUUtil))
+		c.appendPoint(c.Mem, key, float64(m.MemUsage)/(1<<20))
+
+		// rates need a previous sample, 0 for the first one keeping
+		// series of the same length
+		var netRate, ioRate float64
+		if c.sample > 0 {
+			prev := c.last[n]
+			netRate = rate(m.NetRx+m.NetTx, prev.NetRx+prev.NetTx)
+			ioRate = rate(m.IOBytesRead+m.IOBytesWrite, prev.IOBytesRead+prev.IOBytesWrite)
+		}
+		c.appendPoint(c.Net, key, netRate)
+		c.appendPoint(c.IO, key, ioRate)
+		c.last[n] = m
+	}
+	c.sample++
+	c.setMetaRows()
+}
+
+func (c *Compare) appendPoint(lc *ui.LineChart, key string, v float64) {
+	data := append(lc.Data[key], v)
+	if len(data) > compareHistLen {
+		data = data[len(data)-compareHistLen:]
+	}
+	lc.Data[key] = data
+	// no time labels, as in the single view charts
+	lc.DataLabels = make([]string, len(data))
+}
+
+// per-second rate in KiB of a cumulative byte counter, 0 for counters
+// unavailable or reset
+func rate(cur, prev int64) float64 {
+	if cur < 0 || prev < 0 || cur < prev {
+		return 0
+	}
+	return float64(cur-prev) / 1024
+}
+
+// SetMeta sets the meta of each container, in the order of their names
+func (c *Compare) SetMeta(metas []models.Meta) {
+	copy(c.meta, metas)
+	c.setMetaRows()
+}
+
+// key meta and latest metrics, one column per container
+func (c *Compare) setMetaRows() {
+	header := []string{""}
+	for n, name := range c.names {
+		header = append(header, fmt.Sprintf("[%s](fg-%s)", name, compareColors[n%len(compareColors)]))
+	}
+	rows := [][]string{header}
+
+	addRow := func(label string, f func(n int) string) {
+		row := []string{label}
+		for n := range c.names {
+			row = append(row, f(n))
+		}
+		rows = append(rows, row)
+	}
+	metaRow := func(label, key string) {
+		addRow(label, func(n int) string {
+			if v := c.meta[n].Get(key); v != "" {
+				return v
+			}
+			return "-"
+		})
+	}
+	bytes := func(v int64) string { return strings.ToLower(cwidgets.ByteFormat64(v)) }
+
+	metaRow("id", "id")
+	metaRow("image", "image")
+	metaRow("state", "state")
+	metaRow("uptime", "uptime")
+	metaRow("health", "health")
+	addRow("cpu", func(n int) string { return fmt.Sprintf("%d%%", c.last[n].CPUUtil) })
+	addRow("mem", func(n int) string {
+		m := c.last[n]
+		return fmt.Sprintf("%s / %s (%d%%)", bytes(m.MemUsage), bytes(m.MemLimit), m.MemPercent)
+	})
+	addRow("net rx/tx", func(n int) string { return bytes(c.last[n].NetRx) + " / " + bytes(c.last[n].NetTx) })
+	addRow("io r/w", func(n int) string { return bytes(c.last[n].IOBytesRead) + " / " + bytes(c.last[n].IOBytesWrite) })
+	addRow("pids", func(n int) string { return fmt.Sprintf("%d", c.last[n].Pids) })
+
+	c.Meta.Rows = rows
+	c.Meta.FgColors, c.Meta.BgColors = nil, nil
+	c.Meta.Height = len(rows) + 2
+}
+
+func (c *Compare) SetWidth(w int) { c.Width = w }
+
+func (c *Compare) Align() {
+	c.Meta.X, c.Meta.Y = c.X, c.Y
+	c.Meta.Width = c.Width
+
+	y := c.Y + c.Meta.Height
+	h := max((c.Height-c.Meta.Height)/2, 3)
+	w := c.Width / 2
+	for n, lc := range c.charts() {
+		lc.X = c.X + (n%2)*w
+		lc.Y = y + (n/2)*h
+		lc.Width = w
+		if n%2 == 1 {
+			lc.Width = c.Width - w
+		}
+		lc.Height = h
+	}
+}
+
+func (c *Compare) Buffer() ui.Buffer {
+	buf := ui.NewBuffer()
+	buf.Merge(c.Meta.Buffer())
+	for _, lc := range c.charts() {
+		buf.Merge(lc.Buffer())
+	}
+	return buf
+}
@@ -1,32 +1,132 @@
 package single
 
 import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Betzalel75/ctop/models"
 	ui "github.com/gizak/termui"
 )
 
 type Cpu struct {
-	*ui.LineChart
-	hist FloatHist
+	*ui.Block
+	Chart     *ui.LineChart
+	Details   *ui.Par
+	Cores     *ui.BarChart
+	Throttle  *ui.Sparklines
+	hist      FloatHist
+	throttled *IntHist
 }
 
 func NewCpu() *Cpu {
-	cpu := &Cpu{ui.NewLineChart(), NewFloatHist(55)}
-	cpu.Mode = "dot"
+	cpu := &Cpu{
+		Block:     ui.NewBlock(),
+		Chart:     ui.NewLineChart(),
+		Details:   newMemLabel(),
+		Cores:     ui.NewBarChart(),
+		Throttle:  ui.NewSparklines(),
+		hist:      NewFloatHist(55),
+		throttled: NewIntHist(60),
+	}
 	cpu.BorderLabel = "CPU"
-	cpu.Height = 12
 	cpu.Width = colWidth[0]
 	cpu.X = 0
-	cpu.DataLabels = cpu.hist.Labels
+
+	cpu.Chart.Mode = "dot"
+	cpu.Chart.Border = false
+	cpu.Chart.Height = 10
+	cpu.Chart.DataLabels = cpu.hist.Labels
 
 	// hack to force the default minY scale to 0
 	tmpData := []float64{20}
-	cpu.Data["CPU"] = tmpData
-	_ = cpu.Buffer()
+	cpu.Chart.Data["CPU"] = tmpData
+	_ = cpu.Chart.Buffer()
+
+	cpu.Chart.Data["CPU"] = cpu.hist.Data
+
+	cpu.Cores.Border = false
+	cpu.Cores.Height = 0
+	cpu.Cores.SetMax(100)
+	cpu.Cores.BarColor = ui.ColorGreen
+	cpu.Cores.NumColor = ui.ColorBlack
+
+	sl := ui.NewSparkline()
+	sl.Title = "throttled"
+	sl.LineColor = ui.ColorRed
+	sl.Data = cpu.throttled.Data
+	cpu.Throttle.Add(sl)
+	cpu.Throttle.Border = false
+	cpu.Throttle.Height = 2
 
-	cpu.Data["CPU"] = cpu.hist.Data
+	cpu.setHeight()
 	return cpu
 }
 
-func (w *Cpu) Update(val int) {
-	w.hist.Append(float64(val))
+func (w *Cpu) setHeight() {
+	w.Height = w.Chart.Height + w.Details.Height + w.Cores.Height + w.Throttle.Height + 2
+}
+
+func (w *Cpu) Align() {
+	x, y := w.X+1, w.Y+1
+	w.Chart.X, w.Details.X, w.Cores.X, w.Throttle.X = x, x, x, x
+	w.Chart.SetY(y)
+	w.Details.SetY(y + w.Chart.Height)
+	w.Cores.SetY(y + w.Chart.Height + w.Details.Height)
+	w.Throttle.SetY(y + w.Chart.Height + w.Details.Height + w.Cores.Height)
+
+	w.Chart.SetWidth(w.Width - 2)
+	w.Details.SetWidth(w.Width - 2)
+	w.Cores.SetWidth(w.Width - 2)
+	w.Throttle.SetWidth(w.Width - 2)
+}
+
+func (w *Cpu) Buffer() ui.Buffer {
+	buf := ui.NewBuffer()
+	buf.Merge(w.Block.Buffer())
+	buf.Merge(w.Chart.Buffer())
+	buf.Merge(w.Details.Buffer())
+	if w.Cores.Height > 0 {
+		buf.Merge(w.Cores.Buffer())
+	}
+	buf.Merge(w.Throttle.Buffer())
+	return buf
+}
+
+func (w *Cpu) Update(m models.Metrics) {
+	w.hist.Append(float64(m.CPUUtil))
+	w.throttled.Append(m.CPUThrottled)
+	w.Throttle.Lines[0].Data = w.throttled.Data
+	w.Throttle.Lines[0].Title = fmt.Sprintf("throttled %d%% of periods", m.CPUThrottled)
+
+	w.Details.Text = fmt.Sprintf("[user](fg-green) %d%% [system](fg-yellow) %d%%  periods %d  throttled %d (%s)",
+		m.CPUUser, m.CPUSystem, m.CPUPeriods, m.CPUThrottledPeriods,
+		time.Duration(m.CPUThrottledTime).Round(time.Millisecond))
+
+	w.updateCores(m.PerCPU)
+}
+
+// updateCores graphs per-core utilization, hiding the chart for
+// collectors without per-core counters
+func (w *Cpu) updateCores(cores []int) {
+	if len(cores) == 0 {
+		w.Cores.Height = 0
+		w.setHeight()
+		return
+	}
+
+	w.Cores.Height = 6
+	w.Cores.Data = cores
+	if len(w.Cores.DataLabels) != len(cores) {
+		w.Cores.DataLabels = make([]string, len(cores))
+		for i := range cores {
+			w.Cores.DataLabels[i] = strconv.Itoa(i)
+		}
+	}
+	w.Cores.BarGap = 1
+	w.Cores.BarWidth = max(min((w.Width-2)/len(cores)-1, 4), 1)
+	if w.Cores.BarWidth == 1 {
+		w.Cores.BarGap = 0
+	}
+	w.setHeight()
 }
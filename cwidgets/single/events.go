@@ -0,0 +1,38 @@
+package single
+
+import (
+	"github.com/Betzalel75/ctop/models"
+	ui "github.com/gizak/termui"
+)
+
+// number of recent events shown
+const maxEvents = 8
+
+// Events lists the most recent runtime events of a container
+type Events struct {
+	*ui.Table
+}
+
+func NewEvents() *Events {
+	p := ui.NewTable()
+	p.Width = colWidth[0]
+	p.FgColor = ui.ThemeAttr("par.text.fg")
+	p.Separator = false
+	p.BorderLabel = "Events"
+	w := &Events{p}
+	w.Set(nil)
+	return w
+}
+
+// Set replaces the listed events, given oldest first
+func (w *Events) Set(events []models.Event) {
+	w.Rows = [][]string{}
+	for i := len(events) - 1; i >= 0 && len(w.Rows) < maxEvents; i-- {
+		e := events[i]
+		w.Rows = append(w.Rows, []string{e.Time.Local().Format("15:04:05"), e.Action, e.AttributeString()})
+	}
+	if len(w.Rows) == 0 {
+		w.Rows = append(w.Rows, []string{"-", "no events seen"})
+	}
+	w.Height = len(w.Rows) + 2
+}
@@ -0,0 +1,79 @@
+package single
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Betzalel75/ctop/models"
+	ui "github.com/gizak/termui"
+)
+
+// Health lists the recent health probes of a container, most recent
+// first; hidden for containers without a health check
+type Health struct {
+	*ui.Table
+	status string
+	streak string
+	checks []models.HealthCheck
+}
+
+func NewHealth() *Health {
+	p := ui.NewTable()
+	p.Width = colWidth[0]
+	p.FgColor = ui.ThemeAttr("par.text.fg")
+	p.Separator = false
+	p.Height = 0
+	return &Health{Table: p}
+}
+
+func (w *Health) SetMeta(m models.Meta) {
+	w.status = m.Get("health")
+	w.streak = m.Get("failing streak")
+	w.update()
+}
+
+// SetChecks sets the recent probe results, oldest first
+func (w *Health) SetChecks(checks []models.HealthCheck) {
+	w.checks = checks
+	w.update()
+}
+
+func (w *Health) update() {
+	if w.status == "" {
+		w.Height = 0
+		return
+	}
+
+	w.BorderLabel = fmt.Sprintf("Health: %s", w.status)
+	if n := w.streak; n != "" && n != "0" {
+		w.BorderLabel += fmt.Sprintf(" (failing streak %s)", n)
+	}
+	w.BorderFg = ui.ThemeAttr("border.fg")
+	if w.status == "unhealthy" {
+		w.BorderFg = ui.ThemeAttr("status.danger")
+	}
+
+	w.Rows = [][]string{{"START", "TOOK", "EXIT", "OUTPUT"}}
+	for i := len(w.checks) - 1; i >= 0; i-- {
+		c := w.checks[i]
+		output := strings.TrimSpace(c.Output)
+		if n := strings.IndexByte(output, '\n'); n >= 0 {
+			output = output[:n] + " …"
+		}
+		w.Rows = append(w.Rows, []string{
+			c.Start.Local().Format("15:04:05"),
+			c.End.Sub(c.Start).Round(time.Millisecond).String(),
+			fmt.Sprintf("%d", c.ExitCode),
+			output,
+		})
+	}
+	w.Height = len(w.Rows) + 2
+}
+
+func (w *Health) Buffer() ui.Buffer {
+	if w.Height == 0 {
+		return ui.NewBuffer()
+	}
+	return w.Table.Buffer()
+}
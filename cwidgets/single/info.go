@@ -6,7 +6,7 @@ import (
 	ui "github.com/gizak/termui"
 )
 
-var displayInfo = []string{"id", "name", "image", "ports", "IPs", "state", "created", "uptime", "health"}
+var displayInfo = []string{"id", "name", "namespace", "pod", "node", "image", "ports", "IPs", "state", "created", "uptime", "health", "restarts", "exit code", "finished", "error", "cgroup", "unit", "namespaces", "capabilities", "mounts"}
 
 type Info struct {
 	*ui.Table
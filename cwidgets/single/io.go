@@ -5,47 +5,31 @@ import (
 	"strings"
 
 	"github.com/Betzalel75/ctop/cwidgets"
-	ui "github.com/gizak/termui"
+	"github.com/Betzalel75/ctop/models"
 )
 
 type IO struct {
-	*ui.Sparklines
-	readHist  *DiffHist
-	writeHist *DiffHist
+	*breakdown
 }
 
 func NewIO() *IO {
-	io := &IO{ui.NewSparklines(), NewDiffHist(60), NewDiffHist(60)}
-	io.BorderLabel = "IO"
-	io.Height = 6
-	io.Width = colWidth[0]
-	io.X = 0
-	io.Y = 24
-
-	read := ui.NewSparkline()
-	read.Title = "READ"
-	read.Height = 1
-	read.Data = io.readHist.Data
-	read.LineColor = ui.ColorGreen
-
-	write := ui.NewSparkline()
-	write.Title = "WRITE"
-	write.Height = 1
-	write.Data = io.writeHist.Data
-	write.LineColor = ui.ColorYellow
-
-	io.Lines = []ui.Sparkline{read, write}
-	return io
+	return &IO{newBreakdown("IO", []string{"DEVICE", "READ/s", "WRITE/s", "OPS r/w"})}
 }
 
-func (w *IO) Update(read int64, write int64) {
-	var rate string
-
-	w.readHist.Append(int(read))
-	rate = strings.ToLower(cwidgets.ByteFormatShort(w.readHist.Val))
-	w.Lines[0].Title = fmt.Sprintf("read [%s/s]", rate)
-
-	w.writeHist.Append(int(write))
-	rate = strings.ToLower(cwidgets.ByteFormatShort(w.writeHist.Val))
-	w.Lines[1].Title = fmt.Sprintf("write [%s/s]", rate)
+func (w *IO) Update(devs []models.BlockDevice) {
+	var items []breakdownItem
+	for _, d := range devs {
+		r := w.rates(d.Name, d.ReadBytes, d.WriteBytes, d.ReadOps, d.WriteOps)
+		read := strings.ToLower(cwidgets.ByteFormatShort(r[0]))
+		write := strings.ToLower(cwidgets.ByteFormatShort(r[1]))
+		items = append(items, breakdownItem{
+			Name: d.Name,
+			Row:  []string{d.Name, read, write, fmt.Sprintf("%d/%d", r[2], r[3])},
+			Titles: [2]string{
+				fmt.Sprintf("%s read [%s/s]", d.Name, read),
+				fmt.Sprintf("%s write [%s/s]", d.Name, write),
+			},
+		})
+	}
+	w.setItems(items)
 }
@@ -13,25 +13,29 @@ var (
 )
 
 type Single struct {
-	Info  *Info
-	Net   *Net
-	Cpu   *Cpu
-	Mem   *Mem
-	IO    *IO
-	Env   *Env
-	X, Y  int
-	Width int
+	Info   *Info
+	Health *Health
+	Net    *Net
+	Cpu    *Cpu
+	Mem    *Mem
+	IO     *IO
+	Env    *Env
+	Events *Events
+	X, Y   int
+	Width  int
 }
 
 func NewSingle() *Single {
 	return &Single{
-		Info:  NewInfo(),
-		Net:   NewNet(),
-		Cpu:   NewCpu(),
-		Mem:   NewMem(),
-		IO:    NewIO(),
-		Env:   NewEnv(),
-		Width: ui.TermWidth(),
+		Info:   NewInfo(),
+		Health: NewHealth(),
+		Net:    NewNet(),
+		Cpu:    NewCpu(),
+		Mem:    NewMem(),
+		IO:     NewIO(),
+		Env:    NewEnv(),
+		Events: NewEvents(),
+		Width:  ui.TermWidth(),
 	}
 }
 
@@ -53,6 +57,8 @@ func (e *Single) Down() {
 
 func (e *Single) SetWidth(w int) { e.Width = w }
 func (e *Single) SetMeta(m models.Meta) {
+	h := e.GetHeight()
+	e.Health.SetMeta(m)
 	for k, v := range m {
 		if k == "[ENV-VAR]" {
 			e.Env.Set(v)
@@ -60,23 +66,66 @@ func (e *Single) SetMeta(m models.Meta) {
 			e.Info.Set(k, v)
 		}
 	}
+
+	// health checks may have been added
+	if e.GetHeight() != h {
+		e.Align()
+	}
+}
+
+// SetHealthLog sets the recent health probe results, oldest first
+func (e *Single) SetHealthLog(checks []models.HealthCheck) {
+	h := e.GetHeight()
+	e.Health.SetChecks(checks)
+	if e.GetHeight() != h {
+		e.Align()
+	}
 }
 
 func (e *Single) SetMetrics(m models.Metrics) {
-	e.Cpu.Update(m.CPUUtil)
-	e.Net.Update(m.NetRx, m.NetTx)
-	e.Mem.Update(int(m.MemUsage), int(m.MemLimit))
-	e.IO.Update(m.IOBytesRead, m.IOBytesWrite)
+	h := e.GetHeight()
+
+	// fall back to totals for collectors without per-interface
+	// or per-device counters
+	ifaces, devs := m.NetInterfaces, m.BlockDevices
+	if len(ifaces) == 0 && m.NetRx >= 0 {
+		ifaces = []models.NetInterface{{Name: "all", Rx: m.NetRx, Tx: m.NetTx}}
+	}
+	if len(devs) == 0 && m.IOBytesRead >= 0 {
+		devs = []models.BlockDevice{{Name: "all", ReadBytes: m.IOBytesRead, WriteBytes: m.IOBytesWrite}}
+	}
+
+	e.Cpu.Update(m)
+	e.Net.Update(ifaces)
+	e.Mem.Update(m)
+	e.IO.Update(devs)
+
+	// interfaces or devices may have been added
+	if e.GetHeight() != h {
+		e.Align()
+	}
+}
+
+// SetEvents lists the recent runtime events of the container, given
+// oldest first
+func (e *Single) SetEvents(events []models.Event) {
+	h := e.GetHeight()
+	e.Events.Set(events)
+	if e.GetHeight() != h {
+		e.Align()
+	}
 }
 
 // GetHeight returns total column height
 func (e *Single) GetHeight() (h int) {
 	h += e.Info.Height
+	h += e.Health.Height
 	h += e.Net.Height
 	h += e.Cpu.Height
 	h += e.Mem.Height
 	h += e.IO.Height
 	h += e.Env.Height
+	h += e.Events.Height
 	return h
 }
 
@@ -95,7 +144,10 @@ func (e *Single) Align() {
 	if e.Width > colWidth[0] {
 		colWidth[1] = e.Width - (colWidth[0] + 1)
 	}
+	e.Cpu.Align()
 	e.Mem.Align()
+	e.Net.Align()
+	e.IO.Align()
 	log.Debugf("align: width=%v left-col=%v right-col=%v", e.Width, colWidth[0], colWidth[1])
 }
 
@@ -107,10 +159,12 @@ func (e *Single) Buffer() ui.Buffer {
 		return buf
 	}
 	buf.Merge(e.Info.Buffer())
+	buf.Merge(e.Health.Buffer())
 	buf.Merge(e.Cpu.Buffer())
 	buf.Merge(e.Mem.Buffer())
 	buf.Merge(e.Net.Buffer())
 	buf.Merge(e.IO.Buffer())
+	buf.Merge(e.Events.Buffer())
 	buf.Merge(e.Env.Buffer())
 	return buf
 }
@@ -118,10 +172,12 @@ func (e *Single) Buffer() ui.Buffer {
 func (e *Single) all() []ui.GridBufferer {
 	return []ui.GridBufferer{
 		e.Info,
+		e.Health,
 		e.Cpu,
 		e.Mem,
 		e.Net,
 		e.IO,
+		e.Events,
 		e.Env,
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/Betzalel75/ctop/cwidgets"
+	"github.com/Betzalel75/ctop/models"
 	ui "github.com/gizak/termui"
 )
 
@@ -11,7 +12,10 @@ type Mem struct {
 	*ui.Block
 	Chart      *ui.MBarChart
 	InnerLabel *ui.Par
-	valHist    *IntHist
+	Details    *ui.Par
+	rssHist    *IntHist
+	cacheHist  *IntHist
+	swapHist   *IntHist
 	limitHist  *IntHist
 }
 
@@ -20,16 +24,22 @@ func NewMem() *Mem {
 		Block:      ui.NewBlock(),
 		Chart:      newMemChart(),
 		InnerLabel: newMemLabel(),
-		valHist:    NewIntHist(9),
+		Details:    newMemLabel(),
+		rssHist:    NewIntHist(9),
+		cacheHist:  NewIntHist(9),
+		swapHist:   NewIntHist(9),
 		limitHist:  NewIntHist(9),
 	}
-	mem.Height = 13
+	mem.Height = 14
 	mem.Width = colWidth[0]
 	mem.BorderLabel = "MEM"
 
-	mem.Chart.Data[0] = mem.valHist.Data
-	mem.Chart.Data[1] = mem.limitHist.Data
-	mem.Chart.DataLabels = mem.valHist.Labels
+	// stacked usage breakdown, with remaining limit on top
+	mem.Chart.Data[0] = mem.rssHist.Data
+	mem.Chart.Data[1] = mem.cacheHist.Data
+	mem.Chart.Data[2] = mem.swapHist.Data
+	mem.Chart.Data[3] = mem.limitHist.Data
+	mem.Chart.DataLabels = mem.rssHist.Labels
 
 	return mem
 }
@@ -37,9 +47,11 @@ func NewMem() *Mem {
 func (w *Mem) Align() {
 	y := w.Y + 1
 	w.InnerLabel.SetY(y)
-	w.Chart.SetY(y + w.InnerLabel.Height)
+	w.Details.SetY(y + w.InnerLabel.Height)
+	w.Chart.SetY(y + w.InnerLabel.Height + w.Details.Height)
 
-	w.Chart.Height = w.Height - w.InnerLabel.Height - 2
+	w.Details.SetWidth(w.Width - 2)
+	w.Chart.Height = w.Height - w.InnerLabel.Height - w.Details.Height - 2
 	w.Chart.SetWidth(w.Width - 2)
 }
 
@@ -47,6 +59,7 @@ func (w *Mem) Buffer() ui.Buffer {
 	buf := ui.NewBuffer()
 	buf.Merge(w.Block.Buffer())
 	buf.Merge(w.InnerLabel.Buffer())
+	buf.Merge(w.Details.Buffer())
 	buf.Merge(w.Chart.Buffer())
 	return buf
 }
@@ -67,17 +80,35 @@ func newMemChart() *ui.MBarChart {
 	mbar.BarGap = 1
 	mbar.BarWidth = 6
 
-	mbar.BarColor[1] = ui.ColorBlack
+	mbar.BarColor[1] = ui.ColorCyan
 	mbar.NumColor[1] = ui.ColorBlack
+	mbar.BarColor[2] = ui.ColorMagenta
+	mbar.NumColor[2] = ui.ColorBlack
+	mbar.BarColor[3] = ui.ColorBlack
+	mbar.NumColor[3] = ui.ColorBlack
 
 	mbar.NumFmt = cwidgets.ByteFormatShort
 	//mbar.ShowScale = true
 	return mbar
 }
 
-func (w *Mem) Update(val int, limit int) {
-	w.valHist.Append(val)
-	w.limitHist.Append(limit - val)
+func (w *Mem) Update(m models.Metrics) {
+	val, limit := int(m.MemUsage), int(m.MemLimit)
+	rss, cache, swap := int(m.MemRSS), int(m.MemCache), int(m.MemSwap)
+	if rss == 0 && cache == 0 {
+		rss = val // no breakdown available
+	}
+
+	w.rssHist.Append(rss)
+	w.cacheHist.Append(cache)
+	w.swapHist.Append(swap)
+	w.limitHist.Append(max(limit-rss-cache-swap, 0))
 	w.InnerLabel.Text = fmt.Sprintf("%v / %v", cwidgets.ByteFormatShort(val), cwidgets.ByteFormatShort(limit))
-	//w.Data[0] = w.hist.data
+	w.Details.Text = fmt.Sprintf("[rss](fg-green) %s [cache](fg-cyan) %s [swap](fg-magenta) %s  file act/inact %s/%s  majflt %d",
+		cwidgets.ByteFormatShort(rss), cwidgets.ByteFormatShort(cache), cwidgets.ByteFormatShort(swap),
+		cwidgets.ByteFormat64Short(m.MemActiveFile), cwidgets.ByteFormat64Short(m.MemInactiveFile),
+		m.MemMajorFaults)
+	if m.OOMKills > 0 {
+		w.Details.Text += fmt.Sprintf("  [oom kills %d](fg-red)", m.OOMKills)
+	}
 }
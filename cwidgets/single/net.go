@@ -5,47 +5,35 @@ import (
 	"strings"
 
 	"github.com/Betzalel75/ctop/cwidgets"
-	ui "github.com/gizak/termui"
+	"github.com/Betzalel75/ctop/models"
 )
 
 type Net struct {
-	*ui.Sparklines
-	rxHist *DiffHist
-	txHist *DiffHist
+	*breakdown
 }
 
 func NewNet() *Net {
-	net := &Net{ui.NewSparklines(), NewDiffHist(60), NewDiffHist(60)}
-	net.BorderLabel = "NET"
-	net.Height = 6
-	net.Width = colWidth[0]
-	net.X = 0
-	net.Y = 24
-
-	rx := ui.NewSparkline()
-	rx.Title = "RX"
-	rx.Height = 1
-	rx.Data = net.rxHist.Data
-	rx.LineColor = ui.ColorGreen
-
-	tx := ui.NewSparkline()
-	tx.Title = "TX"
-	tx.Height = 1
-	tx.Data = net.txHist.Data
-	tx.LineColor = ui.ColorYellow
-
-	net.Lines = []ui.Sparkline{rx, tx}
-	return net
+	return &Net{newBreakdown("NET", []string{"IFACE", "RX/s", "TX/s", "ERR rx/tx", "DROP rx/tx"})}
 }
 
-func (w *Net) Update(rx int64, tx int64) {
-	var rate string
-
-	w.rxHist.Append(int(rx))
-	rate = strings.ToLower(cwidgets.ByteFormat(w.rxHist.Val))
-	w.Lines[0].Title = fmt.Sprintf("RX [%s/s]", rate)
-
-	w.txHist.Append(int(tx))
-	rate = strings.ToLower(cwidgets.ByteFormat(w.txHist.Val))
-	w.Lines[1].Title = fmt.Sprintf("TX [%s/s]", rate)
+func (w *Net) Update(ifaces []models.NetInterface) {
+	var items []breakdownItem
+	for _, i := range ifaces {
+		r := w.rates(i.Name, i.Rx, i.Tx)
+		rx := strings.ToLower(cwidgets.ByteFormat(r[0]))
+		tx := strings.ToLower(cwidgets.ByteFormat(r[1]))
+		items = append(items, breakdownItem{
+			Name: i.Name,
+			Row: []string{
+				i.Name, rx, tx,
+				fmt.Sprintf("%d/%d", i.RxErrors, i.TxErrors),
+				fmt.Sprintf("%d/%d", i.RxDropped, i.TxDropped),
+			},
+			Titles: [2]string{
+				fmt.Sprintf("%s RX [%s/s]", i.Name, rx),
+				fmt.Sprintf("%s TX [%s/s]", i.Name, tx),
+			},
+		})
+	}
+	w.setItems(items)
 }
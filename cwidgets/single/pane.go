@@ -0,0 +1,204 @@
+package single
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Betzalel75/ctop/cwidgets"
+	"github.com/Betzalel75/ctop/models"
+	ui "github.com/gizak/termui"
+)
+
+// pane display modes
+const (
+	PaneGraphs = "graphs"
+	PaneLogs   = "logs"
+	PaneEnv    = "env"
+)
+
+// PaneModes lists pane modes, in cycling order
+var PaneModes = []string{PaneGraphs, PaneLogs, PaneEnv}
+
+const (
+	paneHistLen = 300 // graph points, wider panes show the most recent
+	paneLogLen  = 500 // log lines kept
+)
+
+// Pane shows the graphs, logs or environment of a container in a
+// fixed area, below the compact grid in the split layout
+type Pane struct {
+	*ui.Block
+	Mode    string
+	name    string
+	cpu     *IntHist
+	mem     *IntHist
+	netRx   *DiffHist
+	netTx   *DiffHist
+	ioRead  *DiffHist
+	ioWrite *DiffHist
+	metrics models.Metrics
+	env     *Env
+	logs    []string
+	lock    sync.Mutex // guards logs, appended by the log stream
+}
+
+func NewPane() *Pane {
+	p := &Pane{Block: ui.NewBlock(), Mode: PaneGraphs}
+	p.Reset()
+	return p
+}
+
+// Reset clears data of the previous container
+func (p *Pane) Reset() {
+	p.name = ""
+	p.cpu = NewIntHist(paneHistLen)
+	p.mem = NewIntHist(paneHistLen)
+	p.netRx = NewDiffHist(paneHistLen)
+	p.netTx = NewDiffHist(paneHistLen)
+	p.ioRead = NewDiffHist(paneHistLen)
+	p.ioWrite = NewDiffHist(paneHistLen)
+	p.metrics = models.NewMetrics()
+	p.env = NewEnv()
+	p.ClearLogs()
+}
+
+// NextMode cycles through pane modes
+func (p *Pane) NextMode() {
+	for n, m := range PaneModes {
+		if m == p.Mode {
+			p.Mode = PaneModes[(n+1)%len(PaneModes)]
+			return
+		}
+	}
+	p.Mode = PaneGraphs
+}
+
+// AddLog appends a log line, shown in logs mode
+func (p *Pane) AddLog(s string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.logs) >= paneLogLen {
+		p.logs = p.logs[1:]
+	}
+	p.logs = append(p.logs, s)
+}
+
+// ClearLogs removes log lines, before streaming them again
+func (p *Pane) ClearLogs() {
+	p.lock.Lock()
+	p.logs = nil
+	p.lock.Unlock()
+}
+
+// Pane implements WidgetUpdater
+func (p *Pane) SetMeta(m models.Meta) {
+	p.name = m.Get("name")
+	if env, ok := m["[ENV-VAR]"]; ok {
+		p.env.Set(env)
+	}
+}
+
+// Pane implements WidgetUpdater
+func (p *Pane) SetMetrics(m models.Metrics) {
+	p.metrics = m
+	p.cpu.Append(m.CPUUtil)
+	p.mem.Append(m.MemPercent)
+	p.netRx.Append(int(m.NetRx))
+	p.netTx.Append(int(m.NetTx))
+	p.ioRead.Append(int(m.IOBytesRead))
+	p.ioWrite.Append(int(m.IOBytesWrite))
+}
+
+func (p *Pane) Buffer() ui.Buffer {
+	p.BorderLabel = fmt.Sprintf("%s [%s]", p.name, p.Mode)
+	buf := p.Block.Buffer()
+
+	switch p.Mode {
+	case PaneLogs:
+		buf.Merge(p.logsBuffer())
+	case PaneEnv:
+		buf.Merge(p.envBuffer())
+	default:
+		buf.Merge(p.graphsBuffer())
+	}
+	return buf
+}
+
+// CPU, memory, network and IO sparklines side by side
+func (p *Pane) graphsBuffer() ui.Buffer {
+	buf := ui.NewBuffer()
+	m := p.metrics
+	rate := func(h *DiffHist) string { return strings.ToLower(cwidgets.ByteFormat(h.Val)) + "/s" }
+
+	charts := [][]ui.Sparkline{
+		{p.sparkline(fmt.Sprintf("CPU %d%%", m.CPUUtil), p.cpu, 1)},
+		{p.sparkline(fmt.Sprintf("MEM %s / %s", cwidgets.ByteFormat64Short(m.MemUsage), cwidgets.ByteFormat64Short(m.MemLimit)), p.mem, 1)},
+		{p.sparkline("NET RX "+rate(p.netRx), p.netRx.IntHist, 2), p.sparkline("NET TX "+rate(p.netTx), p.netTx.IntHist, 2)},
+		{p.sparkline("IO R "+rate(p.ioRead), p.ioRead.IntHist, 2), p.sparkline("IO W "+rate(p.ioWrite), p.ioWrite.IntHist, 2)},
+	}
+
+	width := (p.Width - 2) / len(charts)
+	for n, lines := range charts {
+		s := ui.NewSparklines(lines...)
+		s.Border = false
+		s.X = p.X + 1 + n*width
+		s.Y = p.Y + 1
+		s.Width = width - 1
+		s.Height = p.Height - 2
+		buf.Merge(s.Buffer())
+	}
+	return buf
+}
+
+// sparkline of a history, sharing the pane height with n lines
+func (p *Pane) sparkline(title string, h *IntHist, n int) ui.Sparkline {
+	s := ui.NewSparkline()
+	s.Title = title
+	s.Data = h.Data
+	s.Height = max((p.Height-2)/n-1, 1)
+	return s
+}
+
+// most recent log lines
+func (p *Pane) logsBuffer() ui.Buffer {
+	l := ui.NewList()
+	l.Border = false
+	l.X, l.Y = p.X+1, p.Y+1
+	l.Width, l.Height = p.Width-2, p.Height-2
+	l.ItemFgColor = ui.ThemeAttr("par.text.fg")
+
+	p.lock.Lock()
+	lines := p.logs
+	if len(lines) > l.Height {
+		lines = lines[len(lines)-l.Height:]
+	}
+	lines = append([]string(nil), lines...)
+	p.lock.Unlock()
+
+	l.Items = lines
+	if len(lines) == 0 {
+		l.Items = []string{"no logs"}
+	}
+	return l.Buffer()
+}
+
+// environment variables, as in the single view
+func (p *Pane) envBuffer() ui.Buffer {
+	t := p.env
+	rows := t.Rows
+	if len(rows) == 0 {
+		t.Rows = [][]string{{"no environment variables"}}
+	}
+	if n := max(p.Height-2, 0); len(t.Rows) > n {
+		t.Rows = t.Rows[:n]
+	}
+	t.FgColors, t.BgColors = nil, nil // sized to the rows shown
+
+	t.Border = false
+	t.X, t.Y = p.X+1, p.Y+1
+	t.Width, t.Height = p.Width-2, len(t.Rows)
+	buf := t.Buffer()
+	t.Rows = rows
+	return buf
+}
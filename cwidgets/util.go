@@ -1,6 +1,7 @@
 package cwidgets
 
 import (
+	"math"
 	"strconv"
 )
 
@@ -35,12 +36,45 @@ var (
 	}
 )
 
+// short SI unit labels, for powers of 1000
+var siLabels = []string{"B", "k", "M", "G", "T", "P"}
+
 // convenience methods
 func ByteFormat(n int) string          { return byteFormat(float64(n), false) }
 func ByteFormatShort(n int) string     { return byteFormat(float64(n), true) }
 func ByteFormat64(n int64) string      { return byteFormat(float64(n), false) }
 func ByteFormat64Short(n int64) string { return byteFormat(float64(n), true) }
 
+// ByteFormatUnits returns a short label of n bytes in IEC units, or SI
+// units if si is set, with a fixed number of decimals
+func ByteFormatUnits(n int64, si bool, precision int) string {
+	if !si && precision == 0 {
+		return ByteFormat64Short(n)
+	}
+
+	f, i := float64(n), len(units)-1
+	for i > 0 {
+		unit := units[i]
+		if si {
+			unit = math.Pow(1000, float64(i))
+		}
+		if f >= unit {
+			f /= unit
+			break
+		}
+		i--
+	}
+
+	if i == 0 {
+		precision = 0 // whole bytes
+	}
+	label := labels[i][0]
+	if si {
+		label = siLabels[i]
+	}
+	return strconv.FormatFloat(f, 'f', precision, 64) + label
+}
+
 func byteFormat(n float64, short bool) string {
 	i := len(units) - 1
 
@@ -0,0 +1,62 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	api "github.com/fsouza/go-dockerclient"
+)
+
+// apiURL builds a request URL for a raw Docker Engine API path, for
+// endpoints not (fully) covered by go-dockerclient
+func apiURL(client *api.Client, path string) (string, error) {
+	u, err := url.Parse(client.Endpoint())
+	if err != nil {
+		return "", err
+	}
+
+	switch u.Scheme {
+	case "unix", "npipe":
+		// host is ignored, the transport dials the socket directly
+		return "http://unix.sock" + path, nil
+	case "tcp":
+		u.Scheme = "http"
+		if client.TLSConfig != nil {
+			u.Scheme = "https"
+		}
+	}
+	return strings.TrimRight(u.String(), "/") + path, nil
+}
+
+// doJSON performs a raw API request, decoding the response body into v if non-nil
+func doJSON(client *api.Client, method, path string, v any) error {
+	u, err := apiURL(client, path)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, u, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s %s: %s (%d)", method, path, strings.TrimSpace(string(body)), resp.StatusCode)
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,176 @@
+package manager
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Betzalel75/ctop/dtop/resource"
+	api "github.com/fsouza/go-dockerclient"
+)
+
+// number of items listed per disk usage category
+const diskTopN = 5
+
+const (
+	DiskImages     = "Images"
+	DiskContainers = "Containers"
+	DiskVolumes    = "Local Volumes"
+	DiskBuildCache = "Build Cache"
+)
+
+// response body of GET /system/df; go-dockerclient omits volume
+// usage data and build cache records
+type systemDF struct {
+	LayersSize int64
+	Images     []struct {
+		Id         string
+		RepoTags   []string
+		Size       int64
+		SharedSize int64
+		Containers int64
+	}
+	Containers []struct {
+		Id     string
+		Names  []string
+		SizeRw int64
+		State  string
+	}
+	Volumes []struct {
+		Name      string
+		UsageData *api.VolumeUsageData
+	}
+	BuildCache []struct {
+		ID          string
+		Type        string
+		Description string
+		Size        int64
+		InUse       bool
+		Shared      bool
+	}
+}
+
+func (drm *DockerResourceManager) LoadDiskUsage() (*resource.DiskUsage, error) {
+	var df systemDF
+	if err := doJSON(drm.client, http.MethodGet, "/system/df", &df); err != nil {
+		return nil, err
+	}
+
+	images := resource.DiskCategory{Name: DiskImages, Size: df.LayersSize}
+	var imgUsed int64
+	for _, i := range df.Images {
+		images.Total++
+		tag := "<none>:<none>"
+		if len(i.RepoTags) > 0 {
+			tag = i.RepoTags[0]
+		}
+		if i.Containers > 0 {
+			images.Active++
+			imgUsed += i.Size - max(i.SharedSize, 0)
+		}
+		images.Top = append(images.Top, resource.DiskItem{Id: i.Id, Title: tag, Size: i.Size})
+	}
+	images.Reclaimable = max(df.LayersSize-imgUsed, 0)
+
+	containers := resource.DiskCategory{Name: DiskContainers}
+	for _, c := range df.Containers {
+		containers.Total++
+		containers.Size += c.SizeRw
+		if c.State == "running" {
+			containers.Active++
+		} else {
+			containers.Reclaimable += c.SizeRw
+		}
+		name := c.Id
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		containers.Top = append(containers.Top, resource.DiskItem{Id: c.Id, Title: name, Size: c.SizeRw})
+	}
+
+	volumes := resource.DiskCategory{Name: DiskVolumes}
+	for _, v := range df.Volumes {
+		volumes.Total++
+		if v.UsageData == nil || v.UsageData.Size < 0 {
+			continue // usage not available for this volume driver
+		}
+		volumes.Size += v.UsageData.Size
+		if v.UsageData.RefCount > 0 {
+			volumes.Active++
+		} else {
+			volumes.Reclaimable += v.UsageData.Size
+		}
+		volumes.Top = append(volumes.Top, resource.DiskItem{Id: v.Name, Title: v.Name, Size: v.UsageData.Size})
+	}
+
+	cache := resource.DiskCategory{Name: DiskBuildCache}
+	for _, b := range df.BuildCache {
+		cache.Total++
+		if b.InUse {
+			cache.Active++
+		}
+		if b.Shared {
+			continue
+		}
+		cache.Size += b.Size
+		if !b.InUse {
+			cache.Reclaimable += b.Size
+		}
+		title := fmt.Sprintf("%s %s", b.Type, b.Description)
+		cache.Top = append(cache.Top, resource.DiskItem{Id: b.ID, Title: strings.TrimSpace(title), Size: b.Size})
+	}
+
+	du := &resource.DiskUsage{}
+	for _, cat := range []resource.DiskCategory{images, containers, volumes, cache} {
+		sort.Slice(cat.Top, func(i, j int) bool { return cat.Top[i].Size > cat.Top[j].Size })
+		if len(cat.Top) > diskTopN {
+			cat.Top = cat.Top[:diskTopN]
+		}
+		du.Categories = append(du.Categories, cat)
+	}
+	return du, nil
+}
+
+// Prune removes unused objects of the given disk usage category,
+// mirroring `docker <type> prune` (images: `-a`)
+func (drm *DockerResourceManager) Prune(category string) resource.ResourcesDeletedMsg {
+	var msg resource.ResourcesDeletedMsg
+
+	switch category {
+	case DiskImages:
+		res, err := drm.client.PruneImages(api.PruneImagesOptions{
+			Filters: map[string][]string{"dangling": {"false"}},
+		})
+		if err != nil {
+			msg.Errs = append(msg.Errs, fmt.Errorf("failed to prune images: %w", err))
+		} else {
+			msg.Reclaimed = res.SpaceReclaimed
+		}
+	case DiskContainers:
+		res, err := drm.client.PruneContainers(api.PruneContainersOptions{})
+		if err != nil {
+			msg.Errs = append(msg.Errs, fmt.Errorf("failed to prune containers: %w", err))
+		} else {
+			msg.Reclaimed = res.SpaceReclaimed
+		}
+	case DiskVolumes:
+		res, err := drm.client.PruneVolumes(api.PruneVolumesOptions{})
+		if err != nil {
+			msg.Errs = append(msg.Errs, fmt.Errorf("failed to prune volumes: %w", err))
+		} else {
+			msg.Reclaimed = res.SpaceReclaimed
+		}
+	case DiskBuildCache:
+		var res struct{ SpaceReclaimed int64 }
+		if err := doJSON(drm.client, http.MethodPost, "/build/prune", &res); err != nil {
+			msg.Errs = append(msg.Errs, fmt.Errorf("failed to prune build cache: %w", err))
+		} else {
+			msg.Reclaimed = res.SpaceReclaimed
+		}
+	default:
+		msg.Errs = append(msg.Errs, fmt.Errorf("unknown disk usage category: %s", category))
+	}
+
+	return msg
+}
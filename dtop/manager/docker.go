@@ -3,6 +3,8 @@ package manager
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"strings"
 
@@ -77,6 +79,24 @@ func (drm *DockerResourceManager) LoadVolumes() ([]list.Item, error) {
 	return items, nil
 }
 
+func (drm *DockerResourceManager) LoadNetworks() ([]list.Item, error) {
+	networks, err := drm.client.ListNetworks()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []list.Item
+	for _, n := range networks {
+		desc := fmt.Sprintf("ID: %s | Driver: %s | Scope: %s", n.ID[:12], n.Driver, n.Scope)
+		items = append(items, resource.ResourceItem{
+			Id:    n.ID,
+			Title: n.Name,
+			Desc:  desc,
+		})
+	}
+	return items, nil
+}
+
 func (drm *DockerResourceManager) DeleteContainers(items []resource.ResourceItem) []error {
 	var errs []error
 	for _, item := range items {
@@ -113,6 +133,26 @@ func (drm *DockerResourceManager) DeleteVolumes(items []resource.ResourceItem) [
 	return errs
 }
 
+// Inspect returns the raw inspect document of a resource, by type
+// ("containers", "images", "volumes", "networks" or "services") and ID
+func (drm *DockerResourceManager) Inspect(resourceType, id string) (any, error) {
+	var path string
+	switch resourceType {
+	case "containers", "images":
+		path = fmt.Sprintf("/%s/%s/json", resourceType, url.PathEscape(id))
+	case "volumes", "networks", "services":
+		path = fmt.Sprintf("/%s/%s", resourceType, url.PathEscape(id))
+	default:
+		return nil, fmt.Errorf("cannot inspect resource type: %s", resourceType)
+	}
+
+	var doc any
+	if err := doJSON(drm.client, http.MethodGet, path, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
 func (drm *DockerResourceManager) PublishImages(items []resource.ResourceItem, registry, username, tag string) []error {
 	var errs []error
 	for _, item := range items {
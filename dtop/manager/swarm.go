@@ -0,0 +1,162 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Betzalel75/ctop/dtop/resource"
+	"github.com/docker/docker/api/types/swarm"
+	api "github.com/fsouza/go-dockerclient"
+)
+
+// LoadServices lists swarm services with their desired and running
+// task counts
+func (drm *DockerResourceManager) LoadServices() ([]resource.Service, error) {
+	services, err := drm.client.ListServices(api.ListServicesOptions{Status: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var items []resource.Service
+	for _, s := range services {
+		item := resource.Service{
+			Id:    s.ID,
+			Name:  s.Spec.Name,
+			Mode:  serviceMode(s.Spec.Mode),
+			Image: imageName(s.Spec.TaskTemplate.ContainerSpec),
+		}
+		if s.ServiceStatus != nil {
+			item.Desired = s.ServiceStatus.DesiredTasks
+			item.Running = s.ServiceStatus.RunningTasks
+		} else if r := s.Spec.Mode.Replicated; r != nil && r.Replicas != nil {
+			// daemons older than API 1.41 do not report task counts
+			item.Desired = *r.Replicas
+		}
+		if s.UpdateStatus != nil {
+			item.UpdateState = string(s.UpdateStatus.State)
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items, nil
+}
+
+// LoadTasks lists the tasks of a service, most recent first, along with
+// the hostname of their node
+func (drm *DockerResourceManager) LoadTasks(serviceID string) ([]resource.Task, error) {
+	tasks, err := drm.client.ListTasks(api.ListTasksOptions{
+		Filters: map[string][]string{"service": {serviceID}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := drm.client.ListNodes(api.ListNodesOptions{})
+	if err != nil {
+		return nil, err
+	}
+	hostnames := make(map[string]string, len(nodes))
+	for _, n := range nodes {
+		hostnames[n.ID] = n.Description.Hostname
+	}
+
+	service, err := drm.client.InspectService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []resource.Task
+	for _, t := range tasks {
+		name := fmt.Sprintf("%s.%d", service.Spec.Name, t.Slot)
+		if t.Slot == 0 { // global service
+			name = fmt.Sprintf("%s.%s", service.Spec.Name, t.NodeID)
+		}
+		node := hostnames[t.NodeID]
+		if node == "" {
+			node = t.NodeID
+		}
+		items = append(items, resource.Task{
+			Id:           t.ID,
+			Name:         name,
+			Node:         node,
+			DesiredState: string(t.DesiredState),
+			State:        string(t.Status.State),
+			Since:        t.Status.Timestamp,
+			Error:        t.Status.Err,
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Since.After(items[j].Since) })
+	return items, nil
+}
+
+// ScaleService sets the number of replicas of a replicated service
+func (drm *DockerResourceManager) ScaleService(id string, replicas uint64) error {
+	return drm.updateService(id, "", func(spec *swarm.ServiceSpec) error {
+		if spec.Mode.Replicated == nil {
+			return fmt.Errorf("service %s is not replicated", spec.Name)
+		}
+		spec.Mode.Replicated.Replicas = &replicas
+		return nil
+	})
+}
+
+// ForceUpdateService redeploys the tasks of a service, even if its spec
+// did not change
+func (drm *DockerResourceManager) ForceUpdateService(id string) error {
+	return drm.updateService(id, "", func(spec *swarm.ServiceSpec) error {
+		spec.TaskTemplate.ForceUpdate++
+		return nil
+	})
+}
+
+// RollbackService reverts a service to its previous spec
+func (drm *DockerResourceManager) RollbackService(id string) error {
+	return drm.updateService(id, "previous", func(*swarm.ServiceSpec) error { return nil })
+}
+
+// updateService applies fn to the current spec of a service and submits
+// it at the inspected version
+func (drm *DockerResourceManager) updateService(id, rollback string, fn func(*swarm.ServiceSpec) error) error {
+	service, err := drm.client.InspectService(id)
+	if err != nil {
+		return err
+	}
+	if rollback != "" && service.PreviousSpec == nil {
+		return fmt.Errorf("service %s has no previous spec to roll back to", service.Spec.Name)
+	}
+
+	spec := service.Spec
+	if err := fn(&spec); err != nil {
+		return err
+	}
+	return drm.client.UpdateService(id, api.UpdateServiceOptions{
+		ServiceSpec: spec,
+		Version:     service.Version.Index,
+		Rollback:    rollback,
+	})
+}
+
+func serviceMode(m swarm.ServiceMode) string {
+	switch {
+	case m.Replicated != nil:
+		return "replicated"
+	case m.Global != nil:
+		return "global"
+	case m.ReplicatedJob != nil:
+		return "replicated-job"
+	case m.GlobalJob != nil:
+		return "global-job"
+	}
+	return ""
+}
+
+// image reference of a container spec, without its pinned digest
+func imageName(spec *swarm.ContainerSpec) string {
+	if spec == nil {
+		return ""
+	}
+	if i := strings.Index(spec.Image, "@sha256:"); i >= 0 {
+		return spec.Image[:i]
+	}
+	return spec.Image
+}
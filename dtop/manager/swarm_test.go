@@ -0,0 +1,173 @@
+package manager
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	api "github.com/fsouza/go-dockerclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSwarm serves the swarm endpoints of the Docker API from fixed
+// services, tasks and nodes, recording service updates
+type fakeSwarm struct {
+	services []swarm.Service
+	tasks    []swarm.Task
+	nodes    []swarm.Node
+	updates  []*http.Request
+	specs    []swarm.ServiceSpec
+}
+
+func (f *fakeSwarm) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	reply := func(v any) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/services":
+		reply(f.services)
+		return
+	case r.Method == http.MethodGet && r.URL.Path == "/tasks":
+		reply(f.tasks)
+		return
+	case r.Method == http.MethodGet && r.URL.Path == "/nodes":
+		reply(f.nodes)
+		return
+	}
+	for _, s := range f.services {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/services/"+s.ID:
+			reply(s)
+			return
+		case r.Method == http.MethodPost && r.URL.Path == "/services/"+s.ID+"/update":
+			var spec swarm.ServiceSpec
+			json.NewDecoder(r.Body).Decode(&spec)
+			f.updates = append(f.updates, r)
+			f.specs = append(f.specs, spec)
+			reply(map[string]any{})
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+func newFakeSwarm(t *testing.T) (*fakeSwarm, *DockerResourceManager) {
+	replicas := uint64(3)
+	f := &fakeSwarm{
+		services: []swarm.Service{
+			{
+				ID:   "svc-web",
+				Meta: swarm.Meta{Version: swarm.Version{Index: 42}},
+				Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{Name: "web"},
+					Mode:        swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+					TaskTemplate: swarm.TaskSpec{
+						ContainerSpec: &swarm.ContainerSpec{Image: "nginx:1.27@sha256:abcdef"},
+					},
+				},
+				ServiceStatus: &swarm.ServiceStatus{DesiredTasks: 3, RunningTasks: 2},
+			},
+			{
+				ID:   "svc-agent",
+				Meta: swarm.Meta{Version: swarm.Version{Index: 7}},
+				Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{Name: "agent"},
+					Mode:        swarm.ServiceMode{Global: &swarm.GlobalService{}},
+				},
+			},
+		},
+		tasks: []swarm.Task{
+			{
+				ID: "task-1", Slot: 1, NodeID: "node-a",
+				DesiredState: swarm.TaskStateRunning,
+				Status:       swarm.TaskStatus{State: swarm.TaskStateRunning, Timestamp: time.Now().Add(-time.Hour)},
+			},
+			{
+				ID: "task-2", Slot: 2, NodeID: "node-b",
+				DesiredState: swarm.TaskStateShutdown,
+				Status:       swarm.TaskStatus{State: swarm.TaskStateFailed, Timestamp: time.Now(), Err: "exit 1"},
+			},
+		},
+		nodes: []swarm.Node{
+			{ID: "node-a", Description: swarm.NodeDescription{Hostname: "manager-1"}},
+		},
+	}
+
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	client, err := api.NewClient(server.URL)
+	require.NoError(t, err)
+	return f, NewDockerResourceManager(client)
+}
+
+func TestLoadServices(t *testing.T) {
+	_, drm := newFakeSwarm(t)
+
+	services, err := drm.LoadServices()
+	require.NoError(t, err)
+	require.Len(t, services, 2)
+
+	assert.Equal(t, "agent", services[0].Name)
+	assert.Equal(t, "global", services[0].Mode)
+
+	web := services[1]
+	assert.Equal(t, "web", web.Name)
+	assert.Equal(t, "replicated", web.Mode)
+	assert.Equal(t, "nginx:1.27", web.Image)
+	assert.Equal(t, uint64(3), web.Desired)
+	assert.Equal(t, uint64(2), web.Running)
+}
+
+func TestLoadTasks(t *testing.T) {
+	_, drm := newFakeSwarm(t)
+
+	tasks, err := drm.LoadTasks("svc-web")
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	// most recent first, nodes by hostname when known
+	assert.Equal(t, "web.2", tasks[0].Name)
+	assert.Equal(t, "node-b", tasks[0].Node)
+	assert.Equal(t, "failed", tasks[0].State)
+	assert.Equal(t, "exit 1", tasks[0].Error)
+	assert.Equal(t, "web.1", tasks[1].Name)
+	assert.Equal(t, "manager-1", tasks[1].Node)
+}
+
+func TestScaleService(t *testing.T) {
+	f, drm := newFakeSwarm(t)
+
+	require.NoError(t, drm.ScaleService("svc-web", 5))
+	require.Len(t, f.updates, 1)
+	assert.Equal(t, "42", f.updates[0].URL.Query().Get("version"))
+	require.NotNil(t, f.specs[0].Mode.Replicated)
+	assert.Equal(t, uint64(5), *f.specs[0].Mode.Replicated.Replicas)
+
+	assert.Error(t, drm.ScaleService("svc-agent", 2), "global services cannot be scaled")
+}
+
+func TestForceUpdateService(t *testing.T) {
+	f, drm := newFakeSwarm(t)
+
+	require.NoError(t, drm.ForceUpdateService("svc-web"))
+	require.Len(t, f.specs, 1)
+	assert.Equal(t, uint64(1), f.specs[0].TaskTemplate.ForceUpdate)
+}
+
+func TestRollbackService(t *testing.T) {
+	f, drm := newFakeSwarm(t)
+
+	assert.Error(t, drm.RollbackService("svc-web"))
+	assert.Empty(t, f.updates)
+
+	f.services[0].PreviousSpec = &swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "web"}}
+	require.NoError(t, drm.RollbackService("svc-web"))
+	require.Len(t, f.updates, 1)
+	assert.Equal(t, "previous", f.updates[0].URL.Query().Get("rollback"))
+}
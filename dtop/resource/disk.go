@@ -0,0 +1,23 @@
+package resource
+
+// DiskItem is a single object accounted for in a DiskCategory
+type DiskItem struct {
+	Id    string
+	Title string
+	Size  int64
+}
+
+// DiskCategory summarizes disk usage for one object type,
+// e.g. images or build cache, as reported by `docker system df -v`
+type DiskCategory struct {
+	Name        string
+	Total       int
+	Active      int
+	Size        int64
+	Reclaimable int64
+	Top         []DiskItem // largest items, in descending size order
+}
+
+type DiskUsage struct {
+	Categories []DiskCategory
+}
@@ -0,0 +1,25 @@
+package resource
+
+import "time"
+
+// Service is a swarm service along with its replica counts
+type Service struct {
+	Id          string
+	Name        string
+	Mode        string // replicated, global or job
+	Image       string
+	Desired     uint64
+	Running     uint64
+	UpdateState string // state of the last update or rollback, if any
+}
+
+// Task is a swarm task of a service, scheduled on a node
+type Task struct {
+	Id           string
+	Name         string
+	Node         string
+	DesiredState string
+	State        string
+	Since        time.Time // time of the last state change
+	Error        string
+}
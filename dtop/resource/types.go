@@ -27,7 +27,10 @@ type ResourcesLoadedMsg struct {
 	Err   error
 }
 
-type ResourcesDeletedMsg struct{ Errs []error }
+type ResourcesDeletedMsg struct {
+	Errs      []error
+	Reclaimed int64 // bytes freed, when reported by the daemon
+}
 
 type ImagesPublishedMsg struct {
 	Errs []error
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Betzalel75/ctop/connector"
+	"github.com/Betzalel75/ctop/widgets"
+	ui "github.com/gizak/termui"
+)
+
+// eventLog returns the event log of the current connector, or nil if
+// it does not keep one
+func eventLog() *connector.EventLog {
+	cSource, err := cursor.cSuper.Get()
+	if err != nil {
+		return nil
+	}
+	if es, ok := cSource.(connector.EventSource); ok {
+		return es.Events()
+	}
+	return nil
+}
+
+// EventsView displays the timeline of runtime events seen by the
+// connector, optionally moving the cursor to the container of an event
+func EventsView() MenuFn {
+	events := eventLog()
+	if events == nil {
+		log.Statusf("event timeline not supported by this connector")
+		return nil
+	}
+
+	ui.Clear()
+	ui.DefaultEvtStream.ResetHandlers()
+	defer ui.DefaultEvtStream.ResetHandlers()
+
+	v := widgets.NewEventView()
+	v.BorderLabel = "Events"
+	v.SetEvents(events.All())
+
+	HandleKeys("up", v.Up)
+	HandleKeys("down", v.Down)
+	HandleKeys("pgup", v.PgUp)
+	HandleKeys("pgdown", v.PgDown)
+	HandleKeys("exit", ui.StopLoop)
+	HandleKeys("events.type", v.NextType)
+	HandleKeys("events.container", v.ToggleContainer)
+	HandleKeys("events.probes", v.ToggleProbes)
+	HandleKeys("events.open", func() {
+		e, ok := v.Selected()
+		if !ok || e.ContainerID() == "" {
+			v.SetMessage("no container for selected event")
+			ui.Render(v)
+			return
+		}
+		if !cursor.Select(e.ContainerID()) {
+			v.SetMessage(fmt.Sprintf("container %s is not displayed", e.Attributes["name"]))
+			ui.Render(v)
+			return
+		}
+		containerView.SwitchToRunning()
+		ui.StopLoop()
+	})
+	ui.Handle("/timer/1s", func(ui.Event) { v.SetEvents(events.All()) })
+	ui.Handle("/sys/wnd/resize", func(e ui.Event) {
+		v.Resize()
+		ui.Clear()
+		ui.Render(v)
+	})
+
+	ui.Render(v)
+	ui.Loop()
+	return nil
+}
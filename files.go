@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/Betzalel75/ctop/config"
+	"github.com/Betzalel75/ctop/container"
+	"github.com/Betzalel75/ctop/cwidgets"
+	"github.com/Betzalel75/ctop/widgets"
+	"github.com/Betzalel75/ctop/widgets/menu"
+	ui "github.com/gizak/termui"
+)
+
+// maximum number of entries listed when browsing a container filesystem
+const filesLimit = 5000
+
+// DiffView displays the filesystem changes of the selected container
+// relative to its image
+func DiffView() MenuFn {
+	c := cursor.Selected()
+	if c == nil {
+		return nil
+	}
+
+	changes, err := c.Changes()
+	if err != nil {
+		log.StatusErr(err)
+		return nil
+	}
+	if len(changes) == 0 {
+		log.Statusf("no filesystem changes in %s", c.GetMeta("name"))
+		return nil
+	}
+
+	v := widgets.NewChangesView(changes)
+	v.BorderLabel = fmt.Sprintf("Diff [%s] %d changes", c.GetMeta("name"), len(changes))
+	treeLoop(v)
+	return nil
+}
+
+// FilesView browses the filesystem of the selected container
+func FilesView() MenuFn {
+	c := cursor.Selected()
+	if c == nil {
+		return nil
+	}
+
+	v, err := loadFiles(c, "/")
+	if err != nil {
+		log.StatusErr(err)
+		return nil
+	}
+
+	for {
+		switch treeLoop(v, "tree.goto", "tree.pull") {
+		case "tree.goto":
+			p := pathPrompt("Go to path", v.SelectedFile())
+			if p == "" {
+				continue
+			}
+			nv, err := loadFiles(c, p)
+			if err != nil {
+				v.SetMessage(err.Error())
+				continue
+			}
+			v = nv
+		case "tree.pull":
+			src := v.SelectedFile()
+			if src == "" {
+				continue
+			}
+			dst := pathPrompt(fmt.Sprintf("Pull %s to", path.Base(src)), ".")
+			if dst == "" {
+				continue
+			}
+			copied, err := copyFiles(c, false, src, dst)
+			if err != nil {
+				v.SetMessage(err.Error())
+				continue
+			}
+			if copied {
+				v.SetMessage(fmt.Sprintf("pulled %s to %s", src, dst))
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+func loadFiles(c *container.Container, root string) (*widgets.TreeView, error) {
+	root = path.Clean("/" + root)
+	files, truncated, err := c.ListFiles(root, filesLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	v := widgets.NewFilesView(root, files)
+	v.BorderLabel = fmt.Sprintf("Files [%s] %s", c.GetMeta("name"), root)
+	if truncated {
+		v.SetMessage(fmt.Sprintf("showing first %d entries, use [g] to open a subdirectory", filesLimit))
+	}
+	return v, nil
+}
+
+// CopyMenu copies files between the local host and the selected container
+func CopyMenu() MenuFn {
+	c := cursor.Selected()
+	if c == nil {
+		return nil
+	}
+
+	in, ok := copyDirection()
+	if !ok {
+		return nil
+	}
+
+	var src, dst string
+	name := c.GetMeta("name")
+	if in {
+		src = pathPrompt("Copy local path", ".")
+		if src == "" {
+			return nil
+		}
+		dst = pathPrompt(fmt.Sprintf("Into %s path", name), "/tmp")
+	} else {
+		src = pathPrompt(fmt.Sprintf("Copy %s path", name), "/")
+		if src == "" {
+			return nil
+		}
+		dst = pathPrompt("To local path", ".")
+	}
+	if dst == "" {
+		return nil
+	}
+
+	copied, err := copyFiles(c, in, src, dst)
+	if err != nil {
+		log.StatusErr(err)
+		return nil
+	}
+	if copied {
+		log.Statusf("copied %s to %s", src, dst)
+	}
+	return nil
+}
+
+// prompt for the direction of a copy; in is true when copying
+// local files into the container
+func copyDirection() (in bool, ok bool) {
+	ui.Clear()
+	ui.DefaultEvtStream.ResetHandlers()
+	defer ui.DefaultEvtStream.ResetHandlers()
+
+	m := menu.NewMenu()
+	m.Selectable = true
+	m.BorderLabel = "Copy"
+	m.AddItems(
+		menu.Item{Val: "in", Label: config.KeyHint("copy.in") + " local -> container"},
+		menu.Item{Val: "out", Label: config.KeyHint("copy.out") + " container -> local"},
+		menu.Item{Val: "cancel", Label: config.KeyHint("copy.cancel") + " cancel"},
+	)
+	ui.Render(m)
+
+	var selected string
+	HandleKeys("up", m.Up)
+	HandleKeys("down", m.Down)
+	HandleKeys("exit", ui.StopLoop)
+	for action, val := range map[string]string{"copy.in": "in", "copy.out": "out", "copy.cancel": "cancel"} {
+		val := val
+		HandleKeys(action, func() {
+			selected = val
+			ui.StopLoop()
+		})
+	}
+	selectFn := func() {
+		selected = m.SelectedValue()
+		ui.StopLoop()
+	}
+	HandleMenuMouse(m, nil, selectFn)
+	ui.Handle("/sys/kbd/<enter>", func(ui.Event) { selectFn() })
+	ui.Loop()
+
+	switch selected {
+	case "in":
+		return true, true
+	case "out":
+		return false, true
+	}
+	return false, false
+}
+
+// copyFiles copies src to dst, into the container if in is set, asking
+// for confirmation before overwriting an existing file. copied is false
+// if the copy was cancelled
+func copyFiles(c *container.Container, in bool, src, dst string) (copied bool, err error) {
+	target, exists, err := c.CopyTarget(in, src, dst)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		var overwrite bool
+		Confirm(fmt.Sprintf("overwrite %s?", target), func() { overwrite = true })()
+		if !overwrite {
+			return false, nil
+		}
+	}
+
+	err = runCopy(c, in, src, dst)
+	if errors.Is(err, context.Canceled) {
+		log.Statusf("copy of %s cancelled", src)
+		return false, nil
+	}
+	if err != nil {
+		log.Warningf("container %s: %v", c.Id, err)
+		return false, err
+	}
+	return true, nil
+}
+
+// run a copy, displaying its progress until it completes or is cancelled
+func runCopy(c *container.Container, in bool, src, dst string) error {
+	ui.Clear()
+	ui.DefaultEvtStream.ResetHandlers()
+	defer ui.DefaultEvtStream.ResetHandlers()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := ui.NewGauge()
+	g.BorderLabel = fmt.Sprintf("Copying %s", src)
+	g.Width = ui.TermWidth() / 2
+	g.Height = 3
+	g.X = ui.TermWidth() / 4
+	g.Y = ui.TermHeight()/2 - 1
+	g.Label = "starting..."
+	ui.Render(g)
+
+	var mu sync.Mutex
+	var done, total int64
+	progress := func(d, t int64) {
+		mu.Lock()
+		done, total = d, t
+		mu.Unlock()
+	}
+	render := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if total > 0 {
+			g.Percent = int(min(done*100/total, 100))
+			g.Label = fmt.Sprintf("{{percent}}%% (%s / %s)", cwidgets.ByteFormat64Short(done), cwidgets.ByteFormat64Short(total))
+		} else if done > 0 {
+			g.Label = fmt.Sprintf("%s copied", cwidgets.ByteFormat64Short(done))
+		}
+		ui.Render(g)
+	}
+
+	var err error
+	finished := make(chan struct{})
+	go func() {
+		if in {
+			err = c.CopyTo(ctx, src, dst, progress)
+		} else {
+			err = c.CopyFrom(ctx, src, dst, progress)
+		}
+		close(finished)
+		ui.StopLoop()
+	}()
+	go func() {
+		t := time.NewTicker(200 * time.Millisecond)
+		defer t.Stop()
+		for {
+			select {
+			case <-finished:
+				return
+			case <-t.C:
+				render()
+			}
+		}
+	}()
+
+	HandleKeys("exit", cancel)
+	ui.Loop()
+	<-finished
+	return err
+}
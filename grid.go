@@ -1,8 +1,11 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/Betzalel75/ctop/config"
 	"github.com/Betzalel75/ctop/cwidgets/single"
+	"github.com/Betzalel75/ctop/widgets"
 	ui "github.com/gizak/termui"
 )
 
@@ -50,15 +53,26 @@ func RedrawRows(clr bool) {
 
 	// build layout
 	y := 1
+	header.ShowHost(false)
 	if config.GetSwitchVal("enableHeader") {
+		if config.GetSwitchVal("hostPanel") {
+			header.ShowHost(true)
+			syncHost()
+		}
 		header.SetCount(cursor.Len())
 		header.SetFilter(config.GetVal("filterStr"))
+		var names []string
+		for _, v := range config.Views() {
+			names = append(names, v.Name)
+		}
+		header.SetViews(names, config.ActiveView())
 		y += header.Height()
 	}
 
 	cGrid.SetY(y)
 
 	for _, c := range cursor.filtered {
+		c.Widgets.SetMarked(isMarked(c.Id))
 		cGrid.AddRows(c.Widgets)
 	}
 
@@ -67,8 +81,23 @@ func RedrawRows(clr bool) {
 		log.Debugf("screen cleared")
 	}
 	
+	alignPane()
 	containerView.Align()
 	ui.Render(containerView)
+	syncPane()
+}
+
+// recreateWidgets rebuilds container rows, after a change of columns or
+// colors
+func recreateWidgets() {
+	detachPane()
+	cSource, err := cursor.cSuper.Get()
+	if err != nil {
+		return
+	}
+	for _, c := range cSource.All() {
+		c.RecreateWidgets()
+	}
 }
 
 func SingleView() MenuFn {
@@ -83,6 +112,11 @@ func SingleView() MenuFn {
 
 	ex := single.NewSingle()
 	c.SetUpdater(ex)
+	events := eventLog()
+	if events != nil {
+		ex.SetEvents(events.ForContainer(c.Id))
+	}
+	ex.SetHealthLog(c.HealthLog())
 
 	ex.Align()
 	ui.Render(ex)
@@ -91,7 +125,13 @@ func SingleView() MenuFn {
 	HandleKeys("down", ex.Down)
 	ui.Handle("/sys/kbd/", func(ui.Event) { ui.StopLoop() })
 
-	ui.Handle("/timer/1s", func(ui.Event) { ui.Render(ex) })
+	ui.Handle("/timer/1s", func(ui.Event) {
+		if events != nil {
+			ex.SetEvents(events.ForContainer(c.Id))
+		}
+		ex.SetHealthLog(c.HealthLog())
+		ui.Render(ex)
+	})
 	ui.Handle("/sys/wnd/resize", func(e ui.Event) {
 		ex.SetWidth(ui.TermWidth())
 		ex.Align()
@@ -128,136 +168,130 @@ func Display() bool {
 	cursor.RefreshContainers()
 	RedrawRows(true)
 
-	// HANDLERS POUR LE WIDGET RUNNING (par défaut)
-	HandleKeys("up", func() {
-		if containerView.IsRunningActive() {
-			cursor.Up()
+	// délègue une touche au widget actif de droite (All ou Swarm), en
+	// ouvrant le dialogue Confirm, la vue inspect ou la saisie demandée
+	allKey := func(key string) {
+		if !containerView.HandleKey(key) {
+			return
 		}
-	})
-	HandleKeys("down", func() {
-		if containerView.IsRunningActive() {
-			cursor.Down()
+		if req := containerView.PopConfirm(); req != nil {
+			menu = Confirm(req.Text, req.Fn)
+			ui.StopLoop()
+			return
 		}
-	})
-
-	HandleKeys("pgup", func() {
-		if containerView.IsRunningActive() {
-			cursor.PgUp()
+		if req := containerView.PopInspect(); req != nil {
+			menu = InspectMenu(req.Title, req.Load)
+			ui.StopLoop()
+			return
 		}
-	})
-	HandleKeys("pgdown", func() {
-		if containerView.IsRunningActive() {
-			cursor.PgDown()
+		if req := containerView.PopPrompt(); req != nil {
+			menu = func() MenuFn {
+				i := widgets.NewInput()
+				i.BorderLabel = req.Label
+				i.Data = req.Initial
+				if req.Chars != "" {
+					i.Chars = req.Chars
+				}
+				if s := prompt(i); s != "" {
+					req.Fn(s)
+				}
+				return nil
+			}
+			ui.StopLoop()
+			return
 		}
-	})
+		RedrawRows(false)
+	}
 
-	HandleKeys("exit", ui.StopLoop)
-	HandleKeys("help", func() {
-		menu = HelpMenu
-		ui.StopLoop()
-	})
+	// les actions des widgets Running et All/Swarm partagent des touches,
+	// chaque handler ignore celles du widget inactif
+	keys := make(KeyHandlers)
 
-	// HANDLERS CONDITIONNELS selon le widget actif
-	ui.Handle("/sys/kbd/<enter>", func(ui.Event) {
-		if containerView.IsRunningActive() {
-			menu = ContainerMenu
-			ui.StopLoop()
-		} else {
-			// Déléguer au widget All
-			if containerView.AllWidget.HandleKey("enter") {
-				RedrawRows(false)
+	// NAVIGATION
+	nav := func(action string, runningFn func()) {
+		keys.Add(action, func() bool {
+			if containerView.IsRunningActive() {
+				runningFn()
+				syncPane()
+			} else {
+				allKey(action)
 			}
-		}
-	})
+			return true
+		})
+	}
+	nav("up", cursor.Up)
+	nav("down", cursor.Down)
+	nav("pgup", cursor.PgUp)
+	nav("pgdown", cursor.PgDown)
 
-	ui.Handle("/sys/kbd/<left>", func(ui.Event) {
-		if containerView.IsRunningActive() {
-			menu = LogMenu
-			ui.StopLoop()
+	keys.Add("exit", func() bool {
+		// dans les widgets All et Swarm, revenir au menu ou quitter
+		if !containerView.IsRunningActive() && containerView.HandleKey("back") {
+			RedrawRows(false)
+			return true
 		}
+		ui.StopLoop()
+		return true
 	})
 
-	ui.Handle("/sys/kbd/<right>", func(ui.Event) {
-		if containerView.IsRunningActive() {
-			menu = SingleView
-			ui.StopLoop()
-		}
+	// GLOBAL
+	global := func(action string, f func()) {
+		keys.Add(action, func() bool {
+			f()
+			return true
+		})
+	}
+	global("help", func() {
+		menu = HelpMenu
+		ui.StopLoop()
 	})
-
-	ui.Handle("/sys/kbd/l", func(ui.Event) {
-		if containerView.IsRunningActive() {
-			menu = LogMenu
-			ui.StopLoop()
-		}
+	global("header", func() {
+		config.Toggle("enableHeader")
+		RedrawRows(true)
 	})
-
-	ui.Handle("/sys/kbd/e", func(ui.Event) {
-		if containerView.IsRunningActive() {
-			menu = ExecShell
-			ui.StopLoop()
-		}
+	global("host", func() {
+		config.Toggle("hostPanel")
+		RedrawRows(true)
 	})
-
-	ui.Handle("/sys/kbd/w", func(ui.Event) {
-		if containerView.IsRunningActive() {
-			menu = OpenInBrowser()
-		}
+	global("columns", func() {
+		menu = ColumnsMenu
+		ui.StopLoop()
 	})
-
-	ui.Handle("/sys/kbd/o", func(ui.Event) {
-		if containerView.IsRunningActive() {
-			menu = SingleView
-			ui.StopLoop()
-		}
+	global("profile", func() {
+		menu = ProfileMenu
+		ui.StopLoop()
 	})
-
-	ui.Handle("/sys/kbd/a", func(ui.Event) {
-		if containerView.IsRunningActive() {
-			config.Toggle("allContainers")
-			connErr = RefreshDisplay()
-			if connErr != nil {
-				ui.StopLoop()
-			}
-		}
+	global("theme", func() {
+		menu = ThemeMenu
+		ui.StopLoop()
 	})
-
-	ui.Handle("/sys/kbd/D", func(ui.Event) {
-		if containerView.IsRunningActive() {
-			dumpContainer(cursor.Selected())
-		}
+	global("split", func() {
+		config.Toggle("splitPane")
+		RedrawRows(true)
 	})
-
-	ui.Handle("/sys/kbd/f", func(ui.Event) {
-		if containerView.IsRunningActive() {
-			menu = FilterMenu
-			ui.StopLoop()
-		}
+	global("splitMode", func() {
+		nextPaneMode()
+		RedrawRows(false)
 	})
-
-	ui.Handle("/sys/kbd/H", func(ui.Event) {
-		config.Toggle("enableHeader")
+	global("splitGrow", func() {
+		resizePane(paneHeightStep)
 		RedrawRows(true)
 	})
-
-	// ui.Handle("/sys/kbd/r", func(e ui.Event) {
-	// 	if containerView.IsRunningActive() {
-	// 		config.Toggle("sortReversed")
-	// 	}
-	// })
-
-	ui.Handle("/sys/kbd/s", func(ui.Event) {
-		if containerView.IsRunningActive() {
-			menu = SortMenu
-			ui.StopLoop()
-		}
+	global("splitShrink", func() {
+		resizePane(-paneHeightStep)
+		RedrawRows(true)
 	})
-
-	ui.Handle("/sys/kbd/c", func(ui.Event) {
-		menu = ColumnsMenu
+	global("mouse", func() {
+		config.Toggle("enableMouse")
+		applyMouseMode()
+		if config.GetSwitchVal("enableMouse") {
+			log.Statusf("mouse enabled")
+		} else {
+			log.Statusf("mouse disabled")
+		}
 		ui.StopLoop()
 	})
-
-	ui.Handle("/sys/kbd/S", func(ui.Event) {
+	global("save", func() {
 		path, err := config.Write()
 		if err == nil {
 			log.Statusf("wrote config to %s", path)
@@ -266,66 +300,178 @@ func Display() bool {
 		}
 		ui.StopLoop()
 	})
-
-	ui.Handle("/sys/kbd/r", func(e ui.Event) {
-		if containerView.IsRunningActive() {
-			config.Toggle("sortReversed")
-		} else {
-			// Déléguer au widget All pour le refresh
-			if containerView.AllWidget.HandleKey("r") {
-				RedrawRows(false)
-			}
+	global("nextView", func() {
+		switch containerView.GetActiveWidget() {
+		case "running":
+			containerView.SwitchToAll()
+		case "all":
+			containerView.SwitchToSwarm()
+		default:
+			containerView.SwitchToRunning()
 		}
+		RedrawRows(false)
+	})
+	global("runningView", func() {
+		containerView.SwitchToRunning()
+		RedrawRows(false)
+	})
+	global("allView", func() {
+		containerView.SwitchToAll()
+		RedrawRows(false)
+	})
+	global("swarmView", func() {
+		containerView.SwitchToSwarm()
+		RedrawRows(false)
 	})
 
-	ui.Handle("/sys/kbd/q", func(e ui.Event) {
-		if containerView.IsRunningActive() {
-			ui.StopLoop() // Quitter l'application
-		} else {
-			// Dans le widget All, 'q' doit revenir au menu ou quitter selon le contexte
-			if containerView.AllWidget.HandleKey("q") {
-				RedrawRows(false)
-			} else {
-				ui.StopLoop() // Quitter si on est déjà au menu principal
+	// VUES ENREGISTRÉES
+	for n := 0; n < config.MaxViews; n++ {
+		n := n
+		global(fmt.Sprintf("view%d", n+1), func() {
+			if err := config.RecallView(n); err != nil {
+				log.StatusErr(err)
+				ui.StopLoop()
+				return
 			}
-		}
-	})
-	// Ajouter aussi les handlers pour PageUp/PageDown
-	ui.Handle("/sys/kbd/<prior>", func(ui.Event) { // PageUp
-		if !containerView.IsRunningActive() {
-			if containerView.AllWidget.HandleKey("pgup") {
-				RedrawRows(false)
+			recreateWidgets()
+			containerView.SwitchToRunning()
+			connErr = RefreshDisplay()
+			if connErr != nil {
+				ui.StopLoop()
 			}
+		})
+	}
+	global("saveView", func() {
+		menu = SaveViewMenu
+		ui.StopLoop()
+	})
+	global("deleteView", func() {
+		n := config.ActiveView()
+		if n < 0 {
+			log.Statusf("no view to delete")
+			ui.StopLoop()
+			return
 		}
+		name := config.Views()[n].Name
+		menu = Confirm(fmt.Sprintf("delete view %d (%s)?", n+1, name), func() {
+			if err := config.DeleteView(n); err != nil {
+				log.StatusErr(err)
+			}
+		})
+		ui.StopLoop()
 	})
 
-	ui.Handle("/sys/kbd/<next>", func(ui.Event) { // PageDown
-		if !containerView.IsRunningActive() {
-			if containerView.AllWidget.HandleKey("pgdown") {
-				RedrawRows(false)
+	// WIDGET RUNNING
+	running := func(action string, f func()) {
+		keys.Add(action, func() bool {
+			if !containerView.IsRunningActive() {
+				return false
 			}
+			f()
+			return true
+		})
+	}
+	runningMenu := func(action string, fn MenuFn) {
+		running(action, func() {
+			menu = fn
+			ui.StopLoop()
+		})
+	}
+	runningMenu("running.menu", ContainerMenu)
+	runningMenu("running.logs", LogMenu)
+	runningMenu("running.single", SingleView)
+	runningMenu("running.inspect", InspectContainer)
+	runningMenu("running.exec", ExecShell)
+	runningMenu("running.events", EventsView)
+	runningMenu("running.filter", FilterMenu)
+	runningMenu("running.sort", SortMenu)
+	runningMenu("running.compare", CompareView)
+	running("running.browser", func() { OpenInBrowser() })
+	running("running.dump", func() { dumpContainer(cursor.Selected()) })
+	running("running.mark", func() {
+		toggleMark()
+		RedrawRows(false)
+	})
+	running("running.reverse", func() { config.Toggle("sortReversed") })
+	running("running.all", func() {
+		config.Toggle("allContainers")
+		connErr = RefreshDisplay()
+		if connErr != nil {
+			ui.StopLoop()
 		}
 	})
 
-	// Ajouter handler pour la barre d'espace
-	ui.Handle("/sys/kbd/<space>", func(ui.Event) {
-		if !containerView.IsRunningActive() {
-			if containerView.AllWidget.HandleKey("space") {
-				RedrawRows(false)
+	// WIDGETS ALL ET SWARM
+	for _, action := range []string{"open", "select", "delete", "inspect", "refresh", "prune", "scale", "update", "rollback"} {
+		action := action
+		keys.Add("list."+action, func() bool {
+			if containerView.IsRunningActive() {
+				return false
 			}
+			allKey(action)
+			return true
+		})
+	}
+
+	keys.Handle()
+
+	// SOURIS
+	var clicks clickTracker
+	HandleMouse(func(m ui.EvtMouse) {
+		// panneau de détail en bas
+		if paneHeight() > 0 && m.Y >= pane.Y {
+			return
+		}
+		// widget All ou Swarm à droite
+		if m.X >= containerView.AllWidget.X {
+			switch m.Press {
+			case "MouseLeft":
+				if containerView.IsRunningActive() {
+					containerView.SwitchToList()
+					RedrawRows(false)
+				}
+			case "MouseWheelUp", "MouseWheelDown":
+				if !containerView.IsRunningActive() {
+					allKey(map[string]string{"MouseWheelUp": "up", "MouseWheelDown": "down"}[m.Press])
+				}
+			}
+			return
 		}
-	})
 
-	// Handler pour la touche 'd' (delete)
-	ui.Handle("/sys/kbd/d", func(ui.Event) {
-		if !containerView.IsRunningActive() {
-			if containerView.AllWidget.HandleKey("d") {
+		switch m.Press {
+		case "MouseWheelUp":
+			cursor.Scroll(-1)
+			syncPane()
+		case "MouseWheelDown":
+			cursor.Scroll(1)
+			syncPane()
+		case "MouseLeft":
+			if !containerView.IsRunningActive() {
+				containerView.SwitchToRunning()
 				RedrawRows(false)
 			}
+			if cGrid.IsHeader(m.Y) {
+				if sortByColumn(cGrid.ColumnAt(m.X)) {
+					connErr = RefreshDisplay()
+					if connErr != nil {
+						ui.StopLoop()
+					}
+				}
+				return
+			}
+			n := cGrid.RowAt(m.Y)
+			if n < 0 || n >= cursor.Len() {
+				return
+			}
+			cursor.Select(cursor.filtered[n].Id)
+			ui.Render(cGrid)
+			syncPane()
+			if clicks.click(n) {
+				menu = ContainerMenu
+				ui.StopLoop()
+			}
 		}
 	})
-	//
-	// 
 
 	ui.Handle("/timer/1s", func(e ui.Event) {
 		if log.StatusQueued() {
@@ -338,69 +484,9 @@ func Display() bool {
 			if connErr != nil {
 				ui.StopLoop()
 			}
-		}
-	})
-
-	// CONTRÔLES DE NAVIGATION ENTRE LES WIDGETS
-	ui.Handle("/sys/kbd/<tab>", func(ui.Event) {
-		if containerView.IsRunningActive() {
-			containerView.SwitchToAll()
-		} else {
-			containerView.SwitchToRunning()
-		}
-		RedrawRows(false)
-	})
-
-	ui.Handle("/sys/kbd/1", func(ui.Event) {
-		containerView.SwitchToRunning()
-		RedrawRows(false)
-	})
-
-	ui.Handle("/sys/kbd/2", func(ui.Event) {
-		containerView.SwitchToAll()
-		RedrawRows(false)
-	})
-
-	// GESTIONNAIRE SPÉCIFIQUE POUR LE WIDGET ALL
-	// Utiliser des touches spécifiques pour éviter les conflits
-	ui.Handle("/sys/kbd/k", func(ui.Event) {
-		if !containerView.IsRunningActive() {
-			if containerView.AllWidget.HandleKey("up") {
-				RedrawRows(false)
-			}
-		} else {
-			cursor.Up() // Comportement par défaut pour Running
-		}
-	})
-
-	ui.Handle("/sys/kbd/j", func(ui.Event) {
-		if !containerView.IsRunningActive() {
-			if containerView.AllWidget.HandleKey("down") {
-				RedrawRows(false)
-			}
-		} else {
-			cursor.Down() // Comportement par défaut pour Running
-		}
-	})
-
-	// Touches fléchées pour le widget All
-	ui.Handle("/sys/kbd/<up>", func(ui.Event) {
-		if !containerView.IsRunningActive() {
-			if containerView.AllWidget.HandleKey("up") {
-				RedrawRows(false)
-			}
-		} else {
-			cursor.Up()
-		}
-	})
-
-	ui.Handle("/sys/kbd/<down>", func(ui.Event) {
-		if !containerView.IsRunningActive() {
-			if containerView.AllWidget.HandleKey("down") {
-				RedrawRows(false)
-			}
 		} else {
-			cursor.Down()
+			// afficher les chargements asynchrones du widget All
+			RedrawRows(false)
 		}
 	})
 
@@ -415,6 +501,7 @@ func Display() bool {
 	})
 
 	ui.Loop()
+	detachPane()
 
 	if connErr != nil {
 		return ShowConnError(connErr)
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Betzalel75/ctop/config"
+	"github.com/Betzalel75/ctop/connector"
+	"github.com/Betzalel75/ctop/connector/collector"
+	"github.com/Betzalel75/ctop/models"
+)
+
+const (
+	hostInterval   = 2 * time.Second // host usage refresh
+	daemonInterval = 5               // daemon info refresh, in host refreshes
+)
+
+var hostStats = &hostMonitor{reader: collector.NewHost()}
+
+// hostMonitor reads host usage and daemon info in the background while
+// the host panel is shown
+type hostMonitor struct {
+	reader  *collector.Host
+	host    *models.Host
+	daemon  *models.Daemon
+	local   bool // containers run on this machine
+	started bool
+	lock    sync.Mutex
+}
+
+// start the background refresh, once
+func (m *hostMonitor) start() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.started {
+		return
+	}
+	m.started = true
+	go m.loop()
+}
+
+func (m *hostMonitor) loop() {
+	var n int
+	for {
+		if config.GetSwitchVal("hostPanel") {
+			if n%daemonInterval == 0 {
+				m.readDaemon()
+			}
+			m.readHost()
+			n++
+		}
+		time.Sleep(hostInterval)
+	}
+}
+
+// readDaemon reads daemon info of connectors implementing HostSource;
+// other connectors read containers of this machine
+func (m *hostMonitor) readDaemon() {
+	var daemon *models.Daemon
+	var local bool
+	if cSource, err := cursor.cSuper.Get(); err == nil {
+		if hs, ok := cSource.(connector.HostSource); ok {
+			if d, err := hs.Daemon(); err == nil {
+				daemon, local = &d, d.Local
+			} else {
+				log.Debugf("daemon info: %s", err)
+			}
+		} else {
+			local = true
+		}
+	}
+	m.lock.Lock()
+	m.daemon, m.local = daemon, local
+	m.lock.Unlock()
+}
+
+// readHost reads host usage from this machine, only if the containers
+// run on it: those of a remote daemon are on another host
+func (m *hostMonitor) readHost() {
+	var root string
+	m.lock.Lock()
+	local := m.local
+	if m.daemon != nil {
+		root = m.daemon.RootDir
+	}
+	m.lock.Unlock()
+
+	var host *models.Host
+	if local {
+		if h, err := m.reader.Read(root); err == nil {
+			host = &h
+		} else {
+			log.Debugf("host stats: %s", err)
+		}
+	}
+	m.lock.Lock()
+	m.host = host
+	m.lock.Unlock()
+}
+
+// syncHost shows the latest host usage in the header panel, with the
+// share of it used by running containers
+func syncHost() {
+	hostStats.start()
+
+	hostStats.lock.Lock()
+	header.Host.SetHost(hostStats.host)
+	header.Host.SetDaemon(hostStats.daemon)
+	hostStats.lock.Unlock()
+
+	var cpu int
+	var mem int64
+	if cSource, err := cursor.cSuper.Get(); err == nil {
+		for _, c := range cSource.All() {
+			if c.GetMeta("state") != "running" {
+				continue
+			}
+			cpu += max(c.CPUUtil, 0)
+			mem += max(c.MemUsage, 0)
+		}
+	}
+	header.Host.SetContainers(cpu, mem)
+}
@@ -1,41 +1,43 @@
 package main
 
 import (
+	"github.com/Betzalel75/ctop/config"
 	ui "github.com/gizak/termui"
 )
 
-// Common action keybindings
-var keyMap = map[string][]string{
-	"up": []string{
-		"/sys/kbd/<up>",
-		"/sys/kbd/k",
-	},
-	"down": []string{
-		"/sys/kbd/<down>",
-		"/sys/kbd/j",
-	},
-	"pgup": []string{
-		"/sys/kbd/<previous>",
-		"/sys/kbd/C-<up>",
-	},
-	"pgdown": []string{
-		"/sys/kbd/<next>",
-		"/sys/kbd/C-<down>",
-	},
-	"exit": []string{
-		"/sys/kbd/q",
-		"/sys/kbd/C-c",
-		"/sys/kbd/<escape>",
-	},
-	"help": []string{
-		"/sys/kbd/h",
-		"/sys/kbd/?",
-	},
+// Apply a common handler function to all keys bound to an action
+func HandleKeys(action string, f func()) {
+	for _, k := range config.GetKeys(action) {
+		ui.Handle("/sys/kbd/"+k, func(ui.Event) { f() })
+	}
+}
+
+// KeyHandlers dispatches keys bound to actions of several views, such
+// as the Running and All views sharing keys: handlers of a key are
+// tried in order until one returns true
+type KeyHandlers map[string][]func() bool
+
+// Add a handler to all keys bound to an action
+func (kh KeyHandlers) Add(action string, f func() bool) {
+	for _, k := range config.GetKeys(action) {
+		kh[k] = append(kh[k], f)
+	}
 }
 
-// Apply a common handler function to all given keys
-func HandleKeys(i string, f func()) {
-	for _, k := range keyMap[i] {
-		ui.Handle(k, func(ui.Event) { f() })
+// Handle registers handlers of all keys
+func (kh KeyHandlers) Handle() {
+	for k, handlers := range kh {
+		k, handlers := k, handlers
+		ui.Handle("/sys/kbd/"+k, func(e ui.Event) {
+			// paths match by prefix, <f1> would handle <f10>
+			if kbd, ok := e.Data.(ui.EvtKbd); ok && kbd.KeyStr != k {
+				return
+			}
+			for _, f := range handlers {
+				if f() {
+					return
+				}
+			}
+		})
 	}
 }
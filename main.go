@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"github.com/Betzalel75/ctop/connector"
 	"github.com/Betzalel75/ctop/container"
 	"github.com/Betzalel75/ctop/cwidgets/compact"
+	"github.com/Betzalel75/ctop/cwidgets/single"
 	"github.com/Betzalel75/ctop/logging"
 	"github.com/Betzalel75/ctop/widgets"
 	ui "github.com/gizak/termui"
@@ -30,6 +32,8 @@ var (
 	status  *widgets.StatusLine
 	errView *widgets.ErrorView
 
+	connectorName string // connector in use, profiles may name another
+
 	versionStr    = fmt.Sprintf("ctop version %v, build %v %v", version, build, goVersion)
 	containerView *widgets.ContainerView
 )
@@ -50,8 +54,10 @@ func main() {
 		activeOnlyFlag  = flag.Bool("a", false, "show active containers only")
 		sortFieldFlag   = flag.String("s", "", "select container sort field")
 		reverseSortFlag = flag.Bool("r", false, "reverse container sort order")
-		invertFlag      = flag.Bool("i", false, "invert default colors")
-		connectorFlag   = flag.String("connector", "docker", "container connector to use")
+		invertFlag      = flag.Bool("i", false, "invert default colors (same as -theme light)")
+		themeFlag       = flag.String("theme", "", "color theme: "+strings.Join(themeNames, ", ")+" or a theme of the config file")
+		connectorFlag   = flag.String("connector", "", "container connector to use (default \"docker\")")
+		profileFlag     = flag.String("profile", "", "config profile to use")
 	)
 	flag.Parse()
 
@@ -71,9 +77,27 @@ func main() {
 	// init global config and read config file if exists
 	config.Init()
 	if err := config.Read(); err != nil {
+		var keysErr *config.KeysError
+		if errors.As(err, &keysErr) {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 		log.Warningf("reading config: %s", err)
 	}
 
+	// apply the profile given on the command line, or the saved one
+	if *profileFlag != "" {
+		if err := config.SetProfile(*profileFlag); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	} else if p := config.GetVal("profile"); p != "" {
+		if err := config.SetProfile(p); err != nil {
+			log.Warningf("%s", err)
+			config.Update("profile", "")
+		}
+	}
+
 	// override default config values with command line flags
 	if *filterFlag != "" {
 		config.Update("filterStr", *filterFlag)
@@ -92,19 +116,33 @@ func main() {
 		config.Toggle("sortReversed")
 	}
 
-	// init ui
+	if *connectorFlag != "" {
+		config.Update("connector", *connectorFlag)
+	}
+
 	if *invertFlag {
-		InvertColorMap()
+		config.Update("theme", "light")
+	}
+
+	if *themeFlag != "" {
+		validTheme(*themeFlag)
+		config.Update("theme", *themeFlag)
+	}
+
+	// init ui
+	if err := SetTheme(config.GetVal("theme")); err != nil {
+		log.Warningf("%s, using the dark theme", err)
+		SetTheme("dark")
 	}
-	ui.ColorMap = ColorMap // override default colormap
 	if err := ui.Init(); err != nil {
 		panic(err)
 	}
-	tm.SetInputMode(tm.InputAlt)
+	applyMouseMode()
 
 	defer Shutdown()
 	// init grid, cursor, header
-	cSuper, err := connector.ByName(*connectorFlag)
+	connectorName = config.GetVal("connector")
+	cSuper, err := connector.ByName(connectorName)
 	if err != nil {
 		panic(err)
 	}
@@ -114,6 +152,7 @@ func main() {
 	header = widgets.NewCTopHeader()
 	status = widgets.NewStatusLine()
 	errView = widgets.NewErrorView()
+	pane = single.NewPane()
 
 	// Créer le widget ContainerView qui contient Running et All
 	containerView = widgets.NewContainerView(cGrid, header, cSuper)
@@ -144,6 +183,13 @@ func validSort(s string) {
 	}
 }
 
+func validTheme(s string) {
+	if _, err := Theme(s); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
 func panicExit() {
 	if r := recover(); r != nil {
 		Shutdown()
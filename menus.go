@@ -1,11 +1,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/Betzalel75/ctop/config"
+	"github.com/Betzalel75/ctop/connector/manager"
 	"github.com/Betzalel75/ctop/container"
 	"github.com/Betzalel75/ctop/widgets"
 	"github.com/Betzalel75/ctop/widgets/menu"
@@ -16,39 +19,27 @@ import (
 // MenuFn executes a menu window, returning the next menu or nil
 type MenuFn func() MenuFn
 
-var helpDialog = []menu.Item{
-	{Val: "Navigation:", Label: ""},
-	{Val: "<Enter> - open container menu (Running view)", Label: ""},
-	{Val: "", Label: ""},
-	{Val: "[1] - switch to Running view", Label: ""},
-	{Val: "[2] - switch to All view", Label: ""},
-	{Val: "[tab] - toggle between Running/All views", Label: ""},
-	{Val: "", Label: ""},
-	{Val: "Running view controls:", Label: ""},
-	{Val: "Arrow keys / j,k - navigate containers", Label: ""},
-	{Val: "[a] - toggle display of all containers", Label: ""},
-	{Val: "[f] - filter displayed containers", Label: ""},
-	{Val: "[s] - select container sort field", Label: ""},
-	{Val: "[r] - reverse container sort order", Label: ""},
-	{Val: "[o] - open single view", Label: ""},
-	{Val: "[l] - view container logs", Label: ""},
-	{Val: "[e] - exec shell", Label: ""},
-	{Val: "[w] - open browser", Label: ""},
-	{Val: "", Label: ""},
-	{Val: "All view controls:", Label: ""},
-	{Val: "Arrow keys / j,k - navigate menu/list", Label: ""},
-	{Val: "<enter> - select menu item / confirm", Label: ""},
-	{Val: "<space> - toggle item selection", Label: ""},
-	{Val: "[d] - delete selected items", Label: ""},
-	{Val: "[r] - refresh current list", Label: ""},
-	{Val: "[q] - back to menu / exit", Label: ""},
-	{Val: "", Label: ""},
-	{Val: "Global:", Label: ""},
-	{Val: "[h] - open this help dialog", Label: ""},
-	{Val: "[H] - toggle ctop header", Label: ""},
-	{Val: "[c] - configure columns", Label: ""},
-	{Val: "[S] - save current configuration to file", Label: ""},
-	{Val: "[q] - exit ctop", Label: ""},
+// helpItems lists the effective key bindings by scope, with action
+// names as set in the [keys] config section
+func helpItems() (items []menu.Item) {
+	for _, scope := range config.KeyScopes {
+		if len(items) > 0 {
+			items = append(items, menu.Item{Val: "", Label: ""})
+		}
+		items = append(items, menu.Item{Val: scope.Title + ":", Label: ""})
+		for _, b := range config.KeysByScope(scope.Name) {
+			if len(b.Keys) == 0 {
+				continue
+			}
+			keys := make([]string, len(b.Keys))
+			for i, k := range b.Keys {
+				keys[i] = config.FormatKey(k)
+			}
+			name := strings.TrimPrefix(b.Action, scope.Name+".")
+			items = append(items, menu.Item{Val: fmt.Sprintf("%s - %s (%s)", strings.Join(keys, " "), b.Label, name), Label: ""})
+		}
+	}
+	return items
 }
 
 func HelpMenu() MenuFn {
@@ -58,6 +49,7 @@ func HelpMenu() MenuFn {
 
 	m := menu.NewMenu()
 	m.BorderLabel = "Help"
+	helpDialog := helpItems()
 	m.AddItems(helpDialog...)
 
 	// Activer le défilement si nécessaire
@@ -79,20 +71,30 @@ func HelpMenu() MenuFn {
         ui.Render(m)
     }
 
-    // Gestion des touches
-    HandleKeys("up", func() {
-        if scrollOffset > 0 {
-            scrollOffset--
-            renderHelp()
-        }
-    })
-    
-    HandleKeys("down", func() {
-        if scrollOffset < len(helpDialog)-visibleItems() {
-            scrollOffset++
-            renderHelp()
-        }
-    })
+	scrollUp := func() {
+		if scrollOffset > 0 {
+			scrollOffset--
+			renderHelp()
+		}
+	}
+	scrollDown := func() {
+		if scrollOffset < len(helpDialog)-visibleItems() {
+			scrollOffset++
+			renderHelp()
+		}
+	}
+
+	// Gestion des touches et de la molette
+	HandleKeys("up", scrollUp)
+	HandleKeys("down", scrollDown)
+	HandleMouse(func(e ui.EvtMouse) {
+		switch e.Press {
+		case "MouseWheelUp":
+			scrollUp()
+		case "MouseWheelDown":
+			scrollDown()
+		}
+	})
     
     HandleKeys("exit", ui.StopLoop)
 	
@@ -157,14 +159,17 @@ func SortMenu() MenuFn {
 	// set cursor position to current sort field
 	m.SetCursor(config.GetVal("sortField"))
 
+	sortFn := func() {
+		config.Update("sortField", m.SelectedValue())
+		ui.StopLoop()
+	}
+
 	HandleKeys("up", m.Up)
 	HandleKeys("down", m.Down)
 	HandleKeys("exit", ui.StopLoop)
+	HandleMenuMouse(m, nil, sortFn)
 
-	ui.Handle("/sys/kbd/<enter>", func(ui.Event) {
-		config.Update("sortField", m.SelectedValue())
-		ui.StopLoop()
-	})
+	ui.Handle("/sys/kbd/<enter>", func(ui.Event) { sortFn() })
 
 	ui.Render(m)
 	ui.Loop()
@@ -186,7 +191,7 @@ func ColumnsMenu() MenuFn {
 	m.Selectable = true
 	m.SortItems = false
 	m.BorderLabel = "Columns"
-	m.SubText = "Re-order: <Page Up> / <Page Down>"
+	m.SubText = fmt.Sprintf("Re-order: %s / %s  Format: %s", config.KeyHint("pgup"), config.KeyHint("pgdown"), config.KeyHint("columns.edit"))
 
 	rebuild := func() {
 		// get padding for right alignment of enabled status
@@ -207,6 +212,9 @@ func ColumnsMenu() MenuFn {
 			} else {
 				txt += disabledStr
 			}
+			if f := col.Format.String(); f != "" {
+				txt += " " + f
+			}
 			m.AddItems(menu.Item{Val: col.Name, Label: txt})
 		}
 	}
@@ -228,26 +236,187 @@ func ColumnsMenu() MenuFn {
 		rebuild()
 	}
 
+	// edit the format of the selected column, out of the menu loop
+	editFn := func() {
+		name := m.SelectedValue()
+		i := widgets.NewInput()
+		i.BorderLabel = fmt.Sprintf("Format %s (width min max align units precision style)", name)
+		i.Data = config.GetColumnFormat(name).String()
+		s, ok := promptOk(i)
+		if !ok {
+			return
+		}
+		f, err := config.ParseColumnFormat(s)
+		if err == nil {
+			err = config.SetColumnFormat(name, f)
+		}
+		if err != nil {
+			log.StatusErr(err)
+		}
+	}
+
 	rebuild()
 
+	for {
+		var edit bool
+
+		ui.Clear()
+		ui.DefaultEvtStream.ResetHandlers()
+
+		HandleKeys("up", m.Up)
+		HandleKeys("down", m.Down)
+		HandleKeys("columns.toggle", toggleFn)
+		HandleKeys("pgup", upFn)
+		HandleKeys("pgdown", downFn)
+		HandleKeys("columns.edit", func() {
+			edit = true
+			ui.StopLoop()
+		})
+		HandleKeys("exit", ui.StopLoop)
+		HandleMenuMouse(m, nil, toggleFn)
+
+		ui.Render(m)
+		ui.Loop()
+		if !edit {
+			break
+		}
+		editFn()
+		rebuild()
+	}
+
+	recreateWidgets()
+	return nil
+}
+
+// SaveViewMenu prompts for the name of a view saving the working filter,
+// sort and columns; the current view is replaced if its name is kept
+func SaveViewMenu() MenuFn {
+	i := widgets.NewInput()
+	i.BorderLabel = "Save view"
+	if n := config.ActiveView(); n >= 0 {
+		i.Data = config.Views()[n].Name
+	}
+	name := strings.TrimSpace(prompt(i))
+	if name == "" {
+		return nil
+	}
+	n, err := config.SaveView(name)
+	if err != nil {
+		log.StatusErr(err)
+		return nil
+	}
+	log.Statusf("saved view %d: %s", n+1, name)
+	return nil
+}
+
+// ProfileMenu switches the config profile
+func ProfileMenu() MenuFn {
+	profiles := config.Profiles()
+	if len(profiles) == 0 {
+		log.Statusf("no profiles defined in the config file")
+		return nil
+	}
+
+	ui.Clear()
+	ui.DefaultEvtStream.ResetHandlers()
+	defer ui.DefaultEvtStream.ResetHandlers()
+
+	m := menu.NewMenu()
+	m.Selectable = true
+	m.BorderLabel = "Profile"
+	m.AddItems(menu.Item{Val: "", Label: "(none)"})
+	for _, name := range profiles {
+		m.AddItems(menu.Item{Val: name, Label: ""})
+	}
+	m.SetCursor(config.ActiveProfile())
+
+	var selected *string
+	selectFn := func() {
+		s := m.SelectedValue()
+		selected = &s
+		ui.StopLoop()
+	}
 	HandleKeys("up", m.Up)
 	HandleKeys("down", m.Down)
-	HandleKeys("enter", toggleFn)
-	HandleKeys("pgup", upFn)
-	HandleKeys("pgdown", downFn)
+	HandleKeys("exit", ui.StopLoop)
+	HandleMenuMouse(m, nil, selectFn)
+	ui.Handle("/sys/kbd/<enter>", func(ui.Event) { selectFn() })
 
-	ui.Handle("/sys/kbd/x", func(ui.Event) { toggleFn() })
-	ui.Handle("/sys/kbd/<enter>", func(ui.Event) { toggleFn() })
+	ui.Render(m)
+	ui.Loop()
 
-	HandleKeys("exit", func() {
-		cSource, err := cursor.cSuper.Get()
-		if err == nil {
-			for _, c := range cSource.All() {
-				c.RecreateWidgets()
-			}
+	if selected == nil {
+		return nil
+	}
+	if err := config.SetProfile(*selected); err != nil {
+		log.StatusErr(err)
+		return nil
+	}
+
+	// rebuild rows for the columns of the profile
+	recreateWidgets()
+	applyMouseMode()
+	if c := config.GetVal("connector"); c != connectorName {
+		log.Statusf("restart ctop to use the %s connector of this profile", c)
+	} else if *selected == "" {
+		log.Statusf("profile cleared")
+	} else {
+		log.Statusf("switched to profile %s", *selected)
+	}
+	return nil
+}
+
+// ThemeMenu selects the color theme, previewed on the menu itself
+func ThemeMenu() MenuFn {
+	ui.Clear()
+	ui.DefaultEvtStream.ResetHandlers()
+	defer ui.DefaultEvtStream.ResetHandlers()
+
+	m := menu.NewMenu()
+	m.Selectable = true
+	m.BorderLabel = "Theme"
+	for _, name := range ThemeNames() {
+		m.AddItems(menu.Item{Val: name, Label: ""})
+	}
+
+	current := config.GetVal("theme")
+	m.SetCursor(current)
+
+	preview := func() {
+		if err := SetTheme(m.SelectedValue()); err != nil {
+			m.SubText = err.Error()
+		} else {
+			m.SubText = ""
 		}
+		m.TextFgColor = ui.ThemeAttr("menu.text.fg")
+		m.TextBgColor = ui.ThemeAttr("menu.text.bg")
+		m.BorderFg = ui.ThemeAttr("menu.border.fg")
+		m.BorderLabelFg = ui.ThemeAttr("menu.label.fg")
+		ui.Clear()
+		ui.Render(m)
+	}
+
+	HandleKeys("up", func() {
+		m.Up()
+		preview()
+	})
+	HandleKeys("down", func() {
+		m.Down()
+		preview()
+	})
+	HandleKeys("exit", func() {
+		SetTheme(current)
 		ui.StopLoop()
 	})
+	applyFn := func() {
+		if err := applyTheme(m.SelectedValue()); err != nil {
+			SetTheme(current)
+			log.StatusErr(err)
+		}
+		ui.StopLoop()
+	}
+	HandleMenuMouse(m, preview, applyFn)
+	ui.Handle("/sys/kbd/<enter>", func(ui.Event) { applyFn() })
 
 	ui.Render(m)
 	ui.Loop()
@@ -267,28 +436,41 @@ func ContainerMenu() MenuFn {
 	m.Selectable = true
 	m.BorderLabel = "Menu"
 
-	items := []menu.Item{
-		{Val: "single", Label: "[o] single view"},
-		{Val: "logs", Label: "[l] log view"},
+	// menu items by value, with the action of their shortcut
+	var items []menu.Item
+	shortcuts := make(map[string]string)
+	add := func(val, action, label string) {
+		items = append(items, menu.Item{Val: val, Label: config.KeyHint("container."+action) + " " + label})
+		shortcuts["container."+action] = val
 	}
 
+	add("single", "single", "single view")
+	add("logs", "logs", "log view")
+	add("inspect", "inspect", "inspect")
+	add("diff", "diff", "diff")
+	add("files", "files", "browse files")
+	add("copy", "copy", "copy files")
+	add("top", "top", "processes")
+
 	if c.Meta["state"] == "running" {
-		items = append(items, menu.Item{Val: "stop", Label: "[s] stop"})
-		items = append(items, menu.Item{Val: "pause", Label: "[p] pause"})
-		items = append(items, menu.Item{Val: "restart", Label: "[r] restart"})
-		items = append(items, menu.Item{Val: "exec", Label: "[e] exec shell"})
+		add("stop", "stop", "stop")
+		add("pause", "pause", "pause")
+		add("restart", "restart", "restart")
+		add("exec", "exec", "exec shell")
 		if c.Meta["Web Port"] != "" {
-			items = append(items, menu.Item{Val: "browser", Label: "[w] open in browser"})
+			add("browser", "browser", "open in browser")
 		}
 	}
 	if c.Meta["state"] == "exited" || c.Meta["state"] == "created" {
-		items = append(items, menu.Item{Val: "start", Label: "[s] start"})
-		items = append(items, menu.Item{Val: "remove", Label: "[R] remove"})
+		add("start", "stop", "start")
+		add("remove", "remove", "remove")
 	}
 	if c.Meta["state"] == "paused" {
-		items = append(items, menu.Item{Val: "unpause", Label: "[p] unpause"})
+		add("unpause", "pause", "unpause")
 	}
-	items = append(items, menu.Item{Val: "cancel", Label: "[c] cancel"})
+	// containers can be removed in any state
+	shortcuts["container.remove"] = "remove"
+	add("cancel", "cancel", "cancel")
 
 	m.AddItems(items...)
 	ui.Render(m)
@@ -299,61 +481,22 @@ func ContainerMenu() MenuFn {
 	var selected string
 
 	// shortcuts
-	ui.Handle("/sys/kbd/o", func(ui.Event) {
-		selected = "single"
-		ui.StopLoop()
-	})
-	ui.Handle("/sys/kbd/l", func(ui.Event) {
-		selected = "logs"
-		ui.StopLoop()
-	})
-	if c.Meta["state"] != "paused" {
-		ui.Handle("/sys/kbd/s", func(ui.Event) {
-			if c.Meta["state"] == "running" {
-				selected = "stop"
-			} else {
-				selected = "start"
+	for action, val := range shortcuts {
+		val := val
+		HandleKeys(action, func() {
+			if val != "cancel" {
+				selected = val
 			}
 			ui.StopLoop()
 		})
 	}
-	if c.Meta["state"] != "exited" && c.Meta["state"] != "created" {
-		ui.Handle("/sys/kbd/p", func(ui.Event) {
-			if c.Meta["state"] == "paused" {
-				selected = "unpause"
-			} else {
-				selected = "pause"
-			}
-			ui.StopLoop()
-		})
-	}
-	if c.Meta["state"] == "running" {
-		ui.Handle("/sys/kbd/e", func(ui.Event) {
-			selected = "exec"
-			ui.StopLoop()
-		})
-		ui.Handle("/sys/kbd/r", func(ui.Event) {
-			selected = "restart"
-			ui.StopLoop()
-		})
-		if c.Meta["Web Port"] != "" {
-			ui.Handle("/sys/kbd/w", func(ui.Event) {
-				selected = "browser"
-			})
-		}
-	}
-	ui.Handle("/sys/kbd/R", func(ui.Event) {
-		selected = "remove"
-		ui.StopLoop()
-	})
-	ui.Handle("/sys/kbd/c", func(ui.Event) {
-		ui.StopLoop()
-	})
 
-	ui.Handle("/sys/kbd/<enter>", func(ui.Event) {
+	openFn := func() {
 		selected = m.SelectedValue()
 		ui.StopLoop()
-	})
+	}
+	HandleMenuMouse(m, nil, openFn)
+	ui.Handle("/sys/kbd/<enter>", func(ui.Event) { openFn() })
 	ui.Handle("/sys/kbd/", func(ui.Event) {
 		ui.StopLoop()
 	})
@@ -365,6 +508,16 @@ func ContainerMenu() MenuFn {
 		nextMenu = SingleView
 	case "logs":
 		nextMenu = LogMenu
+	case "inspect":
+		nextMenu = InspectContainer
+	case "diff":
+		nextMenu = DiffView
+	case "files":
+		nextMenu = FilesView
+	case "copy":
+		nextMenu = CopyMenu
+	case "top":
+		nextMenu = ProcessMenu
 	case "exec":
 		nextMenu = ExecShell
 	case "browser":
@@ -404,9 +557,7 @@ func LogMenu() MenuFn {
 	ui.Handle("/sys/wnd/resize", func(e ui.Event) {
 		m.Resize()
 	})
-	ui.Handle("/sys/kbd/t", func(ui.Event) {
-		m.Toggle()
-	})
+	HandleKeys("logs.timestamps", m.Toggle)
 	ui.Handle("/sys/kbd/", func(ui.Event) {
 		quit <- true
 		ui.StopLoop()
@@ -415,6 +566,227 @@ func LogMenu() MenuFn {
 	return nil
 }
 
+// ProcessMenu displays the processes of the selected container, refreshed
+// every two seconds, and sends signals to them
+func ProcessMenu() MenuFn {
+	c := cursor.Selected()
+	if c == nil {
+		return nil
+	}
+
+	procs, err := c.Top()
+	if err != nil {
+		log.StatusErr(err)
+		return nil
+	}
+	v := widgets.NewProcessView()
+	v.BorderLabel = fmt.Sprintf("Processes [%s]", c.GetMeta("name"))
+	v.SetProcesses(procs)
+
+	refresh := func() {
+		procs, err := c.Top()
+		if err != nil {
+			v.SetMessage(err.Error())
+		} else {
+			v.SetProcesses(procs)
+		}
+		ui.Render(v)
+	}
+
+	defer ui.DefaultEvtStream.ResetHandlers()
+	sigNames := map[syscall.Signal]string{syscall.SIGTERM: "SIGTERM", syscall.SIGKILL: "SIGKILL"}
+	for {
+		var sig syscall.Signal
+
+		ui.Clear()
+		ui.DefaultEvtStream.ResetHandlers()
+
+		HandleKeys("up", v.Up)
+		HandleKeys("down", v.Down)
+		HandleKeys("pgup", v.PgUp)
+		HandleKeys("pgdown", v.PgDown)
+		HandleKeys("exit", ui.StopLoop)
+		HandleKeys("processes.sort", v.NextSort)
+		HandleKeys("processes.reverse", v.Reverse)
+		HandleKeys("processes.term", func() {
+			sig = syscall.SIGTERM
+			ui.StopLoop()
+		})
+		HandleKeys("processes.kill", func() {
+			sig = syscall.SIGKILL
+			ui.StopLoop()
+		})
+		ui.Handle("/timer/1s", func(e ui.Event) {
+			if t, ok := e.Data.(ui.EvtTimer); ok && t.Count%2 == 0 {
+				refresh()
+			}
+		})
+		ui.Handle("/sys/wnd/resize", func(e ui.Event) {
+			v.Resize()
+			ui.Clear()
+			ui.Render(v)
+		})
+
+		ui.Render(v)
+		ui.Loop()
+
+		if sig == 0 {
+			return nil
+		}
+		p, ok := v.Selected()
+		if !ok {
+			continue
+		}
+		txt := fmt.Sprintf("send %s to %d (%s)?", sigNames[sig], p.PID, p.Command)
+		Confirm(txt, func() {
+			if err := c.Signal(p.PID, sig); err != nil {
+				if errors.Is(err, manager.ActionNotImplErr) {
+					err = fmt.Errorf("process %d is not on this host, only the main process can be signaled", p.PID)
+				}
+				v.SetMessage(err.Error())
+				return
+			}
+			v.SetMessage(fmt.Sprintf("sent %s to %d", sigNames[sig], p.PID))
+		})()
+		refresh()
+	}
+}
+
+func InspectContainer() MenuFn {
+	c := cursor.Selected()
+	if c == nil {
+		return nil
+	}
+	return InspectMenu(fmt.Sprintf("Inspect [%s]", c.GetMeta("name")), c.Inspect)
+}
+
+// InspectMenu displays a document returned by load as a collapsible tree
+func InspectMenu(title string, load func() (any, error)) MenuFn {
+	return func() MenuFn {
+		doc, err := load()
+		if err != nil {
+			log.StatusErr(err)
+			return nil
+		}
+		v, err := widgets.NewInspectView(doc)
+		if err != nil {
+			log.StatusErr(err)
+			return nil
+		}
+		v.BorderLabel = title
+
+		for treeLoop(v, "tree.copy") == "tree.copy" {
+			path, val := v.SelectedPath()
+			if err := widgets.CopyToClipboard(fmt.Sprintf("%s = %s", path, val)); err != nil {
+				v.SetMessage(err.Error())
+				continue
+			}
+			v.SetMessage(fmt.Sprintf("copied %s to clipboard", path))
+		}
+		return nil
+	}
+}
+
+// treeLoop runs a tree view until it is closed or a key bound to one of
+// the given actions is pressed, returning that action
+func treeLoop(v *widgets.TreeView, actions ...string) string {
+	defer ui.DefaultEvtStream.ResetHandlers()
+	for {
+		var action string
+
+		ui.Clear()
+		ui.DefaultEvtStream.ResetHandlers()
+
+		HandleKeys("up", v.Up)
+		HandleKeys("down", v.Down)
+		HandleKeys("pgup", v.PgUp)
+		HandleKeys("pgdown", v.PgDown)
+		HandleKeys("exit", ui.StopLoop)
+		HandleKeys("tree.toggle", v.Toggle)
+		HandleKeys("tree.expand", v.Expand)
+		HandleKeys("tree.collapse", v.Collapse)
+		HandleKeys("tree.next", func() {
+			v.Next()
+			ui.Render(v)
+		})
+		for _, a := range append(actions, "tree.search") {
+			a := a
+			HandleKeys(a, func() {
+				action = a
+				ui.StopLoop()
+			})
+		}
+		ui.Handle("/sys/wnd/resize", func(e ui.Event) {
+			v.Resize()
+			ui.Clear()
+			ui.Render(v)
+		})
+
+		ui.Render(v)
+		ui.Loop()
+		v.SetMessage("")
+
+		if action != "tree.search" {
+			return action
+		}
+		if q := searchPrompt(); q != "" && !v.Search(q) {
+			v.SetMessage(fmt.Sprintf("no match for %s", q))
+		}
+	}
+}
+
+// prompt for a search string at the bottom of the screen
+func searchPrompt() string {
+	i := widgets.NewInput()
+	i.BorderLabel = "Search"
+	return prompt(i)
+}
+
+// prompt for a local or container path, starting from initial
+func pathPrompt(label, initial string) string {
+	i := widgets.NewPathInput()
+	i.BorderLabel = label
+	i.Data = initial
+	return prompt(i)
+}
+
+// read a string from an input at the bottom of the screen; an empty
+// string is returned if the input is cancelled
+func prompt(i *widgets.Input) string {
+	s, _ := promptOk(i)
+	return s
+}
+
+// read a string as prompt does, reporting whether the input was
+// confirmed rather than cancelled
+func promptOk(i *widgets.Input) (string, bool) {
+	var ok bool
+	ui.DefaultEvtStream.ResetHandlers()
+	defer ui.DefaultEvtStream.ResetHandlers()
+
+	i.SetY(ui.TermHeight() - i.Height)
+	ui.Render(i)
+
+	stream := i.Stream()
+	go func() {
+		for range stream {
+		}
+	}()
+	defer close(stream)
+
+	i.InputHandlers()
+	ui.Handle("/sys/kbd/<escape>", func(ui.Event) {
+		i.Data = ""
+		ui.StopLoop()
+	})
+	ui.Handle("/sys/kbd/<enter>", func(ui.Event) {
+		ok = true
+		ui.StopLoop()
+	})
+	ui.Loop()
+	return i.Data, ok
+}
+
 func ExecShell() MenuFn {
 	c := cursor.Selected()
 
@@ -468,8 +840,8 @@ func Confirm(txt string, fn func()) MenuFn {
 		m.SubText = txt
 
 		items := []menu.Item{
-			{Val: "cancel", Label: "[c]ancel"},
-			{Val: "yes", Label: "[y]es"},
+			{Val: "cancel", Label: config.KeyHint("confirm.no") + " cancel"},
+			{Val: "yes", Label: config.KeyHint("confirm.yes") + " yes"},
 		}
 
 		var response bool
@@ -490,17 +862,19 @@ func Confirm(txt string, fn func()) MenuFn {
 		HandleKeys("up", m.Up)
 		HandleKeys("down", m.Down)
 		HandleKeys("exit", no)
-		ui.Handle("/sys/kbd/c", func(ui.Event) { no() })
-		ui.Handle("/sys/kbd/y", func(ui.Event) { yes() })
+		HandleKeys("confirm.no", no)
+		HandleKeys("confirm.yes", yes)
 
-		ui.Handle("/sys/kbd/<enter>", func(ui.Event) {
+		answer := func() {
 			switch m.SelectedValue() {
 			case "cancel":
 				no()
 			case "yes":
 				yes()
 			}
-		})
+		}
+		HandleMenuMouse(m, nil, answer)
+		ui.Handle("/sys/kbd/<enter>", func(ui.Event) { answer() })
 
 		ui.Loop()
 		if response {
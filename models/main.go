@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
 
 type Log struct {
 	Timestamp time.Time
@@ -41,6 +46,49 @@ type Metrics struct {
 	IOBytesRead  int64
 	IOBytesWrite int64
 	Pids         int
+
+	// CPU breakdown, in percent of system total like CPUUtil
+	CPUUser   int
+	CPUSystem int
+	PerCPU    []int // per-core utilization, if available
+
+	// CFS quota enforcement, from cgroup cpu.stat
+	CPUPeriods          int64 // cumulative enforcement periods
+	CPUThrottledPeriods int64 // cumulative periods the quota was hit in
+	CPUThrottledTime    int64 // cumulative throttled time, in nanoseconds
+	CPUThrottled        int   // percent of periods throttled since last reading
+
+	// memory breakdown, from cgroup memory.stat
+	MemRSS          int64
+	MemCache        int64
+	MemSwap         int64
+	MemActiveFile   int64
+	MemInactiveFile int64
+	MemMajorFaults  int64
+	OOMKills        int64
+
+	NetInterfaces []NetInterface // per-interface counters, if available
+	BlockDevices  []BlockDevice  // per-device counters, if available
+}
+
+// NetInterface holds the cumulative counters of a container network interface
+type NetInterface struct {
+	Name      string
+	Rx        int64
+	Tx        int64
+	RxErrors  int64
+	TxErrors  int64
+	RxDropped int64
+	TxDropped int64
+}
+
+// BlockDevice holds the cumulative IO counters of a block device
+type BlockDevice struct {
+	Name       string
+	ReadBytes  int64
+	WriteBytes int64
+	ReadOps    int64
+	WriteOps   int64
 }
 
 func NewMetrics() Metrics {
@@ -55,3 +103,105 @@ func NewMetrics() Metrics {
 		Pids:         -1,
 	}
 }
+
+const (
+	FileAdded   = "added"
+	FileChanged = "changed"
+	FileDeleted = "deleted"
+)
+
+// FileChange is a filesystem change of a container relative to its image
+type FileChange struct {
+	Path string
+	Kind string
+}
+
+// FileEntry describes a file of a container filesystem
+type FileEntry struct {
+	Path       string
+	Size       int64
+	Mode       os.FileMode
+	IsDir      bool
+	LinkTarget string
+}
+
+// Process is a process running in a container, as listed by `ps`
+type Process struct {
+	PID     int
+	User    string
+	CPU     float64 // percent of a single CPU
+	Mem     float64 // percent of host memory
+	RSS     int64   // resident set size, in bytes
+	Command string
+}
+
+// HealthCheck is the result of a single container health probe
+type HealthCheck struct {
+	Start    time.Time
+	End      time.Time
+	ExitCode int
+	Output   string
+}
+
+// Event is a runtime event, e.g. a container start or an image pull
+type Event struct {
+	Time       time.Time
+	Type       string // container, image, network or volume
+	Action     string
+	ID         string // ID of the object the event is about
+	Name       string // name of the object, if any
+	Attributes map[string]string
+	Probe      bool // exec event of a health probe
+}
+
+// ContainerID returns the ID of the container an event relates to, if
+// any; network events name the container in their attributes
+func (e Event) ContainerID() string {
+	if e.Type == "container" {
+		return e.ID
+	}
+	return e.Attributes["container"]
+}
+
+// AttributeString formats event attributes as sorted key=value pairs,
+// leaving out the name
+func (e Event) AttributeString() string {
+	var attrs []string
+	for k, v := range e.Attributes {
+		if k != "name" {
+			attrs = append(attrs, k+"="+v)
+		}
+	}
+	sort.Strings(attrs)
+	return strings.Join(attrs, " ")
+}
+
+// Host holds system-wide usage of the machine running the containers
+type Host struct {
+	NCpus     int
+	CPUUtil   int // percent of all CPUs
+	MemTotal  int64
+	MemUsed   int64 // excluding caches and buffers
+	SwapTotal int64
+	SwapUsed  int64
+	Load      [3]float64 // 1, 5 and 15 minute load averages
+	DiskPath  string     // mount holding the runtime data root
+	DiskTotal int64
+	DiskUsed  int64
+}
+
+// Daemon describes the container runtime daemon
+type Daemon struct {
+	Name       string
+	Version    string
+	Driver     string // storage driver
+	OS         string
+	Kernel     string
+	RootDir    string // data root, e.g. /var/lib/docker
+	NCpus      int
+	MemTotal   int64
+	Containers int
+	Running    int
+	Images     int
+	Local      bool // running on this machine, sharing its /proc
+}
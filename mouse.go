@@ -0,0 +1,110 @@
+package main
+
+import (
+	"time"
+
+	"github.com/Betzalel75/ctop/config"
+	"github.com/Betzalel75/ctop/container"
+	"github.com/Betzalel75/ctop/widgets/menu"
+	ui "github.com/gizak/termui"
+	tm "github.com/nsf/termbox-go"
+)
+
+// maximum delay between the clicks of a double click
+const doubleClickTime = 400 * time.Millisecond
+
+// sort fields of columns not named after one
+var columnSortFields = map[string]string{
+	"status": "state",
+	"cpus":   "cpu",
+}
+
+// set the terminal input mode, reporting mouse events if enabled
+func applyMouseMode() {
+	mode := tm.InputAlt
+	if config.GetSwitchVal("enableMouse") {
+		mode |= tm.InputMouse
+	}
+	tm.SetInputMode(mode)
+}
+
+// Apply a handler function to mouse events
+func HandleMouse(f func(ui.EvtMouse)) {
+	ui.Handle("/sys/mouse", func(e ui.Event) {
+		if m, ok := e.Data.(ui.EvtMouse); ok {
+			f(m)
+		}
+	})
+}
+
+// HandleMenuMouse makes a menu clickable: a click selects an item, a
+// double click opens it and the wheel moves the cursor. moved, if not
+// nil, is called after the cursor moves
+func HandleMenuMouse(m *menu.Menu, moved, open func()) {
+	var clicks clickTracker
+	if moved == nil {
+		moved = func() {}
+	}
+
+	HandleMouse(func(e ui.EvtMouse) {
+		switch e.Press {
+		case "MouseWheelUp":
+			m.Up()
+			moved()
+		case "MouseWheelDown":
+			m.Down()
+			moved()
+		case "MouseLeft":
+			n, ok := m.ItemAt(e.X, e.Y)
+			if !ok || !m.Click(e.X, e.Y) {
+				return
+			}
+			moved()
+			if clicks.click(n) {
+				open()
+			}
+		}
+	})
+}
+
+// clickTracker tells double clicks from single ones
+type clickTracker struct {
+	target int
+	at     time.Time
+}
+
+// click records a click on a target, such as a row index, returning
+// whether it doubles the previous click
+func (ct *clickTracker) click(target int) bool {
+	now := time.Now()
+	double := target == ct.target && now.Sub(ct.at) < doubleClickTime
+	ct.target, ct.at = target, now
+	if double {
+		ct.at = time.Time{} // a third click starts over
+	}
+	return double
+}
+
+// sortByColumn sorts containers by the field of the nth enabled column,
+// reversing the order if already sorted by it
+func sortByColumn(n int) bool {
+	cols := config.EnabledColumns()
+	if n < 0 || n >= len(cols) {
+		return false
+	}
+	field := cols[n]
+	if f, ok := columnSortFields[field]; ok {
+		field = f
+	}
+	if _, ok := container.Sorters[field]; !ok {
+		return false
+	}
+
+	if config.GetVal("sortField") == field {
+		config.Toggle("sortReversed")
+	} else {
+		config.Update("sortField", field)
+		config.UpdateSwitch("sortReversed", false)
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/Betzalel75/ctop/config"
+	"github.com/Betzalel75/ctop/container"
+	"github.com/Betzalel75/ctop/cwidgets"
+	"github.com/Betzalel75/ctop/cwidgets/single"
+	ui "github.com/gizak/termui"
+)
+
+const (
+	minPaneHeight  = 5  // smallest detail pane
+	minGridHeight  = 10 // lines kept for the grid above the pane
+	paneHeightStep = 2  // lines added or removed on resize
+)
+
+var (
+	pane          *single.Pane
+	paneContainer *container.Container // container followed by the pane
+	paneLogsQuit  chan bool            // stops the log stream of the pane
+)
+
+// paneHeight returns the height of the detail pane, 0 if the split
+// layout is off
+func paneHeight() int {
+	if !config.GetSwitchVal("splitPane") {
+		return 0
+	}
+	h, err := strconv.Atoi(config.GetVal("splitHeight"))
+	if err != nil {
+		h = 12
+	}
+	return max(min(h, ui.TermHeight()-minGridHeight), minPaneHeight)
+}
+
+// resizePane grows or shrinks the detail pane by n lines
+func resizePane(n int) {
+	h := paneHeight()
+	if h == 0 {
+		return
+	}
+	h = max(min(h+n, ui.TermHeight()-minGridHeight), minPaneHeight)
+	config.Update("splitHeight", strconv.Itoa(h))
+}
+
+// alignPane reserves lines below the grid for the detail pane
+func alignPane() {
+	h := paneHeight()
+	containerView.PaneHeight = h
+	cGrid.Reserve = 0
+	if h > 0 {
+		// the bottom border of the container view and the line
+		// between it and the pane
+		cGrid.Reserve = h + 3
+	}
+}
+
+// syncPane attaches the detail pane to the container under the cursor
+// and renders it, detaching it when the split layout is off
+func syncPane() {
+	h := paneHeight()
+	if h == 0 {
+		detachPane()
+		return
+	}
+
+	c := cursor.Selected()
+	if c != paneContainer {
+		detachPane()
+		pane.Reset()
+		if c != nil {
+			c.SetUpdater(cwidgets.MultiUpdater{c.Widgets, pane})
+			paneContainer = c
+		}
+	}
+
+	pane.Mode = config.GetVal("splitMode")
+	if pane.Mode == single.PaneLogs && c != nil {
+		startPaneLogs(c)
+	} else {
+		stopPaneLogs()
+	}
+
+	pane.X, pane.Y = 0, ui.TermHeight()-1-h
+	pane.Width, pane.Height = ui.TermWidth(), h
+	ui.Render(pane)
+}
+
+// detachPane stops updating the detail pane
+func detachPane() {
+	stopPaneLogs()
+	if paneContainer != nil {
+		paneContainer.SetUpdater(paneContainer.Widgets)
+		paneContainer = nil
+	}
+}
+
+// stream logs of a container to the pane, if not already
+func startPaneLogs(c *container.Container) {
+	if paneLogsQuit != nil {
+		return
+	}
+	logs, quit := logReader(c)
+	paneLogsQuit = quit
+	pane.ClearLogs()
+	go func() {
+		for l := range logs {
+			pane.AddLog(l.Toggle(false))
+		}
+	}()
+}
+
+func stopPaneLogs() {
+	if paneLogsQuit != nil {
+		paneLogsQuit <- true
+		paneLogsQuit = nil
+	}
+}
+
+// nextPaneMode cycles the content of the detail pane
+func nextPaneMode() {
+	pane.Mode = config.GetVal("splitMode")
+	pane.NextMode()
+	config.Update("splitMode", pane.Mode)
+}
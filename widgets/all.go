@@ -3,6 +3,7 @@ package widgets
 import (
 	"fmt"
 
+	"github.com/Betzalel75/ctop/config"
 	"github.com/Betzalel75/ctop/connector"
 	"github.com/Betzalel75/ctop/cwidgets/compact"
 	"github.com/Betzalel75/ctop/dtop/manager"
@@ -18,13 +19,16 @@ type AllContainers struct {
 	Header         *CTopHeader
 	connectorSuper *connector.ConnectorSuper
 	dockerManager  *manager.DockerResourceManager
-	currentMode    string // "menu", "containers", "images", "volumes", "publish"
+	currentMode    string // "menu", "containers", "images", "volumes", "networks", "publish", "disk"
 	menuItems      []MenuItem
 	selectedIndex  int
 	statusMsg      string
 	publishData    *PublishData
 	resources      []resource.ResourceItem // Liste des ressources actuelles
 	resourceType   string                  // Type de ressource actuel
+	diskUsage      *resource.DiskUsage
+	confirm        *ConfirmRequest // confirmation en attente, voir PopConfirm
+	inspect        *InspectRequest // inspection en attente, voir PopInspect
 
 	// NOUVEAU: Variables de pagination
 	currentPage     int
@@ -37,6 +41,19 @@ type MenuItem struct {
 	Description string
 }
 
+// ConfirmRequest is an action awaiting user confirmation before
+// being performed
+type ConfirmRequest struct {
+	Text string
+	Fn   func()
+}
+
+// InspectRequest asks for the inspect view of a resource to be opened
+type InspectRequest struct {
+	Title string
+	Load  func() (any, error)
+}
+
 type PublishData struct {
 	Registry string
 	Username string
@@ -61,6 +78,8 @@ func NewAllContainers(grid *compact.CompactGrid,
 			{"📦 Delete Containers", ""},
 			{"💿 Delete Images", ""},
 			{"💾 Delete Volumes", ""},
+			{"🌐 Networks", ""},
+			{"📊 Disk Usage", ""},
 		},
 		selectedIndex: 0,
 		resources:     []resource.ResourceItem{},
@@ -105,7 +124,13 @@ func (a *AllContainers) adjustSelectedIndexForPage() {
 }
 
 func (a *AllContainers) getDockerClient() (*api.Client, error) {
-	conn, err := a.connectorSuper.Get()
+	return dockerClient(a.connectorSuper)
+}
+
+// dockerClient returns the client of the current connector, which must
+// be a Docker connector
+func dockerClient(cSuper *connector.ConnectorSuper) (*api.Client, error) {
+	conn, err := cSuper.Get()
 	if err != nil {
 		return nil, err
 	}
@@ -142,10 +167,12 @@ func (a *AllContainers) Buffer() ui.Buffer {
 	switch a.currentMode {
 	case "menu":
 		a.renderMenu(buf, innerX, innerY, innerWidth)
-	case "containers", "images", "volumes":
+	case "containers", "images", "volumes", "networks":
 		a.renderResourceList(buf, innerX, innerY, innerWidth)
 	case "publish":
 		a.renderPublishFlow(buf, innerX, innerY, innerWidth)
+	case "disk":
+		a.renderDiskUsage(buf, innerX, innerY, innerWidth)
 	}
 
 	// Afficher le message de statut
@@ -223,6 +250,12 @@ func (a *AllContainers) renderMenu(buf ui.Buffer, x, y, width int) {
 }
 
 
+// deletable reports whether items of the current list can be deleted;
+// networks are listed for inspection only
+func (a *AllContainers) deletable() bool {
+	return a.resourceType != "networks"
+}
+
 func (a *AllContainers) renderResourceList(buf ui.Buffer, x, y, width int) {
 	totalPages := a.getTotalPages()
 	currentPageItems := a.getCurrentPageItems()
@@ -247,7 +280,11 @@ func (a *AllContainers) renderResourceList(buf ui.Buffer, x, y, width int) {
 	}
 
 	// Instructions mises à jour
-	instructions := "↑↓: navigate, PgUp/PgDn: pages, Space: select, d: delete, r: refresh, q: back"
+	instructions := "↑↓ navigate  " + config.KeyHint("pgup") + "/" + config.KeyHint("pgdown") + " pages  "
+	if a.deletable() {
+		instructions += keyHints("list.select", "select", "list.delete", "delete") + "  "
+	}
+	instructions += keyHints("list.inspect", "inspect", "list.refresh", "refresh", "exit", "back")
 	instructY := y + 1
 	for i, ch := range instructions {
 		if i >= width {
@@ -280,6 +317,9 @@ func (a *AllContainers) renderResourceList(buf ui.Buffer, x, y, width int) {
 		if res.Selected {
 			checkbox = "[x]"
 		}
+		if !a.deletable() {
+			checkbox = "-"
+		}
 		
 		// Marquer l'item sélectionné avec > et couleur différente
 		prefix := "  "
@@ -359,7 +399,7 @@ func (a *AllContainers) renderResourceList(buf ui.Buffer, x, y, width int) {
 	
 	// Info de navigation
 	if totalPages > 1 {
-		navInfo := fmt.Sprintf("Page %d/%d - Use PgUp/PgDn to navigate", a.currentPage+1, totalPages)
+		navInfo := fmt.Sprintf("Page %d/%d - Use %s/%s to navigate", a.currentPage+1, totalPages, config.KeyHint("pgup"), config.KeyHint("pgdown"))
 		navY := paginationY + 1
 		for i, ch := range navInfo {
 			if i >= width {
@@ -430,29 +470,45 @@ func (a *AllContainers) HandleKey(key string) bool {
 	switch a.currentMode {
 	case "menu":
 		return a.handleMenuKey(key)
-	case "containers", "images", "volumes":
+	case "containers", "images", "volumes", "networks":
 		return a.handleResourceKey(key)
 	case "publish":
 		return a.handlePublishKey(key)
+	case "disk":
+		return a.handleDiskKey(key)
 	}
 	return false
 }
 
+// PopConfirm returns and clears any action awaiting confirmation
+func (a *AllContainers) PopConfirm() *ConfirmRequest {
+	req := a.confirm
+	a.confirm = nil
+	return req
+}
+
+// PopInspect returns and clears any pending inspect view request
+func (a *AllContainers) PopInspect() *InspectRequest {
+	req := a.inspect
+	a.inspect = nil
+	return req
+}
+
 func (a *AllContainers) handleMenuKey(key string) bool {
 	switch key {
-	case "up", "k":
+	case "up":
 		if a.selectedIndex > 0 {
 			a.selectedIndex--
 			a.statusMsg = fmt.Sprintf("Selected: %s", a.menuItems[a.selectedIndex].Title)
 		}
 		return true
-	case "down", "j":
+	case "down":
 		if a.selectedIndex < len(a.menuItems)-1 {
 			a.selectedIndex++
 			a.statusMsg = fmt.Sprintf("Selected: %s", a.menuItems[a.selectedIndex].Title)
 		}
 		return true
-	case "enter":
+	case "open":
 		return a.selectMenuItem()
 	}
 	return false
@@ -461,16 +517,21 @@ func (a *AllContainers) handleMenuKey(key string) bool {
 
 func (a *AllContainers) handleResourceKey(key string) bool {
 	currentPageItems := a.getCurrentPageItems()
+
+	if (key == "select" || key == "delete") && !a.deletable() {
+		a.statusMsg = "Networks can only be inspected here."
+		return true
+	}
 	
 	switch key {
-	case "q", "esc":
+	case "back":
 		a.currentMode = "menu"
 		a.statusMsg = ""
 		a.selectedIndex = 0
 		a.currentPage = 0
 		return true
 		
-	case "up", "k":
+	case "up":
 		if a.selectedIndex > 0 {
 			a.selectedIndex--
 		} else if a.currentPage > 0 {
@@ -481,7 +542,7 @@ func (a *AllContainers) handleResourceKey(key string) bool {
 		}
 		return true
 		
-	case "down", "j":
+	case "down":
 		maxIndex := min(len(currentPageItems)-1, a.maxDisplayItems-1)
 		if a.selectedIndex < maxIndex {
 			a.selectedIndex++
@@ -506,7 +567,7 @@ func (a *AllContainers) handleResourceKey(key string) bool {
 		}
 		return true
 		
-	case "space":
+	case "select":
 		// Toggle selection du current item
 		if a.selectedIndex >= 0 && a.selectedIndex < len(currentPageItems) {
 			realIndex := a.currentPage*a.itemsPerPage + a.selectedIndex
@@ -518,12 +579,12 @@ func (a *AllContainers) handleResourceKey(key string) bool {
 						selectedCount++
 					}
 				}
-				a.statusMsg = fmt.Sprintf("%d items selected. Press 'd' to delete.", selectedCount)
+				a.statusMsg = fmt.Sprintf("%d items selected. Press %s to delete.", selectedCount, config.KeyHint("list.delete"))
 			}
 		}
 		return true
 		
-	case "d":
+	case "delete":
 		// Delete selected items
 		var toDelete []resource.ResourceItem
 		for _, res := range a.resources {
@@ -535,11 +596,29 @@ func (a *AllContainers) handleResourceKey(key string) bool {
 			a.statusMsg = fmt.Sprintf("Deleting %d items...", len(toDelete))
 			go a.deleteSelectedItems(toDelete)
 		} else {
-			a.statusMsg = "No items selected. Press space to select items."
+			a.statusMsg = "No items selected. Press " + config.KeyHint("list.select") + " to select items."
 		}
 		return true
 		
-	case "r": // Refresh manual
+	case "inspect":
+		if a.selectedIndex < 0 || a.selectedIndex >= len(currentPageItems) {
+			return true
+		}
+		res := currentPageItems[a.selectedIndex]
+		resourceType := a.resourceType
+		a.inspect = &InspectRequest{
+			Title: fmt.Sprintf("Inspect %s [%s]", resourceType, res.Title),
+			Load: func() (any, error) {
+				dm, err := a.getDockerManager()
+				if err != nil {
+					return nil, err
+				}
+				return dm.Inspect(resourceType, res.Id)
+			},
+		}
+		return true
+
+	case "refresh":
 		a.statusMsg = "Refreshing..."
 		a.currentPage = 0
 		a.selectedIndex = 0
@@ -552,13 +631,13 @@ func (a *AllContainers) handleResourceKey(key string) bool {
 
 func (a *AllContainers) handlePublishKey(key string) bool {
 	switch key {
-	case "q", "esc":
+	case "back":
 		a.currentMode = "menu"
 		a.publishData = nil
 		a.statusMsg = ""
 		a.selectedIndex = 0
 		return true
-	case "enter":
+	case "open":
 		if a.publishData != nil && a.publishData.Step == 3 {
 			// Confirmer la sélection d'images
 			selectedCount := 0
@@ -568,14 +647,14 @@ func (a *AllContainers) handlePublishKey(key string) bool {
 				}
 			}
 			if selectedCount > 0 {
-				a.statusMsg = fmt.Sprintf("Ready to publish %d images. Press 'p' to confirm.", selectedCount)
+				a.statusMsg = fmt.Sprintf("Ready to publish %d images. Press %s to confirm.", selectedCount, config.KeyHint("list.prune"))
 				a.publishData.Step = 4
 			} else {
-				a.statusMsg = "No images selected. Press space to select images."
+				a.statusMsg = "No images selected. Press " + config.KeyHint("list.select") + " to select images."
 			}
 		}
 		return true
-	case "space":
+	case "select":
 		if a.publishData != nil && a.publishData.Step == 3 {
 			// Toggle selection pour publish
 			if a.selectedIndex >= 0 && a.selectedIndex < len(a.resources) {
@@ -590,19 +669,19 @@ func (a *AllContainers) handlePublishKey(key string) bool {
 			}
 		}
 		return true
-	case "p":
+	case "prune":
 		if a.publishData != nil && a.publishData.Step == 4 {
 			// Lancer la publication
 			go a.publishSelectedImages()
 			a.statusMsg = "Publishing images..."
 		}
 		return true
-	case "up", "k":
+	case "up":
 		if a.publishData != nil && a.publishData.Step == 3 && a.selectedIndex > 0 {
 			a.selectedIndex--
 		}
 		return true
-	case "down", "j":
+	case "down":
 		if a.publishData != nil && a.publishData.Step == 3 && a.selectedIndex < len(a.resources)-1 {
 			a.selectedIndex++
 		}
@@ -631,6 +710,13 @@ func (a *AllContainers) selectMenuItem() bool {
 			a.currentMode = "volumes"
 			a.resourceType = "volumes"
 			go a.loadVolumes()
+		case "🌐 Networks":
+			a.currentMode = "networks"
+			a.resourceType = "networks"
+			go a.loadNetworks()
+		case "📊 Disk Usage":
+			a.currentMode = "disk"
+			go a.loadDiskUsage()
 		}
 		a.selectedIndex = 0 // Reset selection pour les listes
 		return true
@@ -719,6 +805,29 @@ func (a *AllContainers) loadVolumes() {
 	a.statusMsg = fmt.Sprintf("Loaded %d volumes", len(a.resources))
 }
 
+func (a *AllContainers) loadNetworks() {
+	dm, err := a.getDockerManager()
+	if err != nil {
+		a.statusMsg = fmt.Sprintf("Error getting Docker client: %v", err)
+		return
+	}
+
+	items, err := dm.LoadNetworks()
+	if err != nil {
+		a.statusMsg = fmt.Sprintf("Error loading networks: %v", err)
+		return
+	}
+
+	// Convertir les items en ResourceItem
+	a.resources = make([]resource.ResourceItem, 0, len(items))
+	for _, item := range items {
+		if resItem, ok := item.(resource.ResourceItem); ok {
+			a.resources = append(a.resources, resItem)
+		}
+	}
+	a.statusMsg = fmt.Sprintf("Loaded %d networks", len(a.resources))
+}
+
 func (a *AllContainers) loadImagesForPublish() {
 	dm, err := a.getDockerManager()
 	if err != nil {
@@ -814,5 +923,9 @@ func (a *AllContainers) refreshCurrentList() {
 		go a.loadImages()
 	case "volumes":
 		go a.loadVolumes()
+	case "networks":
+		go a.loadNetworks()
+	case "disk":
+		go a.loadDiskUsage()
 	}
 }
@@ -0,0 +1,16 @@
+package widgets
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+)
+
+// CopyToClipboard sets the system clipboard of the controlling terminal
+// using an OSC52 escape sequence; this also works over ssh, provided the
+// terminal emulator supports it
+func CopyToClipboard(s string) error {
+	enc := base64.StdEncoding.EncodeToString([]byte(s))
+	_, err := fmt.Fprintf(os.Stdout, "\x1b]52;c;%s\x07", enc)
+	return err
+}
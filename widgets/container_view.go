@@ -10,9 +10,12 @@ type ContainerView struct {
 	*ui.Block
 	RunningWidget  *RunningContainers
 	AllWidget      *AllContainers
-	activeWidget   string // "running" ou "all"
+	SwarmWidget    *SwarmServices
+	activeWidget   string // "running", "all" ou "swarm"
+	rightWidget    string // widget affiché à droite, "all" ou "swarm"
 	Grid           *compact.CompactGrid
 	Header         *CTopHeader
+	PaneHeight     int // lignes réservées en bas au panneau de détail
 	connectorSuper *connector.ConnectorSuper
 }
 
@@ -23,12 +26,14 @@ func NewContainerView(grid *compact.CompactGrid,
 		Grid:           grid,
 		Header:         header,
 		activeWidget:   "running", // Par défaut sur "running"
+		rightWidget:    "all",
 		connectorSuper: cSuper,
 	}
 
 	cv.Border = false
 	cv.RunningWidget = NewRunningContainers(grid, header)
 	cv.AllWidget = NewAllContainers(grid, header, cSuper)
+	cv.SwarmWidget = NewSwarmServices(cSuper)
 
 	return cv
 }
@@ -45,8 +50,11 @@ func (cv *ContainerView) Buffer() ui.Buffer {
 	// Calculer les dimensions pour chaque widget (50% chacun)
 	halfWidth := cv.Width / 2
 
-	// Position Y après le header (1 ligne pour le header + 1 ligne d'espace)
+	// Position Y après le header (1 ligne, plus le panneau hôte s'il est affiché)
     yOffset := 1
+    if cv.Header != nil {
+        yOffset = cv.Header.Height()
+    }
 	
 	// Positionner le widget Running à gauche
 	cv.RunningWidget.X = cv.X
@@ -59,29 +67,55 @@ func (cv *ContainerView) Buffer() ui.Buffer {
     cv.AllWidget.Y = cv.Y + yOffset
     cv.AllWidget.Width = cv.Width - halfWidth
     cv.AllWidget.Height = cv.Height - yOffset - 1 // -1 pour la status line
+	cv.SwarmWidget.X, cv.SwarmWidget.Y = cv.AllWidget.X, cv.AllWidget.Y
+	cv.SwarmWidget.Width, cv.SwarmWidget.Height = cv.AllWidget.Width, cv.AllWidget.Height
 	
 	if cv.activeWidget == "running" {
 		cv.RunningWidget.BorderFg = ui.ThemeAttr("status.ok") // Vert pour actif
 		cv.RunningWidget.BorderLabel = "Running [ACTIVE]"
 		cv.AllWidget.BorderFg = ui.ThemeAttr("border.fg") // Couleur normale
 		cv.AllWidget.BorderLabel = "All"
+		cv.SwarmWidget.BorderFg = ui.ThemeAttr("border.fg")
+		cv.SwarmWidget.BorderLabel = "Swarm"
 	} else {
 		cv.RunningWidget.BorderFg = ui.ThemeAttr("border.fg") // Couleur normale
 		cv.RunningWidget.BorderLabel = "Running"
 		cv.AllWidget.BorderFg = ui.ThemeAttr("status.ok") // Vert pour actif
 		cv.AllWidget.BorderLabel = "All [ACTIVE]"
+		cv.SwarmWidget.BorderFg = ui.ThemeAttr("status.ok")
+		cv.SwarmWidget.BorderLabel = "Swarm [ACTIVE]"
 	}
 	
 	// Rendre les deux widgets
 	buf.Merge(cv.RunningWidget.Buffer())
-	buf.Merge(cv.AllWidget.Buffer())
+	if cv.rightWidget == "swarm" {
+		buf.Merge(cv.SwarmWidget.Buffer())
+	} else {
+		buf.Merge(cv.AllWidget.Buffer())
+	}
 
 	return buf
 }
 
+// Recolor applies the current theme to the widgets, which read theme
+// colors when created
+func (cv *ContainerView) Recolor() {
+	for _, b := range []*ui.Block{cv.Block, cv.RunningWidget.Block, cv.AllWidget.Block, cv.SwarmWidget.Block} {
+		nb := ui.NewBlock()
+		b.BorderFg, b.BorderBg = nb.BorderFg, nb.BorderBg
+		b.BorderLabelFg, b.BorderLabelBg = nb.BorderLabelFg, nb.BorderLabelBg
+		b.Bg = nb.Bg
+	}
+	cv.Header.Recolor()
+	// les lignes du widget Swarm sont recréées au rechargement
+	if cv.rightWidget == "swarm" {
+		cv.SwarmWidget.Refresh()
+	}
+}
+
 func (cv *ContainerView) Align() {
 	cv.Width = ui.TermWidth()
-	cv.Height = ui.TermHeight() - 1 // -1 pour la status line
+	cv.Height = ui.TermHeight() - 1 - cv.PaneHeight // -1 pour la status line
 }
 
 func (cv *ContainerView) SwitchToRunning() {
@@ -90,6 +124,60 @@ func (cv *ContainerView) SwitchToRunning() {
 
 func (cv *ContainerView) SwitchToAll() {
 	cv.activeWidget = "all"
+	cv.rightWidget = "all"
+}
+
+// SwitchToList active le widget affiché à droite, All ou Swarm
+func (cv *ContainerView) SwitchToList() {
+	if cv.rightWidget == "swarm" {
+		cv.SwitchToSwarm()
+		return
+	}
+	cv.SwitchToAll()
+}
+
+// SwitchToSwarm active le widget Swarm, en chargeant les services à
+// la première ouverture
+func (cv *ContainerView) SwitchToSwarm() {
+	if cv.rightWidget != "swarm" || cv.SwarmWidget.services == nil {
+		cv.SwarmWidget.Refresh()
+	}
+	cv.activeWidget = "swarm"
+	cv.rightWidget = "swarm"
+}
+
+// HandleKey délègue une action au widget actif de droite : up, down,
+// pgup, pgdown, open, back, select, delete, inspect, refresh, prune,
+// scale, update ou rollback
+func (cv *ContainerView) HandleKey(key string) bool {
+	if cv.activeWidget == "swarm" {
+		return cv.SwarmWidget.HandleKey(key)
+	}
+	return cv.AllWidget.HandleKey(key)
+}
+
+// PopConfirm retourne l'action en attente de confirmation du widget actif
+func (cv *ContainerView) PopConfirm() *ConfirmRequest {
+	if cv.activeWidget == "swarm" {
+		return cv.SwarmWidget.PopConfirm()
+	}
+	return cv.AllWidget.PopConfirm()
+}
+
+// PopInspect retourne la vue inspect demandée par le widget actif
+func (cv *ContainerView) PopInspect() *InspectRequest {
+	if cv.activeWidget == "swarm" {
+		return cv.SwarmWidget.PopInspect()
+	}
+	return cv.AllWidget.PopInspect()
+}
+
+// PopPrompt retourne la saisie demandée par le widget actif
+func (cv *ContainerView) PopPrompt() *PromptRequest {
+	if cv.activeWidget == "swarm" {
+		return cv.SwarmWidget.PopPrompt()
+	}
+	return nil
 }
 
 func (cv *ContainerView) GetActiveWidget() string {
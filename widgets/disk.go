@@ -0,0 +1,160 @@
+package widgets
+
+import (
+	"fmt"
+
+	"github.com/Betzalel75/ctop/cwidgets"
+	"github.com/Betzalel75/ctop/dtop/resource"
+	ui "github.com/gizak/termui"
+)
+
+// Vue "docker system df -v" de l'onglet All
+
+func (a *AllContainers) renderDiskUsage(buf ui.Buffer, x, y, width int) {
+	title := "Disk Usage"
+	for i, ch := range title {
+		if i >= width {
+			break
+		}
+		buf.Set(x+i, y, ui.Cell{Ch: ch, Fg: ui.ThemeAttr("header.fg"), Bg: ui.ColorDefault})
+	}
+	a.renderTextLine(buf, x, y+1, width, "↑↓ navigate  "+keyHints("list.prune", "prune category", "list.refresh", "refresh", "exit", "back"))
+
+	if a.diskUsage == nil {
+		return
+	}
+
+	maxY := a.Y + a.Height - 3
+	lineY := y + 3
+	for i, cat := range a.diskUsage.Categories {
+		if lineY >= maxY {
+			break
+		}
+
+		prefix := "  "
+		fg := ui.ThemeAttr("par.text.fg")
+		if i == a.selectedIndex {
+			prefix = "> "
+			fg = ui.ThemeAttr("status.ok")
+		}
+
+		summary := fmt.Sprintf("%s%-14s %3d total %3d active  %8s  reclaimable %s (%d%%)",
+			prefix, cat.Name, cat.Total, cat.Active,
+			cwidgets.ByteFormat64Short(cat.Size),
+			cwidgets.ByteFormat64Short(cat.Reclaimable),
+			diskPercent(cat.Reclaimable, cat.Size))
+		for j, ch := range []rune(summary) {
+			if j >= width {
+				break
+			}
+			buf.Set(x+j, lineY, ui.Cell{Ch: ch, Fg: fg, Bg: ui.ColorDefault})
+		}
+		lineY++
+
+		for _, item := range cat.Top {
+			if lineY >= maxY {
+				break
+			}
+			a.renderTextLine(buf, x, lineY, width, fmt.Sprintf("      %8s  %s", cwidgets.ByteFormat64Short(item.Size), item.Title))
+			lineY++
+		}
+		lineY++
+	}
+}
+
+func (a *AllContainers) handleDiskKey(key string) bool {
+	switch key {
+	case "back":
+		a.currentMode = "menu"
+		a.statusMsg = ""
+		a.selectedIndex = 0
+		a.diskUsage = nil
+		return true
+	case "up":
+		if a.selectedIndex > 0 {
+			a.selectedIndex--
+		}
+		return true
+	case "down":
+		if a.diskUsage != nil && a.selectedIndex < len(a.diskUsage.Categories)-1 {
+			a.selectedIndex++
+		}
+		return true
+	case "prune":
+		if a.diskUsage == nil || a.selectedIndex >= len(a.diskUsage.Categories) {
+			return true
+		}
+		cat := a.diskUsage.Categories[a.selectedIndex]
+		if cat.Reclaimable == 0 {
+			a.statusMsg = fmt.Sprintf("Nothing to reclaim in %s", cat.Name)
+			return true
+		}
+		a.confirm = &ConfirmRequest{
+			Text: fmt.Sprintf("prune unused %s (%s)?", cat.Name, cwidgets.ByteFormat64Short(cat.Reclaimable)),
+			Fn: func() {
+				a.statusMsg = fmt.Sprintf("Pruning %s...", cat.Name)
+				go a.pruneDisk(cat.Name)
+			},
+		}
+		return true
+	case "refresh":
+		a.statusMsg = "Refreshing..."
+		go a.loadDiskUsage()
+		return true
+	}
+	return false
+}
+
+func (a *AllContainers) loadDiskUsage() {
+	dm, err := a.getDockerManager()
+	if err != nil {
+		a.statusMsg = fmt.Sprintf("Error getting Docker client: %v", err)
+		return
+	}
+
+	du, err := dm.LoadDiskUsage()
+	if err != nil {
+		a.statusMsg = fmt.Sprintf("Error loading disk usage: %v", err)
+		return
+	}
+
+	var total, reclaimable int64
+	for _, cat := range du.Categories {
+		total += cat.Size
+		reclaimable += cat.Reclaimable
+	}
+	a.diskUsage = du
+	a.statusMsg = fmt.Sprintf("Total %s, reclaimable %s",
+		cwidgets.ByteFormat64Short(total), cwidgets.ByteFormat64Short(reclaimable))
+}
+
+func (a *AllContainers) pruneDisk(category string) {
+	dm, err := a.getDockerManager()
+	if err != nil {
+		a.statusMsg = fmt.Sprintf("Error getting Docker manager: %v", err)
+		return
+	}
+
+	a.reportDeleted(dm.Prune(category))
+	if a.currentMode == "disk" {
+		status := a.statusMsg
+		a.loadDiskUsage()
+		a.statusMsg = status
+	}
+}
+
+// reportDeleted shows the outcome of a delete or prune operation
+func (a *AllContainers) reportDeleted(msg resource.ResourcesDeletedMsg) {
+	if len(msg.Errs) > 0 {
+		a.statusMsg = fmt.Sprintf("Error deleting: %v", msg.Errs[0])
+		return
+	}
+	a.statusMsg = fmt.Sprintf("Reclaimed %s", cwidgets.ByteFormat64Short(msg.Reclaimed))
+}
+
+func diskPercent(val, total int64) int {
+	if total <= 0 {
+		return 0
+	}
+	return int(val * 100 / total)
+}
@@ -0,0 +1,246 @@
+package widgets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Betzalel75/ctop/models"
+	ui "github.com/gizak/termui"
+)
+
+// event types the timeline can be filtered on, "" for all
+var eventTypes = []string{"", "container", "image", "network", "volume"}
+
+// event table columns, in display order
+var eventColumns = []struct {
+	Title string
+	Width int // 0 for remaining width
+}{
+	{"TIME", 10},
+	{"TYPE", 11},
+	{"NAME", 26},
+	{"ACTION", 22},
+	{"ATTRIBUTES", 0},
+}
+
+// EventView displays a timeline of runtime events, most recent first
+type EventView struct {
+	ui.Block
+	TextFgColor ui.Attribute
+	TextBgColor ui.Attribute
+	all         []models.Event
+	events      []models.Event // events matching the filters
+	typeIdx     int            // index of the type filter in eventTypes
+	container   string         // container ID filter
+	probes      bool           // show exec events of health probes
+	cursorPos   int
+	offset      int
+	message     string
+	padding     Padding
+}
+
+func NewEventView() *EventView {
+	v := &EventView{
+		Block:       *ui.NewBlock(),
+		TextFgColor: ui.ThemeAttr("menu.text.fg"),
+		TextBgColor: ui.ThemeAttr("menu.text.bg"),
+		padding:     Padding{2, 1},
+	}
+	v.BorderFg = ui.ThemeAttr("menu.border.fg")
+	v.BorderLabelFg = ui.ThemeAttr("menu.label.fg")
+	v.Resize()
+	return v
+}
+
+func (v *EventView) Resize() {
+	v.Height = ui.TermHeight()
+	v.Width = ui.TermWidth()
+}
+
+// SetEvents replaces the displayed events, given oldest first, keeping
+// the cursor on the selected event
+func (v *EventView) SetEvents(events []models.Event) {
+	v.all = events
+	v.apply()
+}
+
+// SetMessage sets a message shown in the footer of the view
+func (v *EventView) SetMessage(s string) { v.message = s }
+
+// Selected returns the event under the cursor, if any
+func (v *EventView) Selected() (models.Event, bool) {
+	if v.cursorPos >= 0 && v.cursorPos < len(v.events) {
+		return v.events[v.cursorPos], true
+	}
+	return models.Event{}, false
+}
+
+// NextType filters events by the next event type
+func (v *EventView) NextType() {
+	v.typeIdx = (v.typeIdx + 1) % len(eventTypes)
+	v.apply()
+}
+
+// ToggleContainer filters events by the container of the selected
+// event, or clears the container filter if set
+func (v *EventView) ToggleContainer() {
+	if v.container != "" {
+		v.container = ""
+	} else if e, ok := v.Selected(); ok && e.ContainerID() != "" {
+		v.container = e.ContainerID()
+	} else {
+		v.message = "no container for selected event"
+	}
+	v.apply()
+}
+
+// ToggleProbes shows or hides exec events of health probes, run every
+// few seconds in each container with a health check
+func (v *EventView) ToggleProbes() {
+	v.probes = !v.probes
+	v.apply()
+}
+
+// apply rebuilds the filtered events from the full list
+func (v *EventView) apply() {
+	selected, ok := v.Selected()
+
+	v.events = v.events[:0]
+	typ := eventTypes[v.typeIdx]
+	for i := len(v.all) - 1; i >= 0; i-- {
+		e := v.all[i]
+		if typ != "" && e.Type != typ {
+			continue
+		}
+		if v.container != "" && e.ContainerID() != v.container {
+			continue
+		}
+		if e.Probe && !v.probes {
+			continue
+		}
+		v.events = append(v.events, e)
+	}
+
+	if ok {
+		for i, e := range v.events {
+			if e.Time.Equal(selected.Time) && e.ID == selected.ID && e.Action == selected.Action {
+				v.cursorPos = i
+				break
+			}
+		}
+	}
+	v.move(0)
+}
+
+// number of table rows that fit in the view
+func (v *EventView) pageSize() int {
+	n := v.Height - (v.padding[1] * 2) - 2 // reserve header and footer lines
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+func (v *EventView) Up()     { v.move(-1) }
+func (v *EventView) Down()   { v.move(1) }
+func (v *EventView) PgUp()   { v.move(-v.pageSize()) }
+func (v *EventView) PgDown() { v.move(v.pageSize()) }
+
+func (v *EventView) move(n int) {
+	v.cursorPos += n
+	if v.cursorPos >= len(v.events) {
+		v.cursorPos = len(v.events) - 1
+	}
+	if v.cursorPos < 0 {
+		v.cursorPos = 0
+	}
+	ui.Render(v)
+}
+
+// format a table row from column values
+func (v *EventView) row(vals []string) string {
+	var sb strings.Builder
+	for i, col := range eventColumns {
+		if col.Width == 0 {
+			sb.WriteString(vals[i])
+			continue
+		}
+		s := vals[i]
+		if len(s) >= col.Width {
+			s = s[:col.Width-1]
+		}
+		sb.WriteString(fmt.Sprintf("%-*s", col.Width, s))
+	}
+	return sb.String()
+}
+
+func (v *EventView) Buffer() ui.Buffer {
+	buf := v.Block.Buffer()
+
+	page := v.pageSize()
+	if v.cursorPos < v.offset {
+		v.offset = v.cursorPos
+	}
+	if v.cursorPos >= v.offset+page {
+		v.offset = v.cursorPos - page + 1
+	}
+
+	maxWidth := v.Width - (v.padding[0] * 2)
+	x := v.X + v.padding[0]
+	y := v.Y + v.padding[1]
+	printLine := func(s string, y int, fg, bg ui.Attribute) {
+		for i, ch := range []rune(s) {
+			if i >= maxWidth {
+				break
+			}
+			buf.Set(x+i, y, ui.Cell{Ch: ch, Fg: fg, Bg: bg})
+		}
+	}
+
+	titles := make([]string, len(eventColumns))
+	for i, col := range eventColumns {
+		titles[i] = col.Title
+	}
+	printLine(v.row(titles), y, ui.ThemeAttr("header.fg")|ui.AttrBold, v.TextBgColor)
+	y++
+
+	for n := v.offset; n < len(v.events) && n < v.offset+page; n++ {
+		e := v.events[n]
+		name := e.Name
+		if name == "" {
+			name = e.ID
+		}
+		line := v.row([]string{
+			e.Time.Local().Format("15:04:05"),
+			e.Type,
+			name,
+			e.Action,
+			e.AttributeString(),
+		})
+		if n == v.cursorPos {
+			printLine(line, y, ui.ThemeAttr("par.text.hi"), v.TextFgColor)
+		} else {
+			printLine(line, y, v.TextFgColor, v.TextBgColor)
+		}
+		y++
+	}
+
+	filters := "all types"
+	if typ := eventTypes[v.typeIdx]; typ != "" {
+		filters = typ + " events"
+	}
+	if v.container != "" {
+		filters += " of one container"
+	}
+	if !v.probes {
+		filters += ", no health probes"
+	}
+	footer := fmt.Sprintf("%d/%d events (%s)  ↑↓ move  %s", len(v.events), len(v.all), filters, keyHints(
+		"events.type", "type", "events.container", "container", "events.probes", "health probes", "events.open", "go to container", "exit", "back"))
+	if v.message != "" {
+		footer = v.message + "  |  " + footer
+	}
+	printLine(footer, v.Y+v.Height-2, ui.ThemeAttr("menu.label.fg"), v.TextBgColor)
+
+	return buf
+}
@@ -0,0 +1,122 @@
+package widgets
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/Betzalel75/ctop/cwidgets"
+	"github.com/Betzalel75/ctop/models"
+)
+
+var changeMarks = map[string]string{
+	models.FileAdded:   "A",
+	models.FileChanged: "C",
+	models.FileDeleted: "D",
+}
+
+// fileTree builds a tree of absolute paths below a root directory
+type fileTree struct {
+	root  *treeNode
+	nodes map[string]*treeNode
+}
+
+func newFileTree(root string) *fileTree {
+	n := &treeNode{Path: root, Depth: -1, isBranch: true}
+	return &fileTree{root: n, nodes: map[string]*treeNode{root: n}}
+}
+
+// node returns the tree node for p, creating missing parent directories
+func (t *fileTree) node(p string) *treeNode {
+	p = path.Clean(p)
+	if n, ok := t.nodes[p]; ok {
+		return n
+	}
+	parent := t.node(path.Dir(p))
+	n := &treeNode{
+		Key:      path.Base(p),
+		Path:     p,
+		Depth:    parent.Depth + 1,
+		parent:   parent,
+		isBranch: true, // until proven to be a file
+	}
+	n.label = n.Key + "/"
+	parent.Children = append(parent.Children, n)
+	t.nodes[p] = n
+	return n
+}
+
+// sort directories first, then by name
+func (t *fileTree) sort(n *treeNode) {
+	sort.Slice(n.Children, func(i, j int) bool {
+		a, b := n.Children[i], n.Children[j]
+		if a.isBranch != b.isBranch {
+			return a.isBranch
+		}
+		return a.Key < b.Key
+	})
+	for _, c := range n.Children {
+		t.sort(c)
+	}
+}
+
+// NewChangesView creates a tree of container filesystem changes, as
+// reported by `docker diff`
+func NewChangesView(changes []models.FileChange) *TreeView {
+	t := newFileTree("/")
+	for _, ch := range changes {
+		if ch.Path == "/" {
+			continue
+		}
+		n := t.node(ch.Path)
+		n.Value = ch.Kind
+		n.label = fmt.Sprintf("[%s] %s", changeMarks[ch.Kind], n.Key)
+	}
+	// directories reported as changes have no further children
+	for _, n := range t.nodes {
+		if n.Value != nil && len(n.Children) == 0 {
+			n.isBranch = false
+		}
+	}
+	t.sort(t.root)
+
+	v := newTreeView(t.root)
+	v.Footer = keyHints("exit", "back")
+	return v
+}
+
+// NewFilesView creates a tree of a container filesystem listing below root
+func NewFilesView(root string, files []models.FileEntry) *TreeView {
+	t := newFileTree(root)
+	for _, f := range files {
+		if path.Clean(f.Path) == root {
+			continue
+		}
+		n := t.node(f.Path)
+		n.Value = f.Mode.String()
+		if f.IsDir {
+			n.label = fmt.Sprintf("%s  %8s  %s/", f.Mode, "", n.Key)
+			n.Collapsed = true
+			continue
+		}
+		n.isBranch = false
+		name := n.Key
+		if f.LinkTarget != "" {
+			name = fmt.Sprintf("%s -> %s", name, f.LinkTarget)
+		}
+		n.label = fmt.Sprintf("%s  %8s  %s", f.Mode, cwidgets.ByteFormat64Short(f.Size), name)
+	}
+	t.sort(t.root)
+
+	v := newTreeView(t.root)
+	v.Footer = keyHints("tree.goto", "go to path", "tree.pull", "pull to local path", "exit", "back")
+	return v
+}
+
+// SelectedFile returns the path of the file or directory under the cursor
+func (v *TreeView) SelectedFile() string {
+	if n := v.Selected(); n != nil {
+		return n.Path
+	}
+	return ""
+}
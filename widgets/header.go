@@ -2,6 +2,7 @@ package widgets
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	ui "github.com/gizak/termui"
@@ -12,6 +13,10 @@ type CTopHeader struct {
 	Count  *ui.Par
 	Filter *ui.Par
 	bg     *ui.Par
+	Host   *HostPanel
+	filter string
+	views  string
+	host   bool // host panel shown
 }
 
 func NewCTopHeader() *CTopHeader {
@@ -20,6 +25,7 @@ func NewCTopHeader() *CTopHeader {
 		Count:  headerPar(24, "-"),
 		Filter: headerPar(40, ""),
 		bg:     headerBg(),
+		Host:   NewHostPanel(),
 	}
 }
 
@@ -30,17 +36,39 @@ func (c *CTopHeader) Buffer() ui.Buffer {
 	buf.Merge(c.Time.Buffer())
 	buf.Merge(c.Count.Buffer())
 	buf.Merge(c.Filter.Buffer())
+	if c.host {
+		c.Host.Y = c.bg.Y + c.bg.Height
+		buf.Merge(c.Host.Buffer())
+	}
 	return buf
 }
 
 func (c *CTopHeader) Align() {
 	c.bg.SetWidth(ui.TermWidth() - 1)
+	c.Filter.SetWidth(max(ui.TermWidth()-c.Filter.X-1, 20))
+	c.Host.Width = ui.TermWidth() - 1
 }
 
 func (c *CTopHeader) Height() int {
+	if c.host {
+		return c.bg.Height + c.Host.Height()
+	}
 	return c.bg.Height
 }
 
+// ShowHost shows or hides the host panel below the header line
+func (c *CTopHeader) ShowHost(show bool) { c.host = show }
+
+// Recolor applies the current theme, keeping header texts
+func (c *CTopHeader) Recolor() {
+	for _, p := range []*ui.Par{c.Time, c.Count, c.Filter} {
+		p.Bg = ui.ThemeAttr("header.bg")
+		p.TextFgColor = ui.ThemeAttr("header.fg")
+		p.TextBgColor = ui.ThemeAttr("header.bg")
+	}
+	c.bg.Bg = ui.ThemeAttr("header.bg")
+}
+
 
 func headerBg() *ui.Par {
 	bg := ui.NewPar("")
@@ -56,11 +84,33 @@ func (c *CTopHeader) SetCount(val int) {
 }
 
 func (c *CTopHeader) SetFilter(val string) {
-	if val == "" {
-		c.Filter.Text = ""
-	} else {
-		c.Filter.Text = fmt.Sprintf("filter: %s", val)
+	c.filter = val
+	c.setFilterText()
+}
+
+// SetViews sets the names of saved views, numbered from 1 and the
+// active one bracketed; active is -1 if none
+func (c *CTopHeader) SetViews(names []string, active int) {
+	a := make([]string, len(names))
+	for i, name := range names {
+		a[i] = fmt.Sprintf("%d:%s", i+1, name)
+		if i == active {
+			a[i] = "[" + a[i] + "]"
+		}
+	}
+	c.views = strings.Join(a, " ")
+	c.setFilterText()
+}
+
+func (c *CTopHeader) setFilterText() {
+	var a []string
+	if c.filter != "" {
+		a = append(a, fmt.Sprintf("filter: %s", c.filter))
+	}
+	if c.views != "" {
+		a = append(a, "views: "+c.views)
 	}
+	c.Filter.Text = strings.Join(a, "  ")
 }
 // Header
 func timeStr() string {
@@ -0,0 +1,133 @@
+package widgets
+
+import (
+	"fmt"
+
+	"github.com/Betzalel75/ctop/cwidgets"
+	"github.com/Betzalel75/ctop/models"
+	ui "github.com/gizak/termui"
+)
+
+const hostPanelHeight = 3
+
+// HostPanel shows system-wide usage of the host below the header line,
+// with the share of it used by containers
+type HostPanel struct {
+	X, Y   int
+	Width  int
+	host   *models.Host
+	daemon *models.Daemon
+	cpu    int   // CPU used by containers, in percent of the host
+	mem    int64 // memory used by containers
+}
+
+func NewHostPanel() *HostPanel { return &HostPanel{X: 1} }
+
+func (h *HostPanel) Height() int { return hostPanelHeight }
+
+// SetHost sets host usage, nil if unavailable
+func (h *HostPanel) SetHost(host *models.Host) { h.host = host }
+
+// SetDaemon sets the runtime daemon info, nil if unavailable
+func (h *HostPanel) SetDaemon(d *models.Daemon) { h.daemon = d }
+
+// SetContainers sets the CPU percent and memory used by all containers
+func (h *HostPanel) SetContainers(cpu int, mem int64) {
+	h.cpu, h.mem = cpu, mem
+}
+
+// a run of text of a single color
+type hostSeg struct {
+	text string
+	fg   ui.Attribute
+}
+
+func (h *HostPanel) Buffer() ui.Buffer {
+	buf := ui.NewBuffer()
+	for n, line := range h.lines() {
+		x := h.X + 1
+		for _, seg := range line {
+			for _, ch := range seg.text {
+				if x >= h.X+h.Width {
+					break
+				}
+				buf.Set(x, h.Y+n, ui.Cell{Ch: ch, Fg: seg.fg, Bg: ui.ThemeAttr("par.text.bg")})
+				x++
+			}
+		}
+	}
+	return buf
+}
+
+func (h *HostPanel) lines() [][]hostSeg {
+	fg := ui.ThemeAttr("par.text.fg")
+	label := func(s string) hostSeg { return hostSeg{s, ui.ThemeAttr("label.fg")} }
+	text := func(f string, a ...any) hostSeg { return hostSeg{fmt.Sprintf(f, a...), fg} }
+	size := cwidgets.ByteFormat64Short
+
+	lines := make([][]hostSeg, hostPanelHeight)
+	host, d := h.host, h.daemon
+	switch {
+	case host != nil:
+		lines[0] = []hostSeg{
+			label("cpu "), percentSeg(host.CPUUtil), text(" of %d cpus  ", host.NCpus),
+			label("mem "), text("%s / %s ", size(host.MemUsed), size(host.MemTotal)), percentSeg(usagePercent(host.MemUsed, host.MemTotal)),
+			label("  swap "), text("%s / %s ", size(host.SwapUsed), size(host.SwapTotal)), percentSeg(usagePercent(host.SwapUsed, host.SwapTotal)),
+			label("  load "), text("%.2f %.2f %.2f", host.Load[0], host.Load[1], host.Load[2]),
+		}
+		if host.DiskPath != "" {
+			lines[1] = []hostSeg{
+				label("disk "), text("%s %s / %s ", host.DiskPath, size(host.DiskUsed), size(host.DiskTotal)),
+				percentSeg(usagePercent(host.DiskUsed, host.DiskTotal)), text("  "),
+			}
+		}
+	case d != nil:
+		// usage of a remote host is unknown, only its totals
+		lines[0] = []hostSeg{
+			label("host "), text("%d cpus, %s memory  ", d.NCpus, size(d.MemTotal)),
+			text("usage not available, the daemon is not on this machine"),
+		}
+	default:
+		lines[0] = []hostSeg{label("host "), text("stats unavailable")}
+	}
+
+	// totals of the daemon host, which container usage is relative to
+	var memTotal int64
+	if d != nil {
+		memTotal = d.MemTotal
+		lines[1] = append(lines[1],
+			label("daemon "), text("%s %s, %s, %s, kernel %s  ", d.Name, d.Version, d.Driver, d.OS, d.Kernel),
+			label("containers "), text("%d (%d running)  ", d.Containers, d.Running),
+			label("images "), text("%d", d.Images),
+		)
+	} else if host != nil {
+		memTotal = host.MemTotal
+	}
+	if memTotal > 0 {
+		lines[2] = []hostSeg{
+			label("containers use cpu "), percentSeg(h.cpu),
+			label("  mem "), text("%s ", size(h.mem)), percentSeg(usagePercent(h.mem, memTotal)), text(" of host"),
+		}
+	}
+	return lines
+}
+
+// percent value colored by level
+func percentSeg(n int) hostSeg {
+	fg := ui.ThemeAttr("par.text.fg")
+	switch {
+	case n >= 90:
+		fg = ui.ThemeAttr("status.danger")
+	case n >= 75:
+		fg = ui.ThemeAttr("status.warn")
+	}
+	return hostSeg{fmt.Sprintf("%d%%", n), fg}
+}
+
+// usagePercent is the share of total used, 0 if total is unknown
+func usagePercent(used, total int64) int {
+	if total <= 0 {
+		return 0
+	}
+	return int(used * 100 / total)
+}
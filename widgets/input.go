@@ -8,6 +8,7 @@ import (
 
 var (
 	input_chars = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ-_."
+	path_chars  = input_chars + "/~+@:,="
 )
 
 type Padding [2]int // x,y padding
@@ -17,6 +18,7 @@ type Input struct {
 	Label       string
 	Data        string
 	MaxLen      int
+	Chars       string // allowed input characters
 	TextFgColor ui.Attribute
 	TextBgColor ui.Attribute
 	stream      chan string // stream text as it changes
@@ -28,6 +30,7 @@ func NewInput() *Input {
 		Block:       *ui.NewBlock(),
 		Label:       "input",
 		MaxLen:      20,
+		Chars:       input_chars,
 		TextFgColor: ui.ThemeAttr("menu.text.fg"),
 		TextBgColor: ui.ThemeAttr("menu.text.bg"),
 		padding:     Padding{4, 2},
@@ -38,6 +41,15 @@ func NewInput() *Input {
 	return i
 }
 
+// NewPathInput creates an input accepting file paths
+func NewPathInput() *Input {
+	i := NewInput()
+	i.Chars = path_chars
+	i.MaxLen = 60
+	i.calcSize()
+	return i
+}
+
 func (i *Input) calcSize() {
 	i.Height = 3 // minimum height
 	i.Width = i.MaxLen + (i.padding[0] * 2)
@@ -77,7 +89,7 @@ func (i *Input) KeyPress(e ui.Event) {
 	if len(i.Data) >= i.MaxLen {
 		return
 	}
-	if strings.Contains(input_chars, ch) {
+	if strings.Contains(i.Chars, ch) {
 		i.Data += ch
 		i.stream <- i.Data
 		ui.Render(i)
@@ -0,0 +1,69 @@
+package widgets
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// newTree builds a tree from a generic JSON document, as produced
+// by json.Unmarshal into an interface value
+func newTree(key, path string, v any, depth int, parent *treeNode) *treeNode {
+	n := &treeNode{Key: key, Path: path, Depth: depth, parent: parent}
+
+	switch val := v.(type) {
+	case map[string]any:
+		n.isBranch = true
+		n.brackets = [2]string{"{", "}"}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			n.Children = append(n.Children, newTree(k, keyPath(path, k), val[k], depth+1, n))
+		}
+	case []any:
+		n.isBranch = true
+		n.brackets = [2]string{"[", "]"}
+		for i, item := range val {
+			n.Children = append(n.Children, newTree(fmt.Sprintf("[%d]", i), fmt.Sprintf("%s[%d]", path, i), item, depth+1, n))
+		}
+	default:
+		n.Value = val
+	}
+
+	// nested documents start collapsed
+	n.Collapsed = n.isBranch && depth >= 0
+	return n
+}
+
+// keyPath appends an object key to a jq path, quoted unless it is a
+// plain identifier, e.g. .Labels["com.docker.compose.project"]
+func keyPath(path, key string) string {
+	if identRe.MatchString(key) {
+		return path + "." + key
+	}
+	b, _ := json.Marshal(key)
+	return path + "[" + string(b) + "]"
+}
+
+var identRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// NewInspectView creates a view for a given document; the document is
+// normalized through JSON so that structs may be passed as well
+func NewInspectView(doc any) (*TreeView, error) {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return nil, err
+	}
+
+	v := newTreeView(newTree("", "", generic, -1, nil))
+	v.Footer = keyHints("tree.copy", "copy path+value", "exit", "back")
+	return v, nil
+}
@@ -0,0 +1,61 @@
+package widgets
+
+import "testing"
+
+var testDoc = map[string]any{
+	"Id": "abc",
+	"State": map[string]any{
+		"Status": "running",
+		"Health": map[string]any{"Status": "healthy"},
+	},
+	"Mounts": []any{
+		map[string]any{"Source": "/data"},
+	},
+}
+
+func TestTreePaths(t *testing.T) {
+	root := newTree("", "", testDoc, -1, nil)
+
+	mounts := root.Children[1]
+	if mounts.Path != ".Mounts" {
+		t.Errorf("expected: .Mounts, got: %s", mounts.Path)
+	}
+
+	src := mounts.Children[0].Children[0]
+	if src.Path != ".Mounts[0].Source" {
+		t.Errorf("expected: .Mounts[0].Source, got: %s", src.Path)
+	}
+	if src.JSON() != `"/data"` {
+		t.Errorf("expected: \"/data\", got: %s", src.JSON())
+	}
+}
+
+func TestTreeSearchExpands(t *testing.T) {
+	v := &TreeView{root: newTree("", "", testDoc, -1, nil)}
+	v.rebuild()
+
+	if len(v.visible) != 3 {
+		t.Errorf("expected: 3 visible nodes, got: %d", len(v.visible))
+	}
+
+	v.query = "healthy"
+	if !v.Next() {
+		t.Fatalf("expected match for query")
+	}
+	path, val := v.SelectedPath()
+	if path != ".State.Health.Status" || val != `"healthy"` {
+		t.Errorf("expected: .State.Health.Status, got: %s = %s", path, val)
+	}
+}
+
+func TestTreeQuotedPaths(t *testing.T) {
+	doc := map[string]any{
+		"Labels": map[string]any{"com.docker.compose.project": "app"},
+	}
+	root := newTree("", "", doc, -1, nil)
+
+	label := root.Children[0].Children[0]
+	if label.Path != `.Labels["com.docker.compose.project"]` {
+		t.Errorf(`expected: .Labels["com.docker.compose.project"], got: %s`, label.Path)
+	}
+}
@@ -105,7 +105,7 @@ func (m *Menu) Buffer() ui.Buffer {
 		for _, ch := range item.Text() {
 			// invert bg/fg colors on currently selected row
 			if m.Selectable && n == m.cursorPos {
-				cell = ui.Cell{Ch: ch, Fg: ui.ColorBlack, Bg: m.TextFgColor}
+				cell = ui.Cell{Ch: ch, Fg: ui.ThemeAttr("par.text.hi"), Bg: m.TextFgColor}
 			} else {
 				cell = ui.Cell{Ch: ch, Fg: m.TextFgColor, Bg: m.TextBgColor}
 			}
@@ -121,6 +121,33 @@ func (m *Menu) Buffer() ui.Buffer {
 	return buf
 }
 
+// ItemAt returns the index of the item displayed at screen position x, y
+func (m *Menu) ItemAt(x, y int) (int, bool) {
+	if x < m.X || x >= m.X+m.Width {
+		return 0, false
+	}
+	n := y - m.Y - m.padding[1]
+	if m.SubText != "" {
+		n -= 2
+	}
+	if n < 0 || n >= len(m.items) {
+		return 0, false
+	}
+	return n, true
+}
+
+// Click moves the cursor to the item at screen position x, y, returning
+// false if there is none
+func (m *Menu) Click(x, y int) bool {
+	n, ok := m.ItemAt(x, y)
+	if !ok || !m.Selectable {
+		return false
+	}
+	m.cursorPos = n
+	ui.Render(m)
+	return true
+}
+
 func (m *Menu) Up() {
 	if m.cursorPos > 0 {
 		m.cursorPos--
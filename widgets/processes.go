@@ -0,0 +1,212 @@
+package widgets
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Betzalel75/ctop/cwidgets"
+	"github.com/Betzalel75/ctop/models"
+	ui "github.com/gizak/termui"
+	"github.com/mattn/go-runewidth"
+)
+
+// process table columns, in display order
+var processColumns = []struct {
+	Title string
+	Width int // 0 for remaining width
+	Less  func(a, b models.Process) bool
+}{
+	{"PID", 8, func(a, b models.Process) bool { return a.PID < b.PID }},
+	{"USER", 10, func(a, b models.Process) bool { return a.User < b.User }},
+	{"%CPU", 7, func(a, b models.Process) bool { return a.CPU < b.CPU }},
+	{"%MEM", 7, func(a, b models.Process) bool { return a.Mem < b.Mem }},
+	{"RSS", 9, func(a, b models.Process) bool { return a.RSS < b.RSS }},
+	{"COMMAND", 0, func(a, b models.Process) bool { return a.Command < b.Command }},
+}
+
+// ProcessView displays the processes of a container in a sortable table
+type ProcessView struct {
+	ui.Block
+	TextFgColor ui.Attribute
+	TextBgColor ui.Attribute
+	procs       []models.Process
+	sortField   int
+	reverse     bool
+	cursorPos   int
+	offset      int
+	message     string
+	padding     Padding
+}
+
+func NewProcessView() *ProcessView {
+	v := &ProcessView{
+		Block:       *ui.NewBlock(),
+		TextFgColor: ui.ThemeAttr("menu.text.fg"),
+		TextBgColor: ui.ThemeAttr("menu.text.bg"),
+		sortField:   2, // %CPU
+		reverse:     true,
+		padding:     Padding{2, 1},
+	}
+	v.BorderFg = ui.ThemeAttr("menu.border.fg")
+	v.BorderLabelFg = ui.ThemeAttr("menu.label.fg")
+	v.Resize()
+	return v
+}
+
+func (v *ProcessView) Resize() {
+	v.Height = ui.TermHeight()
+	v.Width = ui.TermWidth()
+}
+
+// SetProcesses replaces the displayed processes, keeping the cursor on
+// the selected process if it is still running
+func (v *ProcessView) SetProcesses(procs []models.Process) {
+	selected, ok := v.Selected()
+	v.procs = procs
+	v.sort()
+	if ok {
+		for i, p := range v.procs {
+			if p.PID == selected.PID {
+				v.cursorPos = i
+				break
+			}
+		}
+	}
+	v.move(0)
+}
+
+// SetMessage sets a message shown in the footer of the view
+func (v *ProcessView) SetMessage(s string) { v.message = s }
+
+// Selected returns the process under the cursor, if any
+func (v *ProcessView) Selected() (models.Process, bool) {
+	if v.cursorPos >= 0 && v.cursorPos < len(v.procs) {
+		return v.procs[v.cursorPos], true
+	}
+	return models.Process{}, false
+}
+
+// NextSort sorts the table by the next column
+func (v *ProcessView) NextSort() {
+	v.sortField = (v.sortField + 1) % len(processColumns)
+	v.sort()
+	ui.Render(v)
+}
+
+// Reverse reverses the sort order
+func (v *ProcessView) Reverse() {
+	v.reverse = !v.reverse
+	v.sort()
+	ui.Render(v)
+}
+
+func (v *ProcessView) sort() {
+	less := processColumns[v.sortField].Less
+	sort.SliceStable(v.procs, func(i, j int) bool {
+		if v.reverse {
+			return less(v.procs[j], v.procs[i])
+		}
+		return less(v.procs[i], v.procs[j])
+	})
+}
+
+// number of table rows that fit in the view
+func (v *ProcessView) pageSize() int {
+	n := v.Height - (v.padding[1] * 2) - 2 // reserve header and footer lines
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+func (v *ProcessView) Up()     { v.move(-1) }
+func (v *ProcessView) Down()   { v.move(1) }
+func (v *ProcessView) PgUp()   { v.move(-v.pageSize()) }
+func (v *ProcessView) PgDown() { v.move(v.pageSize()) }
+
+func (v *ProcessView) move(n int) {
+	v.cursorPos += n
+	if v.cursorPos >= len(v.procs) {
+		v.cursorPos = len(v.procs) - 1
+	}
+	if v.cursorPos < 0 {
+		v.cursorPos = 0
+	}
+	ui.Render(v)
+}
+
+// format a table row from column values
+func (v *ProcessView) row(vals []string) string {
+	var sb strings.Builder
+	for i, col := range processColumns {
+		if col.Width == 0 {
+			sb.WriteString(vals[i])
+			continue
+		}
+		s := runewidth.Truncate(vals[i], col.Width-1, "")
+		sb.WriteString(runewidth.FillRight(s, col.Width))
+	}
+	return sb.String()
+}
+
+func (v *ProcessView) Buffer() ui.Buffer {
+	buf := v.Block.Buffer()
+
+	page := v.pageSize()
+	if v.cursorPos < v.offset {
+		v.offset = v.cursorPos
+	}
+	if v.cursorPos >= v.offset+page {
+		v.offset = v.cursorPos - page + 1
+	}
+
+	maxWidth := v.Width - (v.padding[0] * 2)
+	x := v.X + v.padding[0]
+	y := v.Y + v.padding[1]
+	printLine := func(s string, y int, fg, bg ui.Attribute) {
+		for i, ch := range []rune(s) {
+			if i >= maxWidth {
+				break
+			}
+			buf.Set(x+i, y, ui.Cell{Ch: ch, Fg: fg, Bg: bg})
+		}
+	}
+
+	titles := make([]string, len(processColumns))
+	for i, col := range processColumns {
+		titles[i] = col.Title
+		if i == v.sortField {
+			titles[i] += map[bool]string{false: "▲", true: "▼"}[v.reverse]
+		}
+	}
+	printLine(v.row(titles), y, ui.ThemeAttr("header.fg")|ui.AttrBold, v.TextBgColor)
+	y++
+
+	for n := v.offset; n < len(v.procs) && n < v.offset+page; n++ {
+		p := v.procs[n]
+		line := v.row([]string{
+			fmt.Sprintf("%d", p.PID),
+			p.User,
+			fmt.Sprintf("%.1f", p.CPU),
+			fmt.Sprintf("%.1f", p.Mem),
+			cwidgets.ByteFormat64Short(p.RSS),
+			p.Command,
+		})
+		if n == v.cursorPos {
+			printLine(line, y, ui.ThemeAttr("par.text.hi"), v.TextFgColor)
+		} else {
+			printLine(line, y, v.TextFgColor, v.TextBgColor)
+		}
+		y++
+	}
+
+	footer := fmt.Sprintf("%d processes  ↑↓ move  %s", len(v.procs), keyHints(
+		"processes.sort", "sort", "processes.reverse", "reverse", "processes.term", "SIGTERM", "processes.kill", "SIGKILL", "exit", "back"))
+	if v.message != "" {
+		footer = v.message + "  |  " + footer
+	}
+	printLine(footer, v.Y+v.Height-2, ui.ThemeAttr("menu.label.fg"), v.TextBgColor)
+
+	return buf
+}
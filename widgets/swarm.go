@@ -0,0 +1,405 @@
+package widgets
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Betzalel75/ctop/connector"
+	"github.com/Betzalel75/ctop/cwidgets/compact"
+	"github.com/Betzalel75/ctop/dtop/manager"
+	"github.com/Betzalel75/ctop/dtop/resource"
+	"github.com/Betzalel75/ctop/models"
+	ui "github.com/gizak/termui"
+	"github.com/hako/durafmt"
+)
+
+// PromptRequest asks for a value to be read from the user and passed
+// to Fn; cancelled prompts are not passed on
+type PromptRequest struct {
+	Label   string
+	Initial string
+	Chars   string // allowed input characters
+	Fn      func(string)
+}
+
+// SwarmServices lists the services of a swarm, or the tasks of one
+// service, in compact grids
+type SwarmServices struct {
+	*ui.Block
+	connectorSuper *connector.ConnectorSuper
+	dockerManager  *manager.DockerResourceManager
+	serviceGrid    *compact.CompactGrid
+	taskGrid       *compact.CompactGrid
+	services       []resource.Service
+	tasks          []resource.Task
+	rows           []*compact.CompactRow
+	service        *resource.Service // service whose tasks are listed, if any
+	selectedIndex  int
+	statusMsg      string
+	confirm        *ConfirmRequest
+	inspect        *InspectRequest
+	prompt         *PromptRequest
+	lock           sync.Mutex
+}
+
+func NewSwarmServices(cSuper *connector.ConnectorSuper) *SwarmServices {
+	block := ui.NewBlock()
+	block.BorderLabel = "Swarm"
+	block.Border = true
+
+	return &SwarmServices{
+		Block:          block,
+		connectorSuper: cSuper,
+		serviceGrid:    compact.NewCompactGridCols(compact.NewServiceCols),
+		taskGrid:       compact.NewCompactGridCols(compact.NewTaskCols),
+	}
+}
+
+func (s *SwarmServices) getDockerManager() (*manager.DockerResourceManager, error) {
+	if s.dockerManager == nil {
+		client, err := dockerClient(s.connectorSuper)
+		if err != nil {
+			return nil, err
+		}
+		s.dockerManager = manager.NewDockerResourceManager(client)
+	}
+	return s.dockerManager, nil
+}
+
+// PopConfirm returns and clears any action awaiting confirmation
+func (s *SwarmServices) PopConfirm() *ConfirmRequest {
+	req := s.confirm
+	s.confirm = nil
+	return req
+}
+
+// PopInspect returns and clears any pending inspect view request
+func (s *SwarmServices) PopInspect() *InspectRequest {
+	req := s.inspect
+	s.inspect = nil
+	return req
+}
+
+// PopPrompt returns and clears any pending prompt request
+func (s *SwarmServices) PopPrompt() *PromptRequest {
+	req := s.prompt
+	s.prompt = nil
+	return req
+}
+
+// Refresh reloads the listed services or tasks in the background
+func (s *SwarmServices) Refresh() {
+	if s.service != nil {
+		go s.loadTasks(*s.service)
+	} else {
+		go s.loadServices()
+	}
+}
+
+func (s *SwarmServices) loadServices() {
+	dm, err := s.getDockerManager()
+	if err != nil {
+		s.statusMsg = fmt.Sprintf("Error getting Docker client: %v", err)
+		return
+	}
+	services, err := dm.LoadServices()
+	if err != nil {
+		s.statusMsg = fmt.Sprintf("Error loading services: %v", err)
+		return
+	}
+
+	rows := make([]*compact.CompactRow, len(services))
+	for i, svc := range services {
+		rows[i] = compact.NewCompactRowCols(compact.NewServiceCols())
+		rows[i].SetMeta(serviceMeta(svc))
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.service != nil {
+		return // tasks were opened meanwhile
+	}
+	s.services, s.rows = services, rows
+	s.statusMsg = fmt.Sprintf("Loaded %d services", len(services))
+	s.clampSelection()
+}
+
+func (s *SwarmServices) loadTasks(svc resource.Service) {
+	dm, err := s.getDockerManager()
+	if err != nil {
+		s.statusMsg = fmt.Sprintf("Error getting Docker client: %v", err)
+		return
+	}
+	tasks, err := dm.LoadTasks(svc.Id)
+	if err != nil {
+		s.statusMsg = fmt.Sprintf("Error loading tasks: %v", err)
+		return
+	}
+
+	rows := make([]*compact.CompactRow, len(tasks))
+	for i, t := range tasks {
+		rows[i] = compact.NewCompactRowCols(compact.NewTaskCols())
+		rows[i].SetMeta(taskMeta(t))
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.service == nil || s.service.Id != svc.Id {
+		return
+	}
+	s.tasks, s.rows = tasks, rows
+	s.statusMsg = fmt.Sprintf("Loaded %d tasks of %s", len(tasks), svc.Name)
+	s.clampSelection()
+}
+
+func serviceMeta(svc resource.Service) models.Meta {
+	m := models.NewMeta(
+		"name", svc.Name,
+		"mode", svc.Mode,
+		"replicas", fmt.Sprintf("%d/%d", svc.Running, svc.Desired),
+		"image", svc.Image,
+		"update", svc.UpdateState,
+	)
+	// status indicator: stopped without running tasks, warning while
+	// replicas are missing
+	switch {
+	case svc.Running == 0 && svc.Desired > 0:
+		m["state"] = "exited"
+	case svc.Running < svc.Desired:
+		m["state"] = "running"
+		m["health"] = "starting"
+	default:
+		m["state"] = "running"
+	}
+	return m
+}
+
+func taskMeta(t resource.Task) models.Meta {
+	current := t.State
+	if !t.Since.IsZero() {
+		current += " " + durafmt.Parse(time.Since(t.Since)).LimitFirstN(1).String() + " ago"
+	}
+	m := models.NewMeta(
+		"name", t.Name,
+		"node", t.Node,
+		"desired", t.DesiredState,
+		"current", current,
+		"error", t.Error,
+	)
+	switch t.State {
+	case "running":
+		m["state"] = "running"
+	case "complete", "shutdown", "failed", "rejected", "orphaned", "remove":
+		m["state"] = "exited"
+	default: // new, pending, assigned, accepted, preparing, starting
+		m["state"] = "created"
+	}
+	return m
+}
+
+func (s *SwarmServices) clampSelection() {
+	if s.selectedIndex >= len(s.rows) {
+		s.selectedIndex = len(s.rows) - 1
+	}
+	if s.selectedIndex < 0 {
+		s.selectedIndex = 0
+	}
+}
+
+// selected service, in the services list
+func (s *SwarmServices) selectedService() (resource.Service, bool) {
+	if s.service != nil || s.selectedIndex >= len(s.services) {
+		return resource.Service{}, false
+	}
+	return s.services[s.selectedIndex], true
+}
+
+func (s *SwarmServices) HandleKey(key string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch key {
+	case "up":
+		if s.selectedIndex > 0 {
+			s.selectedIndex--
+		}
+		return true
+	case "down":
+		if s.selectedIndex < len(s.rows)-1 {
+			s.selectedIndex++
+		}
+		return true
+	case "pgup":
+		s.selectedIndex -= s.pageSize()
+		s.clampSelection()
+		return true
+	case "pgdown":
+		s.selectedIndex += s.pageSize()
+		s.clampSelection()
+		return true
+	case "refresh":
+		s.statusMsg = "Refreshing..."
+		s.Refresh()
+		return true
+	case "back":
+		if s.service == nil {
+			return false
+		}
+		s.service, s.rows, s.tasks = nil, nil, nil
+		s.selectedIndex = 0
+		s.statusMsg = "Loading services..."
+		s.Refresh()
+		return true
+	}
+
+	svc, ok := s.selectedService()
+	if !ok {
+		return false
+	}
+	switch key {
+	case "open":
+		s.service = &svc
+		s.rows = nil
+		s.selectedIndex = 0
+		s.statusMsg = fmt.Sprintf("Loading tasks of %s...", svc.Name)
+		s.Refresh()
+	case "inspect":
+		s.inspect = &InspectRequest{
+			Title: fmt.Sprintf("Inspect service [%s]", svc.Name),
+			Load: func() (any, error) {
+				dm, err := s.getDockerManager()
+				if err != nil {
+					return nil, err
+				}
+				return dm.Inspect("services", svc.Id)
+			},
+		}
+	case "scale":
+		if svc.Mode != "replicated" {
+			s.statusMsg = fmt.Sprintf("Cannot scale %s service %s", svc.Mode, svc.Name)
+			return true
+		}
+		s.prompt = &PromptRequest{
+			Label:   fmt.Sprintf("Replicas of %s", svc.Name),
+			Initial: strconv.FormatUint(svc.Desired, 10),
+			Chars:   "0123456789",
+			Fn: func(val string) {
+				n, err := strconv.ParseUint(val, 10, 64)
+				if err != nil {
+					s.statusMsg = fmt.Sprintf("Invalid replica count: %s", val)
+					return
+				}
+				s.serviceAction(fmt.Sprintf("Scaled %s to %d", svc.Name, n), func(dm *manager.DockerResourceManager) error {
+					return dm.ScaleService(svc.Id, n)
+				})
+			},
+		}
+	case "update":
+		s.confirm = &ConfirmRequest{
+			Text: fmt.Sprintf("Force update of %s?", svc.Name),
+			Fn: func() {
+				s.serviceAction(fmt.Sprintf("Updating %s", svc.Name), func(dm *manager.DockerResourceManager) error {
+					return dm.ForceUpdateService(svc.Id)
+				})
+			},
+		}
+	case "rollback":
+		s.confirm = &ConfirmRequest{
+			Text: fmt.Sprintf("Roll back %s to its previous spec?", svc.Name),
+			Fn: func() {
+				s.serviceAction(fmt.Sprintf("Rolling back %s", svc.Name), func(dm *manager.DockerResourceManager) error {
+					return dm.RollbackService(svc.Id)
+				})
+			},
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// serviceAction runs fn against the Docker manager, reporting done on
+// success, and reloads the services
+func (s *SwarmServices) serviceAction(done string, fn func(*manager.DockerResourceManager) error) {
+	dm, err := s.getDockerManager()
+	if err == nil {
+		err = fn(dm)
+	}
+	if err != nil {
+		s.statusMsg = fmt.Sprintf("Error: %v", err)
+		return
+	}
+	s.statusMsg = done
+	go s.loadServices()
+}
+
+// number of grid rows that fit in the widget
+func (s *SwarmServices) pageSize() int {
+	n := s.Height - 7 // borders, title, grid header and footer
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+func (s *SwarmServices) Buffer() ui.Buffer {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	buf := s.Block.Buffer()
+	x, y, width := s.X+1, s.Y+1, s.Width-2
+
+	grid := s.serviceGrid
+	title := fmt.Sprintf("Services (%d)", len(s.services))
+	help := "↑↓ navigate  " + keyHints("list.open", "tasks", "list.scale", "scale", "list.update", "force update",
+		"list.rollback", "rollback", "list.inspect", "inspect", "list.refresh", "refresh")
+	if s.service != nil {
+		grid = s.taskGrid
+		title = fmt.Sprintf("Tasks of %s (%d)", s.service.Name, len(s.tasks))
+		help = "↑↓ navigate  " + keyHints("list.refresh", "refresh", "exit", "back to services")
+	}
+	printText(buf, x, y, width, title, ui.ThemeAttr("header.fg"))
+
+	// keep the selected row in view
+	page := s.pageSize()
+	if s.selectedIndex < grid.Offset {
+		grid.Offset = s.selectedIndex
+	}
+	if s.selectedIndex >= grid.Offset+page {
+		grid.Offset = s.selectedIndex - page + 1
+	}
+	offset := grid.Offset
+
+	grid.Clear()
+	for i, row := range s.rows {
+		if i == s.selectedIndex {
+			row.Highlight()
+		} else {
+			row.UnHighlight()
+		}
+		if i >= offset && i < offset+page {
+			grid.AddRows(row)
+		}
+	}
+	grid.Offset = 0
+	grid.X, grid.Y = x, y+1
+	grid.SetWidth(width)
+	grid.Align()
+	buf.Merge(grid.Buffer())
+	grid.Offset = offset
+
+	printText(buf, x, s.Y+s.Height-3, width, help, ui.ThemeAttr("par.text.fg"))
+	printText(buf, x, s.Y+s.Height-2, width, s.statusMsg, ui.ThemeAttr("status.warn"))
+	return buf
+}
+
+func printText(buf ui.Buffer, x, y, width int, text string, fg ui.Attribute) {
+	for i, ch := range []rune(text) {
+		if i >= width {
+			break
+		}
+		buf.Set(x+i, y, ui.Cell{Ch: ch, Fg: fg, Bg: ui.ColorDefault})
+	}
+}
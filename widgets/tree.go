@@ -0,0 +1,345 @@
+package widgets
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Betzalel75/ctop/config"
+	ui "github.com/gizak/termui"
+)
+
+// node of a document or file tree
+type treeNode struct {
+	Key       string
+	Path      string // jq-style path from document root, e.g. .State.Health.Status
+	Value     any    // leaf value; nil for objects and arrays
+	Children  []*treeNode
+	Depth     int
+	Collapsed bool
+	parent    *treeNode
+	isBranch  bool
+	brackets  [2]string
+	label     string // display text, if different from key and value
+}
+
+// JSON returns the encoded value of a leaf node, or the full
+// subtree document for an object or array
+func (n *treeNode) JSON() string {
+	b, err := json.Marshal(n.raw())
+	if err != nil {
+		return fmt.Sprintf("%v", n.Value)
+	}
+	return string(b)
+}
+
+// rebuild the generic document represented by this node
+func (n *treeNode) raw() any {
+	if !n.isBranch {
+		return n.Value
+	}
+	if n.brackets[0] == "[" {
+		a := make([]any, len(n.Children))
+		for i, c := range n.Children {
+			a[i] = c.raw()
+		}
+		return a
+	}
+	m := make(map[string]any, len(n.Children))
+	for _, c := range n.Children {
+		m[c.Key] = c.raw()
+	}
+	return m
+}
+
+func (n *treeNode) text() string {
+	indent := strings.Repeat("  ", n.Depth)
+	if n.label != "" {
+		mark := " "
+		if n.isBranch && n.Collapsed {
+			mark = "▸"
+		} else if n.isBranch {
+			mark = "▾"
+		}
+		return fmt.Sprintf("%s%s %s", indent, mark, n.label)
+	}
+	if n.isBranch {
+		mark := "▾"
+		if n.Collapsed {
+			mark = "▸"
+		}
+		return fmt.Sprintf("%s%s %s %s%d%s", indent, mark, n.Key, n.brackets[0], len(n.Children), n.brackets[1])
+	}
+	return fmt.Sprintf("%s  %s: %s", indent, n.Key, n.JSON())
+}
+
+// TreeView renders a document as a navigable, collapsible tree
+type TreeView struct {
+	ui.Block
+	TextFgColor ui.Attribute
+	TextBgColor ui.Attribute
+	root        *treeNode
+	visible     []*treeNode // currently displayed nodes
+	cursorPos   int
+	offset      int
+	query       string
+	message     string
+	padding     Padding
+	Footer      string // key help shown at the bottom of the view
+}
+
+func newTreeView(root *treeNode) *TreeView {
+	v := &TreeView{
+		Block:       *ui.NewBlock(),
+		TextFgColor: ui.ThemeAttr("menu.text.fg"),
+		TextBgColor: ui.ThemeAttr("menu.text.bg"),
+		root:        root,
+		padding:     Padding{2, 1},
+	}
+	v.BorderFg = ui.ThemeAttr("menu.border.fg")
+	v.BorderLabelFg = ui.ThemeAttr("menu.label.fg")
+	v.rebuild()
+	v.Resize()
+	return v
+}
+
+// SetMessage sets a message shown in the footer of the view
+// in place of the selected path
+func (v *TreeView) SetMessage(s string) {
+	v.message = s
+}
+
+func (v *TreeView) Resize() {
+	v.Height = ui.TermHeight()
+	v.Width = ui.TermWidth()
+}
+
+// number of tree lines that fit in the view
+func (v *TreeView) pageSize() int {
+	n := v.Height - (v.padding[1] * 2) - 1 // reserve a line for the footer
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// rebuild list of visible nodes from current collapsed states
+func (v *TreeView) rebuild() {
+	v.visible = v.visible[:0]
+	var walk func(n *treeNode)
+	walk = func(n *treeNode) {
+		for _, c := range n.Children {
+			v.visible = append(v.visible, c)
+			if c.isBranch && !c.Collapsed {
+				walk(c)
+			}
+		}
+	}
+	walk(v.root)
+	if v.cursorPos >= len(v.visible) {
+		v.cursorPos = len(v.visible) - 1
+	}
+	if v.cursorPos < 0 {
+		v.cursorPos = 0
+	}
+}
+
+// Selected returns the node under the cursor, if any
+func (v *TreeView) Selected() *treeNode {
+	if v.cursorPos >= 0 && v.cursorPos < len(v.visible) {
+		return v.visible[v.cursorPos]
+	}
+	return nil
+}
+
+// SelectedPath returns the path and JSON value of the node under the cursor
+func (v *TreeView) SelectedPath() (path, value string) {
+	n := v.Selected()
+	if n == nil {
+		return "", ""
+	}
+	return n.Path, n.JSON()
+}
+
+func (v *TreeView) Up()     { v.move(-1) }
+func (v *TreeView) Down()   { v.move(1) }
+func (v *TreeView) PgUp()   { v.move(-v.pageSize()) }
+func (v *TreeView) PgDown() { v.move(v.pageSize()) }
+
+func (v *TreeView) move(n int) {
+	v.cursorPos += n
+	if v.cursorPos >= len(v.visible) {
+		v.cursorPos = len(v.visible) - 1
+	}
+	if v.cursorPos < 0 {
+		v.cursorPos = 0
+	}
+	ui.Render(v)
+}
+
+// Toggle collapses or expands the node under the cursor
+func (v *TreeView) Toggle() {
+	n := v.Selected()
+	if n == nil || !n.isBranch {
+		return
+	}
+	n.Collapsed = !n.Collapsed
+	v.rebuild()
+	ui.Render(v)
+}
+
+// Expand opens the node under the cursor, or moves to its first child if already open
+func (v *TreeView) Expand() {
+	n := v.Selected()
+	if n == nil || !n.isBranch {
+		return
+	}
+	if n.Collapsed {
+		n.Collapsed = false
+		v.rebuild()
+	} else if len(n.Children) > 0 {
+		v.cursorPos++
+	}
+	ui.Render(v)
+}
+
+// Collapse closes the node under the cursor, or moves to its parent
+func (v *TreeView) Collapse() {
+	n := v.Selected()
+	if n == nil {
+		return
+	}
+	if n.isBranch && !n.Collapsed {
+		n.Collapsed = true
+		v.rebuild()
+	} else if n.parent != nil && n.parent != v.root {
+		v.selectNode(n.parent)
+	}
+	ui.Render(v)
+}
+
+// Search sets the search query and moves to its first match
+func (v *TreeView) Search(q string) bool {
+	v.query = q
+	v.cursorPos = -1
+	found := v.Next()
+	if !found {
+		v.cursorPos = 0
+	}
+	return found
+}
+
+// Next moves the cursor to the next node matching the current search
+// query, expanding collapsed parents as needed
+func (v *TreeView) Next() bool {
+	if v.query == "" {
+		return false
+	}
+	q := strings.ToLower(v.query)
+
+	var all []*treeNode
+	var walk func(n *treeNode)
+	walk = func(n *treeNode) {
+		for _, c := range n.Children {
+			all = append(all, c)
+			walk(c)
+		}
+	}
+	walk(v.root)
+
+	// start search after current node, wrapping around
+	start := 0
+	if cur := v.Selected(); cur != nil {
+		for i, n := range all {
+			if n == cur {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	for i := 0; i < len(all); i++ {
+		n := all[(start+i)%len(all)]
+		match := strings.Contains(strings.ToLower(n.Key), q)
+		if !n.isBranch {
+			match = match || strings.Contains(strings.ToLower(n.JSON()), q)
+		}
+		if match {
+			v.selectNode(n)
+			return true
+		}
+	}
+	return false
+}
+
+// expand all parents of a node and move the cursor to it
+func (v *TreeView) selectNode(n *treeNode) {
+	for p := n.parent; p != nil; p = p.parent {
+		p.Collapsed = false
+	}
+	v.rebuild()
+	for i, vn := range v.visible {
+		if vn == n {
+			v.cursorPos = i
+			return
+		}
+	}
+}
+
+func (v *TreeView) Buffer() ui.Buffer {
+	var cell ui.Cell
+	buf := v.Block.Buffer()
+
+	// keep cursor within visible page
+	page := v.pageSize()
+	if v.cursorPos < v.offset {
+		v.offset = v.cursorPos
+	}
+	if v.cursorPos >= v.offset+page {
+		v.offset = v.cursorPos - page + 1
+	}
+
+	maxWidth := v.Width - (v.padding[0] * 2)
+	y := v.Y + v.padding[1]
+	for n := v.offset; n < len(v.visible) && n < v.offset+page; n++ {
+		x := v.X + v.padding[0]
+		for i, ch := range []rune(v.visible[n].text()) {
+			if i >= maxWidth {
+				break
+			}
+			if n == v.cursorPos {
+				cell = ui.Cell{Ch: ch, Fg: ui.ThemeAttr("par.text.hi"), Bg: v.TextFgColor}
+			} else {
+				cell = ui.Cell{Ch: ch, Fg: v.TextFgColor, Bg: v.TextBgColor}
+			}
+			buf.Set(x+i, y, cell)
+		}
+		y++
+	}
+
+	footer := "↑↓ move  " + keyHints("tree.toggle", "collapse/expand", "tree.search", "search", "tree.next", "next") + "  " + v.Footer
+	if v.message != "" {
+		footer = v.message + "  |  " + footer
+	} else if path, _ := v.SelectedPath(); path != "" {
+		footer = path + "  |  " + footer
+	}
+	x := v.X + v.padding[0]
+	for i, ch := range []rune(footer) {
+		if i >= maxWidth {
+			break
+		}
+		buf.Set(x+i, v.Y+v.Height-2, ui.Cell{Ch: ch, Fg: ui.ThemeAttr("menu.label.fg"), Bg: v.TextBgColor})
+	}
+
+	return buf
+}
+
+// keyHints formats action and label pairs for a footer, with the first
+// key bound to each action
+func keyHints(pairs ...string) string {
+	var a []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		a = append(a, config.KeyHint(pairs[i])+" "+pairs[i+1])
+	}
+	return strings.Join(a, "  ")
+}